@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/beego/beego/v2/client/orm"
 	"github.com/beego/beego/v2/server/web"
 )
 
@@ -60,12 +61,34 @@ func (c *AdminController) UpdateUserHandler() {
 		return
 	}
 
+	if updateUserRequest.Ban && !user.Ban {
+		adminID := c.Ctx.Input.GetData("id").(int)
+		if adminID == user.Id {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Ctx.Output.JSON(map[string]string{"error": "Admins cannot ban themselves"}, false, false)
+			return
+		}
+
+		_, err = models.CreateBan(user.Id, adminID, updateUserRequest.BanReason, updateUserRequest.BanEndsAt)
+		if err != nil {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "Failed to ban user"}, false, false)
+			return
+		}
+	} else if !updateUserRequest.Ban && user.Ban {
+		err = models.LiftActiveBansByUserID(user.Id)
+		if err != nil {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "Failed to lift ban"}, false, false)
+			return
+		}
+	}
+
 	if updateUserRequest.Role != "" {
 		user.Role = updateUserRequest.Role
 	}
-	user.Ban = updateUserRequest.Ban
 
-	err = models.UpdateUser(user)
+	err = models.UpdateUser(user, "Role")
 	if err != nil {
 		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
 		c.Ctx.Output.JSON(map[string]string{"error": "User update failed"}, false, false)
@@ -105,13 +128,11 @@ func (c *AdminController) GetUsersHandler() {
 		case "freelancer":
 			freelancerData, err := models.GetFreelancerDataByUserID(user.Id)
 			if err == nil && freelancerData != nil {
-				var skillList []types.Skill
-
-				for _, skill := range freelancerData.Skills {
-					skillList = append(skillList, types.Skill{
-						Id:   skill.Id,
-						Name: skill.Name,
-					})
+				skillList, err := freelancerSkillList(freelancerData.Id)
+				if err != nil {
+					c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+					c.Ctx.Output.JSON(map[string]string{"error": "Error fetching freelancer skills"}, false, false)
+					return
 				}
 
 				response.FreelancerData = &types.FreelancerData{
@@ -142,3 +163,182 @@ func (c *AdminController) GetUsersHandler() {
 	c.ServeJSON()
 
 }
+
+func (c *AdminController) GetDeletedUsersHandler() {
+
+	users, err := models.GetDeletedUsers()
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	var usersResponse []types.UserResponseForAdmins
+	for _, user := range users {
+		usersResponse = append(usersResponse, types.UserResponseForAdmins{
+			ID:        user.Id,
+			Email:     user.Email,
+			Role:      user.Role,
+			Name:      user.Name,
+			Surname:   user.Surname,
+			Ban:       user.Ban,
+			CreatedAt: user.CreatedAt,
+			DeletedAt: user.DeletedAt,
+		})
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = usersResponse
+	c.ServeJSON()
+
+}
+
+func (c *AdminController) RestoreUserHandler() {
+
+	userID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid user ID"}, false, false)
+		return
+	}
+
+	err = models.RestoreUserByID(userID)
+	if err != nil {
+		if err == orm.ErrNoRows {
+			c.Ctx.Output.SetStatus(http.StatusNotFound)
+			c.Ctx.Output.JSON(map[string]string{"error": "User not found"}, false, false)
+		} else if err.Error() == "user is not deleted" {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Ctx.Output.JSON(map[string]string{"error": "User is not deleted"}, false, false)
+		} else {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "User restore failed"}, false, false)
+		}
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": "User restored successfully"}
+	c.ServeJSON()
+
+}
+
+func (c *AdminController) GetStatsHandler() {
+
+	limit, err := c.GetInt("limit", 0)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid limit"}, false, false)
+		return
+	}
+
+	statsQuery, err := validators.StatsQueryValidator(c.GetString("interval"), c.GetString("from"), c.GetString("to"), limit)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	from, to, interval := statsQuery.From, statsQuery.To, statsQuery.Interval
+	response := types.AdminStatsResponse{
+		Interval: interval,
+		From:     from,
+		To:       to,
+	}
+
+	response.UsersByRole, err = models.GetUserCountsByRole()
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching user counts"}, false, false)
+		return
+	}
+
+	response.JobsByStatus, err = models.GetJobCountsByStatus()
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching job counts"}, false, false)
+		return
+	}
+
+	response.BannedUsers, err = models.GetBannedUserCount()
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching banned user count"}, false, false)
+		return
+	}
+
+	accepted, decided, err := models.GetApplicationOutcomes(from, to)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching acceptance rate"}, false, false)
+		return
+	}
+	if decided > 0 {
+		acceptanceRate := float64(accepted) / float64(decided)
+		response.AcceptanceRate = &acceptanceRate
+	}
+
+	medianHours, hires, err := models.GetMedianTimeToHire(from, to)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching time to hire"}, false, false)
+		return
+	}
+	if hires > 0 {
+		response.MedianTimeToHireHours = &medianHours
+	}
+
+	response.Registrations, err = models.GetRegistrationSeries(interval, from, to)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching registrations"}, false, false)
+		return
+	}
+
+	response.JobsPosted, err = models.GetJobSeries(interval, from, to)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching posted jobs"}, false, false)
+		return
+	}
+
+	response.ApplicationsSubmitted, err = models.GetCountSeries("applications", "created_at", true, interval, from, to)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching submitted applications"}, false, false)
+		return
+	}
+
+	response.Hires, err = models.GetCountSeries("applications", "accepted_at", true, interval, from, to)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching hires"}, false, false)
+		return
+	}
+
+	response.BansIssued, err = models.GetCountSeries("bans", "created_at", false, interval, from, to)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching issued bans"}, false, false)
+		return
+	}
+
+	response.TopSkillsDemanded, err = models.GetTopDemandedSkills(from, to, statsQuery.Limit)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching demanded skills"}, false, false)
+		return
+	}
+
+	response.TopSkillsOffered, err = models.GetTopOfferedSkills(statsQuery.Limit)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching offered skills"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = response
+	c.ServeJSON()
+
+}
@@ -1,16 +1,16 @@
 package controllers
 
 import (
+	"backend/jobstate"
 	"backend/models"
 	"backend/types"
 	"backend/validators"
-	"encoding/base64"
+	"errors"
+	"fmt"
 	"net/http"
-	"os"
-	"path/filepath"
 	"strconv"
-	"time"
 
+	"github.com/beego/beego/v2/client/orm"
 	"github.com/beego/beego/v2/server/web"
 )
 
@@ -44,34 +44,27 @@ func (c *ApplicationController) GetFreelancerApplications() {
 	var applicationList []types.Application
 	for _, application := range applications {
 
-		attachment, err := models.GetAttachmentByApplicationID(application.Id)
+		attachments, err := models.GetAttachmentsByApplicationID(application.Id)
 		if err != nil {
 			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
-			c.Ctx.Output.JSON(map[string]string{"error": "Error fetching attachment"}, false, false)
+			c.Ctx.Output.JSON(map[string]string{"error": "Error fetching attachments"}, false, false)
 			return
 		}
-
-		var attachmentInfo *types.Attachment
-		if attachment != nil {
-			attachmentInfo = &types.Attachment{
-				ID:            attachment.Id,
-				ApplicationID: attachment.Application.Id,
-				FileName:      attachment.FileName,
-				FilePath:      attachment.FilePath,
-				CreatedAt:     attachment.CreatedAt,
-			}
-		}
+		attachmentInfos := attachmentList(attachments)
 
 		applicationList = append(applicationList, types.Application{
-			ID:              application.Id,
-			UserID:          application.User.Id,
-			JobID:           application.Job.Id,
-			JobTitle:        application.Job.Title,
-			Description:     application.Description,
-			RejectionReason: application.RejectionReason,
-			Status:          application.Status,
-			CreatedAt:       application.CreatedAt,
-			Attachment:      attachmentInfo,
+			ID:               application.Id,
+			UserID:           application.User.Id,
+			JobID:            application.Job.Id,
+			JobTitle:         application.Job.Title,
+			Description:      application.Description,
+			RejectionReason:  application.RejectionReason,
+			Status:           application.Job.FreelancerStatus(application.Status),
+			CreatedAt:        application.CreatedAt,
+			Attachment:       firstAttachment(attachmentInfos),
+			Attachments:      attachmentInfos,
+			WithdrawalReason: application.WithdrawalReason,
+			WithdrawnAt:      application.WithdrawnAt,
 		})
 	}
 
@@ -111,32 +104,40 @@ func (c *ApplicationController) GetFreelancerApplication() {
 		return
 	}
 
-	attachment, err := models.GetAttachmentByApplicationID(application.Id)
+	attachments, err := models.GetAttachmentsByApplicationID(application.Id)
 	if err != nil {
 		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
-		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching attachment"}, false, false)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching attachments"}, false, false)
 		return
 	}
-	var attachmentInfo *types.Attachment
-	if attachment != nil {
-		attachmentInfo = &types.Attachment{
-			ID:            attachment.Id,
-			ApplicationID: attachment.Application.Id,
-			FileName:      attachment.FileName,
-			FilePath:      attachment.FilePath,
-			CreatedAt:     attachment.CreatedAt,
-		}
+	attachmentInfos := attachmentList(attachments)
+
+	var cancellationInfo *types.CancellationRequestInfo
+	cancellation, err := models.GetPendingCancellationRequest(application.Id)
+	if err != nil && err != orm.ErrNoRows {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching cancellation requests"}, false, false)
+		return
+	}
+	if cancellation != nil {
+		cancellation.Application = application
+		info := cancellationRequestInfo(cancellation)
+		cancellationInfo = &info
 	}
 
 	applicationInfo := types.Application{
-		ID:              application.Id,
-		UserID:          application.User.Id,
-		JobID:           application.Job.Id,
-		Description:     application.Description,
-		RejectionReason: application.RejectionReason,
-		Status:          application.Status,
-		CreatedAt:       application.CreatedAt,
-		Attachment:      attachmentInfo,
+		ID:                  application.Id,
+		UserID:              application.User.Id,
+		JobID:               application.Job.Id,
+		Description:         application.Description,
+		RejectionReason:     application.RejectionReason,
+		Status:              application.Job.FreelancerStatus(application.Status),
+		CreatedAt:           application.CreatedAt,
+		Attachment:          firstAttachment(attachmentInfos),
+		Attachments:         attachmentInfos,
+		WithdrawalReason:    application.WithdrawalReason,
+		WithdrawnAt:         application.WithdrawnAt,
+		CancellationRequest: cancellationInfo,
 	}
 
 	c.Ctx.Output.SetStatus(http.StatusOK)
@@ -145,9 +146,16 @@ func (c *ApplicationController) GetFreelancerApplication() {
 
 }
 
+// SubmitApplication takes the application as JSON with base64 files, or as multipart/form-data
 func (c *ApplicationController) SubmitApplication() {
 
-	submitApplicationRequest, err := validators.SubmitApplicationValidator(c.Ctx.Input.RequestBody)
+	var submitApplicationRequest *types.SubmitApplicationRequest
+	var err error
+	if c.Ctx.Input.IsUpload() {
+		submitApplicationRequest, err = validators.SubmitApplicationFormValidator(c.Ctx.Request.MultipartForm)
+	} else {
+		submitApplicationRequest, err = validators.SubmitApplicationValidator(c.Ctx.Input.RequestBody)
+	}
 	if err != nil {
 		c.Ctx.Output.SetStatus(http.StatusBadRequest)
 		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
@@ -180,54 +188,57 @@ func (c *ApplicationController) SubmitApplication() {
 		return
 	}
 
-	existingApplication, err := models.GetApplicationByUserAndJob(userID, submitApplicationRequest.JobID)
-
-	if err != nil {
-		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
-		c.Ctx.Output.JSON(map[string]string{"error": "Error checking existing application"}, false, false)
+	if !models.CanViewJob(job, user) {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Job not found"}, false, false)
 		return
 	}
 
-	if existingApplication != nil {
+	if models.ApplicationExists(userID, submitApplicationRequest.JobID) {
 		c.Ctx.Output.SetStatus(http.StatusBadRequest)
 		c.Ctx.Output.JSON(map[string]string{"error": "You have already applied"}, false, false)
 		return
 	}
 
-	applicationId, err := models.CreateApplication(user, job, submitApplicationRequest.Description)
+	questions, err := screeningQuestionList(job.Id)
 	if err != nil {
 		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
-		c.Ctx.Output.JSON(map[string]string{"error": "Failed to save application"}, false, false)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching screening questions"}, false, false)
+		return
+	}
+	if err := validators.ScreeningAnswersValidator(questions, submitApplicationRequest.Answers); err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
 		return
 	}
 
-	if submitApplicationRequest.FileName != "" && submitApplicationRequest.FileBase64 != "" {
-		decodedFile, err := base64.StdEncoding.DecodeString(submitApplicationRequest.FileBase64)
-		if err != nil {
-			c.Ctx.Output.SetStatus(http.StatusBadRequest)
-			c.Ctx.Output.JSON(map[string]string{"error": "Invalid base64 encoding"}, false, false)
-			return
-		}
-
-		uniqueFileName := time.Now().Format("20060102_150405") + "_" + submitApplicationRequest.FileName
-		uploadDir := "uploads"
-		os.MkdirAll(uploadDir, os.ModePerm)
-
-		filePath := filepath.Join(uploadDir, uniqueFileName)
-		err = os.WriteFile(filePath, decodedFile, 0644)
-		if err != nil {
-			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
-			c.Ctx.Output.JSON(map[string]string{"error": "Failed to save attachment"}, false, false)
-			return
-		}
+	files, err := attachmentFiles(userID, submitApplicationRequest.Files, nil, attachmentLimits())
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
 
-		err = models.CreateAttachment(applicationId, submitApplicationRequest.FileName, filePath)
+	attachments, err := storeAttachmentFiles(files)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Failed to save attachment"}, false, false)
+		return
+	}
 
-		if err != nil {
-			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
-			c.Ctx.Output.JSON(map[string]string{"error": "Failed to save attachment link in the database"}, false, false)
-			return
-		}
+	_, err = models.CreateApplication(user, job, submitApplicationRequest.Description, submitApplicationRequest.Answers, attachments)
+	if err != nil {
+		deleteStoredFiles(attachments)
+	}
+	if err != nil && err.Error() == "not enough credits" {
+		c.Ctx.Output.SetStatus(http.StatusPaymentRequired)
+		c.Ctx.Output.JSON(map[string]string{"error": fmt.Sprintf("Not enough credits, applying to this job costs %d credits", models.ApplicationCreditCost(job))}, false, false)
+		return
+	}
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Failed to save application"}, false, false)
+		return
 	}
 
 	c.Ctx.Output.SetStatus(http.StatusOK)
@@ -235,8 +246,15 @@ func (c *ApplicationController) SubmitApplication() {
 	c.ServeJSON()
 }
 
+// UpdateApplication takes the update as JSON with a base64 file, or as multipart/form-data
 func (c *ApplicationController) UpdateApplication() {
-	updateApplicationRequest, err := validators.UpdateApplicationValidator(c.Ctx.Input.RequestBody)
+	var updateApplicationRequest *types.UpdateApplicationRequest
+	var err error
+	if c.Ctx.Input.IsUpload() {
+		updateApplicationRequest, err = validators.UpdateApplicationFormValidator(c.Ctx.Request.MultipartForm)
+	} else {
+		updateApplicationRequest, err = validators.UpdateApplicationValidator(c.Ctx.Input.RequestBody)
+	}
 	if err != nil {
 		c.Ctx.Output.SetStatus(http.StatusBadRequest)
 		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
@@ -277,12 +295,23 @@ func (c *ApplicationController) UpdateApplication() {
 		return
 	}
 
-	if application.Status != "pending" {
+	if !application.InReview() {
 		c.Ctx.Output.SetStatus(http.StatusBadRequest)
-		c.Ctx.Output.JSON(map[string]string{"error": "Only pending applications can be updated"}, false, false)
+		c.Ctx.Output.JSON(map[string]string{"error": "Only applications under review can be updated"}, false, false)
 		return
 	}
 
+	// older clients send a single file that replaces the attachments
+	var files []uploadedFile
+	if updateApplicationRequest.File != nil {
+		files, err = attachmentFiles(userID, []types.AttachmentUpload{*updateApplicationRequest.File}, nil, attachmentLimits())
+		if err != nil {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+			return
+		}
+	}
+
 	if updateApplicationRequest.Description != "" {
 		application.Description = updateApplicationRequest.Description
 		err = models.UpdateApplication(application)
@@ -293,51 +322,23 @@ func (c *ApplicationController) UpdateApplication() {
 		}
 	}
 
-	if updateApplicationRequest.FileName != "" && updateApplicationRequest.FileBase64 != "" {
-		attachment, err := models.GetAttachmentByApplicationID(application.Id)
-		if err == nil && attachment != nil {
-
-			err := os.Remove(attachment.FilePath)
-			if err != nil {
-				c.Ctx.Output.SetStatus(http.StatusInternalServerError)
-				c.Ctx.Output.JSON(map[string]string{"error": "Failed to delete old attachment"}, false, false)
-				return
-			}
-
-			err = models.DeleteAttachmentByID(attachment.Id)
-			if err != nil {
-				c.Ctx.Output.SetStatus(http.StatusInternalServerError)
-				c.Ctx.Output.JSON(map[string]string{"error": "Failed to delete old attachment link"}, false, false)
-				return
-			}
-		}
-
-		decodedFile, err := base64.StdEncoding.DecodeString(updateApplicationRequest.FileBase64)
-		if err != nil {
-			c.Ctx.Output.SetStatus(http.StatusBadRequest)
-			c.Ctx.Output.JSON(map[string]string{"error": "Invalid base64 encoding"}, false, false)
-			return
-		}
-
-		uniqueFileName := time.Now().Format("20060102_150405") + "_" + updateApplicationRequest.FileName
-		uploadDir := "uploads"
-		os.MkdirAll(uploadDir, os.ModePerm)
-
-		filePath := filepath.Join(uploadDir, uniqueFileName)
-		err = os.WriteFile(filePath, decodedFile, 0644)
+	if len(files) > 0 {
+		// the old attachments are only deleted once the new one is saved
+		stored, err := storeAttachmentFiles(files)
 		if err != nil {
 			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
 			c.Ctx.Output.JSON(map[string]string{"error": "Failed to save new attachment"}, false, false)
 			return
 		}
 
-		err = models.CreateAttachment(application.Id, updateApplicationRequest.FileName, filePath)
+		replaced, err := models.ReplaceApplicationAttachments(application.Id, &stored[0])
 		if err != nil {
+			deleteStoredFiles(stored)
 			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
-			c.Ctx.Output.JSON(map[string]string{"error": "Failed to save new attachment link in database"}, false, false)
+			c.Ctx.Output.JSON(map[string]string{"error": "Failed to save new attachment"}, false, false)
 			return
 		}
-
+		deleteStoredFiles(replaced)
 	}
 
 	c.Ctx.Output.SetStatus(http.StatusOK)
@@ -346,7 +347,9 @@ func (c *ApplicationController) UpdateApplication() {
 
 }
 
-func (c *ApplicationController) DeleteApplication() {
+// WithdrawApplication withdraws an application under review right away. Hired freelancers
+// request a cancellation instead, they leave the job once the client acknowledges it.
+func (c *ApplicationController) WithdrawApplication() {
 
 	applicationID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
 	if err != nil {
@@ -355,6 +358,13 @@ func (c *ApplicationController) DeleteApplication() {
 		return
 	}
 
+	withdrawApplicationRequest, err := validators.WithdrawApplicationValidator(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
 	userID := c.Ctx.Input.GetData("id").(int)
 	user, err := models.GetUserById(userID)
 	if user == nil || err != nil {
@@ -372,37 +382,150 @@ func (c *ApplicationController) DeleteApplication() {
 
 	if application.User.Id != user.Id {
 		c.Ctx.Output.SetStatus(http.StatusForbidden)
-		c.Ctx.Output.JSON(map[string]string{"error": "Freelancers are only allowed to delete their own applications"}, false, false)
+		c.Ctx.Output.JSON(map[string]string{"error": "Freelancers are only allowed to withdraw their own applications"}, false, false)
+		return
+	}
+
+	if application.Status == "accepted" {
+		c.requestCancellation(application, user, withdrawApplicationRequest.Reason)
 		return
 	}
 
-	if application.Status != "pending" {
+	if !application.InReview() {
 		c.Ctx.Output.SetStatus(http.StatusBadRequest)
-		c.Ctx.Output.JSON(map[string]string{"error": "Only pending applications can be deleted"}, false, false)
+		c.Ctx.Output.JSON(map[string]string{"error": "Only applications under review or accepted applications can be withdrawn"}, false, false)
 		return
 	}
 
-	attachment, err := models.GetAttachmentByApplicationID(application.Id)
-	if err == nil && attachment != nil {
-		err := os.Remove(attachment.FilePath)
-		if err != nil {
-			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
-			c.Ctx.Output.JSON(map[string]string{"error": "Failed to delete attachment"}, false, false)
-			return
-		}
+	err = models.WithdrawApplication(application, withdrawApplicationRequest.Reason)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Failed to withdraw application"}, false, false)
+		return
+	}
+
+	models.CreateNotification(application.Job.Client.Id, "application-withdrawn", fmt.Sprintf("%s %s withdrew their application to \"%s\"", user.Name, user.Surname, application.Job.Title),
+		withdrawalMessage(withdrawApplicationRequest.Reason), fmt.Sprintf("/user/client/jobs/%d", application.Job.Id))
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": "Application withdrawn successfully"}
+	c.ServeJSON()
+
+}
+
+// requestCancellation asks the client to let the hired freelancer leave the job
+func (c *ApplicationController) requestCancellation(application *models.Application, user *models.User, reason string) {
+	if application.Job.Status == "completed" {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "The job is already completed"}, false, false)
+		return
+	}
+
+	if reason == "" {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Missing required fields: reason"}, false, false)
+		return
+	}
+
+	_, err := models.GetPendingCancellationRequest(application.Id)
+	if err == nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Your cancellation request is waiting for the client"}, false, false)
+		return
+	}
+	if err != orm.ErrNoRows {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching cancellation requests"}, false, false)
+		return
+	}
+
+	requestID, err := models.CreateCancellationRequest(application, reason)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Failed to request cancellation"}, false, false)
+		return
+	}
+
+	models.CreateNotification(application.Job.Client.Id, "application-withdrawn", fmt.Sprintf("%s %s wants to leave \"%s\"", user.Name, user.Surname, application.Job.Title),
+		withdrawalMessage(reason), fmt.Sprintf("/user/client/jobs/%d", application.Job.Id))
+
+	c.Ctx.Output.SetStatus(http.StatusAccepted)
+	c.Data["json"] = map[string]interface{}{"message": "Cancellation requested, you leave the job once the client acknowledges it", "cancellation_request_id": requestID}
+	c.ServeJSON()
+}
+
+// AcknowledgeCancellationHandler lets the hired freelancer leave the job, the application is withdrawn
+// and the opening is free again
+func (c *ApplicationController) AcknowledgeCancellationHandler() {
+
+	applicationID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid application ID"}, false, false)
+		return
+	}
+
+	userID := c.Ctx.Input.GetData("id").(int)
+	application, err := models.GetApplicationByID(applicationID)
+	if err != nil || application == nil || application.Job.Client.Id != userID {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Application not found"}, false, false)
+		return
+	}
+
+	request, err := models.GetPendingCancellationRequest(application.Id)
+	if err == orm.ErrNoRows || (err == nil && application.Status != "accepted") {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "There is no cancellation request to acknowledge"}, false, false)
+		return
+	}
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching cancellation requests"}, false, false)
+		return
+	}
+
+	if err := jobstate.Can(application.Job, jobstate.Withdraw); err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
 	}
 
-	err = models.DeleteApplicationByID(applicationID)
+	request.Application = application
+	err = jobstate.Fire(application.Job, jobstate.Change{Event: jobstate.Withdraw, ActorID: userID, Reason: request.Reason, Cancellation: request})
+	if errors.Is(err, jobstate.ErrNotAllowed) {
+		c.Ctx.Output.SetStatus(http.StatusConflict)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
 	if err != nil {
 		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
-		c.Ctx.Output.JSON(map[string]string{"error": "Failed to delete application"}, false, false)
+		c.Ctx.Output.JSON(map[string]string{"error": "Failed to acknowledge cancellation request"}, false, false)
 		return
 	}
 
 	c.Ctx.Output.SetStatus(http.StatusOK)
-	c.Data["json"] = map[string]string{"message": "Application deleted successfully"}
+	c.Data["json"] = map[string]string{"message": "Cancellation acknowledged successfully", "job_status": application.Job.Status}
 	c.ServeJSON()
+}
 
+func withdrawalMessage(reason string) string {
+	if reason == "" {
+		return "No reason was given."
+	}
+	return "Reason: " + reason
+}
+
+func cancellationRequestInfo(request *models.CancellationRequest) types.CancellationRequestInfo {
+	return types.CancellationRequestInfo{
+		ID:             request.Id,
+		ApplicationID:  request.Application.Id,
+		FreelancerID:   request.Application.User.Id,
+		Reason:         request.Reason,
+		Status:         request.Status,
+		CreatedAt:      request.CreatedAt,
+		AcknowledgedAt: request.AcknowledgedAt,
+	}
 }
 
 func (c *ApplicationController) ChangeApplicationStatus() {
@@ -454,69 +577,47 @@ func (c *ApplicationController) ChangeApplicationStatus() {
 		return
 	}
 
-	if application.Status != "pending" {
+	if !application.InReview() {
 		c.Ctx.Output.SetStatus(http.StatusBadRequest)
-		c.Ctx.Output.JSON(map[string]string{"error": "You can only change application status of pending applications"}, false, false)
+		c.Ctx.Output.JSON(map[string]string{"error": "You can only change application status of applications under review"}, false, false)
+		return
+	}
+
+	if application.Status == changeApplicationStatusRequest.Status {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Application is already " + application.Status}, false, false)
 		return
 	}
 
 	if changeApplicationStatusRequest.Status == "rejected" {
 
-		if changeApplicationStatusRequest.RejectionReason == "" { // if no reason is provided, set a default one
-			application.RejectionReason = "Your application was rejected."
-		} else {
-			application.RejectionReason = changeApplicationStatusRequest.RejectionReason
+		rejectionReason := changeApplicationStatusRequest.RejectionReason
+		if rejectionReason == "" { // if no reason is provided, set a default one
+			rejectionReason = "Your application was rejected."
 		}
 
-		application.Status = "rejected"
-
-		err = models.UpdateApplication(application)
+		// a pending offer on the application is withdrawn
+		err = models.RejectApplication(application, rejectionReason)
 		if err != nil {
 			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
 			c.Ctx.Output.JSON(map[string]string{"error": "Failed to update application status"}, false, false)
 			return
 		}
 
-	} else if changeApplicationStatusRequest.Status == "accepted" {
-
-		// reject all other applications for this job
-		applications, err := models.GetApplicationsByJobID(application.Job.Id)
-		if err != nil {
-			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
-			c.Ctx.Output.JSON(map[string]string{"error": "Failed to get applications for this job"}, false, false)
-			return
-		}
-		for _, app := range applications {
-			if app.Id != applicationID && app.Status == "pending" {
-
-				app.Status = "rejected"
-				app.RejectionReason = "Your application was automatically rejected because another application was accepted."
-
-				err = models.UpdateApplication(&app)
-				if err != nil {
-					c.Ctx.Output.SetStatus(http.StatusInternalServerError)
-					c.Ctx.Output.JSON(map[string]string{"error": "Failed to reject other applications"}, false, false)
-					return
-				}
-			}
-		}
-
-		// update the status of the accepted application
-		application.Status = "accepted"
-		application.Job.Status = "in-progress"
-		application.Job.Freelancer = application.User
+	} else {
 
-		err = models.UpdateApplication(application)
+		stage := changeApplicationStatusRequest.Status
+		err = models.MoveApplicationToStage(application, stage)
 		if err != nil {
 			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
 			c.Ctx.Output.JSON(map[string]string{"error": "Failed to update application status"}, false, false)
 			return
 		}
-		err = models.UpdateJob(application.Job)
-		if err != nil {
-			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
-			c.Ctx.Output.JSON(map[string]string{"error": "Failed to update job status"}, false, false)
-			return
+
+		// the freelancer only hears about the stages the client exposes
+		if stage != "pending" && application.Job.FreelancerStatus(stage) == stage {
+			models.CreateNotification(application.User.Id, "application-stage", fmt.Sprintf("Your application to \"%s\" moved to %s", application.Job.Title, stage),
+				"The client moved your application to the next stage.", fmt.Sprintf("/user/freelancer/applications/%d", application.Id))
 		}
 
 	}
@@ -526,3 +627,101 @@ func (c *ApplicationController) ChangeApplicationStatus() {
 	c.ServeJSON()
 
 }
+
+func (c *ApplicationController) ApplicationNotesHandler() {
+
+	applicationNotesRequest, err := validators.ApplicationNotesValidator(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	applicationID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid application ID"}, false, false)
+		return
+	}
+
+	userID := c.Ctx.Input.GetData("id").(int)
+	application, err := models.GetApplicationByID(applicationID)
+	if err != nil || application == nil || application.Job.Client.Id != userID {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Application not found"}, false, false)
+		return
+	}
+
+	err = models.UpdateApplicationNotes(application, applicationNotesRequest.Notes)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Failed to update application notes"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": "Application notes updated successfully"}
+	c.ServeJSON()
+}
+
+// Admin function
+func (c *ApplicationController) GetDeletedApplications() {
+
+	applications, err := models.GetDeletedApplications()
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching applications"}, false, false)
+		return
+	}
+
+	var applicationList []types.Application
+	for _, application := range applications {
+		applicationList = append(applicationList, types.Application{
+			ID:              application.Id,
+			UserID:          application.User.Id,
+			JobID:           application.Job.Id,
+			Description:     application.Description,
+			RejectionReason: application.RejectionReason,
+			Status:          application.Status,
+			CreatedAt:       application.CreatedAt,
+			DeletedAt:       application.DeletedAt,
+		})
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = applicationList
+	c.ServeJSON()
+}
+
+// Admin function
+func (c *ApplicationController) RestoreApplication() {
+
+	applicationID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid application ID"}, false, false)
+		return
+	}
+
+	err = models.RestoreApplicationByID(applicationID)
+	if err != nil {
+		if err == orm.ErrNoRows {
+			c.Ctx.Output.SetStatus(http.StatusNotFound)
+			c.Ctx.Output.JSON(map[string]string{"error": "Application not found"}, false, false)
+		} else if err.Error() == "application is not deleted" {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Ctx.Output.JSON(map[string]string{"error": "Application is not deleted"}, false, false)
+		} else if err.Error() == "application job is deleted" || err.Error() == "application user is deleted" {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Ctx.Output.JSON(map[string]string{"error": "Restore the job and applicant before restoring the application"}, false, false)
+		} else {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "Failed to restore application"}, false, false)
+		}
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": "Application restored successfully"}
+	c.ServeJSON()
+}
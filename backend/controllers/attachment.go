@@ -2,23 +2,77 @@ package controllers
 
 import (
 	"backend/models"
+	"backend/storage"
+	"backend/types"
+	"backend/validators"
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
 	"net/http"
-	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/beego/beego/v2/server/web"
 )
 
+// maxAttachments is how many files an application or a job can have
+const maxAttachments = 10
+
 type AttachmentController struct {
 	web.Controller
 }
 
+// DownloadAttachment redirects to a short-lived signed URL when the storage has them and
+// storage_signed_downloads is on, otherwise the backend streams the file itself. Only files
+// scanned clean are downloaded.
 func (c *AttachmentController) DownloadAttachment() {
+	attachment, ok := c.getDownloadableAttachment()
+	if !ok {
+		return
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(attachment.FileName))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	c.serveStoredFile(attachment.FilePath, attachment.FileName, contentType, attachment.Size)
+}
+
+// GetAttachmentThumbnailHandler serves the PNG of the first page, made by the process-attachments task
+func (c *AttachmentController) GetAttachmentThumbnailHandler() {
+	attachment, ok := c.getDownloadableAttachment()
+	if !ok {
+		return
+	}
+
+	if attachment.ThumbnailPath == "" {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Thumbnail not available"}, false, false)
+		return
+	}
+
+	c.serveStoredFile(attachment.ThumbnailPath, "", "image/png", 0)
+}
+
+// getDownloadableAttachment loads the attachment from the :id parameter and writes the error response
+// when the user cannot see it or its file is not scanned clean
+func (c *AttachmentController) getDownloadableAttachment() (*models.Attachment, bool) {
 	attachmentID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
 	if err != nil {
 		c.Ctx.Output.SetStatus(http.StatusBadRequest)
 		c.Ctx.Output.JSON(map[string]string{"error": "Invalid application ID"}, false, false)
-		return
+		return nil, false
 	}
 
 	userID := c.Ctx.Input.GetData("id").(int)
@@ -26,22 +80,34 @@ func (c *AttachmentController) DownloadAttachment() {
 	if user == nil || err != nil {
 		c.Ctx.Output.SetStatus(http.StatusUnauthorized)
 		c.Ctx.Output.JSON(map[string]string{"error": "User not found"}, false, false)
-		return
+		return nil, false
 	}
 
 	attachment, err := models.GetAttachmentByID(attachmentID)
 	if err != nil {
 		c.Ctx.Output.SetStatus(http.StatusNotFound)
 		c.Ctx.Output.JSON(map[string]string{"error": "Attachment not found"}, false, false)
-		return
+		return nil, false
 	}
 
-	if user.Role == "freelancer" {
+	if attachment.PortfolioItem != nil {
+
+		// Portfolio files are shown on the freelancer's profile, which every user can see
+
+	} else if attachment.Job != nil {
+
+		if !canViewJobAttachment(attachment.Job, user) {
+			c.Ctx.Output.SetStatus(http.StatusForbidden)
+			c.Ctx.Output.JSON(map[string]string{"error": "You do not have permission to download this attachment"}, false, false)
+			return nil, false
+		}
+
+	} else if user.Role == "freelancer" {
 
 		if user.Id != attachment.Application.User.Id {
 			c.Ctx.Output.SetStatus(http.StatusForbidden)
 			c.Ctx.Output.JSON(map[string]string{"error": "You do not have permission to download this attachment"}, false, false)
-			return
+			return nil, false
 		}
 
 	} else if user.Role == "client" {
@@ -50,22 +116,618 @@ func (c *AttachmentController) DownloadAttachment() {
 		if err != nil {
 			c.Ctx.Output.SetStatus(http.StatusNotFound)
 			c.Ctx.Output.JSON(map[string]string{"error": "Job not found"}, false, false)
-			return
+			return nil, false
 		}
 
 		if user.Id != job.Client.Id {
 			c.Ctx.Output.SetStatus(http.StatusForbidden)
 			c.Ctx.Output.JSON(map[string]string{"error": "You do not have permission to download this attachment"}, false, false)
-			return
+			return nil, false
 		}
 
 	}
 
-	if _, err := os.Stat(attachment.FilePath); os.IsNotExist(err) {
+	switch attachment.Status {
+	case "quarantined":
+		c.Ctx.Output.SetStatus(http.StatusConflict)
+		c.Ctx.Output.JSON(map[string]string{"error": "Attachment is waiting for a malware scan"}, false, false)
+		return nil, false
+	case "infected":
+		c.Ctx.Output.SetStatus(http.StatusGone)
+		c.Ctx.Output.JSON(map[string]string{"error": "Attachment was removed because it contains malware"}, false, false)
+		return nil, false
+	case "rejected":
+		c.Ctx.Output.SetStatus(http.StatusGone)
+		c.Ctx.Output.JSON(map[string]string{"error": "Attachment was removed because it could not be scanned"}, false, false)
+		return nil, false
+	}
+
+	return attachment, true
+}
+
+// serveStoredFile redirects to a signed URL of the key or streams the file. A file name makes the
+// browser download the file, without one it is shown inline.
+func (c *AttachmentController) serveStoredFile(key, fileName, contentType string, size int64) {
+	store := storage.Default()
+	if web.AppConfig.DefaultBool("storage_signed_downloads", true) {
+		expires := time.Duration(web.AppConfig.DefaultInt("storage_signed_url_expiry", 300)) * time.Second
+		url, err := store.SignedURL(key, fileName, expires)
+		if err == nil {
+			c.Ctx.Output.Header("Cache-Control", "no-store")
+			c.Redirect(url, http.StatusFound)
+			return
+		}
+		if err != storage.ErrSignedURLUnsupported {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "Failed to download attachment"}, false, false)
+			return
+		}
+	}
+
+	file, err := store.Get(key)
+	if err == storage.ErrNotFound {
 		c.Ctx.Output.SetStatus(http.StatusNotFound)
 		c.Ctx.Output.JSON(map[string]string{"error": "File not found"}, false, false)
 		return
 	}
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Failed to download attachment"}, false, false)
+		return
+	}
+	defer file.Close()
+
+	c.Ctx.Output.Header("Content-Type", contentType)
+	if fileName != "" {
+		c.Ctx.Output.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	}
+	if size > 0 {
+		c.Ctx.Output.Header("Content-Length", strconv.FormatInt(size, 10))
+	}
+	c.Ctx.ResponseWriter.WriteHeader(http.StatusOK)
+	io.Copy(c.Ctx.ResponseWriter, file)
+}
+
+// AddApplicationAttachmentHandler attaches one more file to the freelancer's application under review
+func (c *AttachmentController) AddApplicationAttachmentHandler() {
+	application, ok := c.getOwnApplicationInReview()
+	if !ok {
+		return
+	}
+
+	attachmentUpload, err := c.attachmentUploadRequest()
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	attachments, err := models.GetAttachmentsByApplicationID(application.Id)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching attachments"}, false, false)
+		return
+	}
+
+	userID := c.Ctx.Input.GetData("id").(int)
+	files, err := attachmentFiles(userID, []types.AttachmentUpload{*attachmentUpload}, attachments, attachmentLimits())
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	attachment, err := saveApplicationAttachment(application.Id, &files[0])
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Failed to save attachment"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusCreated)
+	c.Data["json"] = attachmentInfo(attachment)
+	c.ServeJSON()
+}
+
+// DeleteApplicationAttachmentHandler removes a file from the freelancer's application under review
+func (c *AttachmentController) DeleteApplicationAttachmentHandler() {
+	application, ok := c.getOwnApplicationInReview()
+	if !ok {
+		return
+	}
+
+	attachment, ok := c.getAttachmentParam()
+	if !ok {
+		return
+	}
+	if attachment.Application == nil || attachment.Application.Id != application.Id {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Attachment not found"}, false, false)
+		return
+	}
+
+	if err := deleteAttachment(attachment); err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Failed to delete attachment"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": "Attachment deleted successfully"}
+	c.ServeJSON()
+}
+
+// AddJobAttachmentHandler attaches one more spec document to the client's job
+func (c *AttachmentController) AddJobAttachmentHandler() {
+	job, ok := c.getOwnEditableJob()
+	if !ok {
+		return
+	}
+
+	attachmentUpload, err := c.attachmentUploadRequest()
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	attachments, err := models.GetAttachmentsByJobID(job.Id)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching attachments"}, false, false)
+		return
+	}
+
+	userID := c.Ctx.Input.GetData("id").(int)
+	files, err := attachmentFiles(userID, []types.AttachmentUpload{*attachmentUpload}, attachments, attachmentLimits())
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	attachment, err := saveJobAttachment(job.Id, &files[0])
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Failed to save attachment"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusCreated)
+	c.Data["json"] = attachmentInfo(attachment)
+	c.ServeJSON()
+}
+
+// DeleteJobAttachmentHandler removes a spec document from the client's job
+func (c *AttachmentController) DeleteJobAttachmentHandler() {
+	job, ok := c.getOwnEditableJob()
+	if !ok {
+		return
+	}
+
+	attachment, ok := c.getAttachmentParam()
+	if !ok {
+		return
+	}
+	if attachment.Job == nil || attachment.Job.Id != job.Id {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Attachment not found"}, false, false)
+		return
+	}
+
+	if err := deleteAttachment(attachment); err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Failed to delete attachment"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": "Attachment deleted successfully"}
+	c.ServeJSON()
+}
+
+// getOwnApplicationInReview loads the application from the :id parameter and writes the error response
+// when it is not the freelancer's own application under review
+func (c *AttachmentController) getOwnApplicationInReview() (*models.Application, bool) {
+	applicationID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid application ID"}, false, false)
+		return nil, false
+	}
+
+	userID := c.Ctx.Input.GetData("id").(int)
+	application, err := models.GetApplicationByID(applicationID)
+	if err != nil || application == nil || application.User.Id != userID {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Application not found"}, false, false)
+		return nil, false
+	}
+
+	if !application.InReview() {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Only applications under review can be updated"}, false, false)
+		return nil, false
+	}
+
+	return application, true
+}
+
+// getOwnEditableJob loads the job from the :id parameter and writes the error response when it
+// belongs to another client or can no longer be updated
+func (c *AttachmentController) getOwnEditableJob() (*models.Job, bool) {
+	jobID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid job ID"}, false, false)
+		return nil, false
+	}
+
+	userID := c.Ctx.Input.GetData("id").(int)
+	job, err := models.GetJobByID(jobID)
+	if err != nil || job.Client.Id != userID {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Job not found"}, false, false)
+		return nil, false
+	}
+
+	if job.Status != "open" && job.Status != "draft" && job.Status != "scheduled" {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "It is only possible to update open, draft or scheduled jobs"}, false, false)
+		return nil, false
+	}
+
+	return job, true
+}
+
+// attachmentUploadRequest reads the file of a JSON or a multipart/form-data request
+func (c *AttachmentController) attachmentUploadRequest() (*types.AttachmentUpload, error) {
+	if c.Ctx.Input.IsUpload() {
+		return validators.AttachmentFormValidator(c.Ctx.Request.MultipartForm)
+	}
+	return validators.AttachmentUploadValidator(c.Ctx.Input.RequestBody)
+}
+
+func (c *AttachmentController) getAttachmentParam() (*models.Attachment, bool) {
+	attachmentID, err := strconv.Atoi(c.Ctx.Input.Param(":attachmentId"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid attachment ID"}, false, false)
+		return nil, false
+	}
+
+	attachment, err := models.GetAttachmentByID(attachmentID)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Attachment not found"}, false, false)
+		return nil, false
+	}
+
+	return attachment, true
+}
+
+// canViewJobAttachment reports whether the user can see the job as on the job page: the job must be
+// visible to the user, and jobs that are not open are only shown to their client, applicants and admins
+func canViewJobAttachment(job *models.Job, user *models.User) bool {
+	if !models.CanViewJob(job, user) {
+		return false
+	}
+	if job.Status == "open" || user.Role == "admin" || job.Client.Id == user.Id {
+		return true
+	}
+
+	if user.Role == "freelancer" {
+		application, err := models.GetApplicationByUserAndJob(user.Id, job.Id)
+		return err == nil && application != nil
+	}
+	return false
+}
+
+// uploadedFile is a file of a request checked against the size limits, ready to be stored
+type uploadedFile struct {
+	FileName string
+	Size     int64
+	Data     []byte                // decoded file of a JSON request
+	Part     *multipart.FileHeader // file of a multipart request, spooled by middleware.MultipartUploadChain
+	Upload   *models.Upload        // completed resumable upload
+}
+
+// fileLimits are the limits on the files of one application, job or portfolio item
+type fileLimits struct {
+	Files     int
+	FileSize  int64
+	TotalSize int64
+	CheckName func(fileName string) error // checks the names of finished resumable uploads
+}
+
+// attachmentLimits are the limits on the PDF attachments of applications and jobs
+func attachmentLimits() fileLimits {
+	return fileLimits{
+		Files:     maxAttachments,
+		FileSize:  int64(web.AppConfig.DefaultInt("attachment_max_file_size", 10)) << 20,
+		TotalSize: int64(web.AppConfig.DefaultInt("attachment_max_total_size", 25)) << 20,
+		CheckName: validators.AttachmentFileNameValidator,
+	}
+}
+
+// attachmentFiles loads the files of the request and checks them against the limits, counting the
+// attachments the application, job or portfolio item already has. Errors are meant for the client.
+func attachmentFiles(userID int, uploads []types.AttachmentUpload, existing []models.Attachment, limits fileLimits) ([]uploadedFile, error) {
+	if len(existing)+len(uploads) > limits.Files {
+		return nil, fmt.Errorf("At most %d files can be attached", limits.Files)
+	}
+
+	totalSize := models.AttachmentsSize(existing)
+	files := make([]uploadedFile, 0, len(uploads))
+	for i := range uploads {
+		file, err := attachmentFile(userID, &uploads[i], limits.CheckName)
+		if err != nil {
+			return nil, err
+		}
+
+		if file.Size > limits.FileSize {
+			return nil, fmt.Errorf("%s is larger than %d MB", file.FileName, limits.FileSize>>20)
+		}
+		totalSize += file.Size
+		if totalSize > limits.TotalSize {
+			return nil, fmt.Errorf("Attachments cannot be larger than %d MB in total", limits.TotalSize>>20)
+		}
+
+		files = append(files, *file)
+	}
+
+	return files, nil
+}
+
+// attachmentFile loads the file of the request and checks that its content matches its extension
+func attachmentFile(userID int, upload *types.AttachmentUpload, checkName func(fileName string) error) (*uploadedFile, error) {
+	if upload.Part != nil {
+		src, err := upload.Part.Open()
+		if err != nil {
+			return nil, errors.New("Failed to read file")
+		}
+		defer src.Close()
+		if err := validators.FileContentValidator(upload.FileName, src); err != nil {
+			return nil, fmt.Errorf("%s: %v", upload.FileName, err)
+		}
+		return &uploadedFile{FileName: upload.FileName, Size: upload.Part.Size, Part: upload.Part}, nil
+	}
+
+	if upload.UploadID != 0 {
+		finished, err := models.GetUpload(upload.UploadID, userID)
+		if err != nil {
+			return nil, errors.New("Upload not found")
+		}
+		if finished.Status != "completed" {
+			return nil, errors.New("Upload is not completed")
+		}
+		if err := checkName(finished.FileName); err != nil {
+			return nil, err
+		}
+		src, err := storage.Default().Get(finished.FilePath)
+		if err != nil {
+			return nil, errors.New("Failed to read upload")
+		}
+		defer src.Close()
+		if err := validators.FileContentValidator(finished.FileName, src); err != nil {
+			return nil, fmt.Errorf("%s: %v", finished.FileName, err)
+		}
+		return &uploadedFile{FileName: finished.FileName, Size: finished.Length, Upload: finished}, nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(upload.FileBase64)
+	if err != nil {
+		return nil, errors.New("Invalid base64 encoding")
+	}
+	if err := validators.FileContentValidator(upload.FileName, bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("%s: %v", upload.FileName, err)
+	}
+	return &uploadedFile{FileName: upload.FileName, Size: int64(len(data)), Data: data}, nil
+}
+
+// storeAttachmentFile puts the file in the storage and returns its key. Multipart files are streamed
+// from their temporary file and a resumable upload hands over the file it already stored.
+func storeAttachmentFile(file *uploadedFile) (string, error) {
+	if file.Upload != nil {
+		if err := models.ClaimUpload(file.Upload); err != nil {
+			return "", err
+		}
+		return file.Upload.FilePath, nil
+	}
+
+	key := newStorageKey(file.FileName)
+	if file.Part == nil {
+		return key, storage.Default().Put(key, bytes.NewReader(file.Data), file.Size)
+	}
+
+	src, err := file.Part.Open()
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	if err := storage.Default().Put(key, src, file.Size); err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
+
+// newStorageKey returns a unique storage key for the file. Only the extension of the client's
+// file name is kept, the name itself is random.
+func newStorageKey(fileName string) string {
+	random := make([]byte, 12)
+	rand.Read(random)
+	return time.Now().Format("20060102_150405") + "_" + hex.EncodeToString(random) + strings.ToLower(filepath.Ext(fileName))
+}
+
+// storeAttachmentFiles stores the files of an application or a job before it is created, so that
+// it is created together with its attachments. On error the files stored so far are removed.
+func storeAttachmentFiles(files []uploadedFile) ([]models.Attachment, error) {
+	attachments := make([]models.Attachment, 0, len(files))
+	for i := range files {
+		key, err := storeAttachmentFile(&files[i])
+		if err != nil {
+			deleteStoredFiles(attachments)
+			return nil, err
+		}
+		attachments = append(attachments, models.NewAttachment(files[i].FileName, key, files[i].Size))
+	}
+
+	return attachments, nil
+}
+
+// deleteStoredFiles removes the files of attachments that were not created or whose rows were deleted
+func deleteStoredFiles(attachments []models.Attachment) {
+	for _, attachment := range attachments {
+		storage.Default().Delete(attachment.FilePath)
+		if attachment.ThumbnailPath != "" {
+			storage.Default().Delete(attachment.ThumbnailPath)
+		}
+	}
+}
+
+func saveApplicationAttachment(applicationID int, file *uploadedFile) (*models.Attachment, error) {
+	key, err := storeAttachmentFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment, err := models.CreateApplicationAttachment(applicationID, file.FileName, key, file.Size)
+	if err != nil {
+		storage.Default().Delete(key)
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+func saveJobAttachment(jobID int, file *uploadedFile) (*models.Attachment, error) {
+	key, err := storeAttachmentFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment, err := models.CreateJobAttachment(jobID, file.FileName, key, file.Size)
+	if err != nil {
+		storage.Default().Delete(key)
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// deleteAttachment removes the file and the attachment row, a file that is already gone is not an error
+func deleteAttachment(attachment *models.Attachment) error {
+	for _, key := range []string{attachment.FilePath, attachment.ThumbnailPath} {
+		if key == "" {
+			continue
+		}
+		if err := storage.Default().Delete(key); err != nil {
+			return err
+		}
+	}
+
+	return models.DeleteAttachmentByID(attachment.Id)
+}
+
+func attachmentInfo(attachment *models.Attachment) types.Attachment {
+	info := types.Attachment{
+		ID:            attachment.Id,
+		FileName:      attachment.FileName,
+		FilePath:      attachment.FilePath,
+		Size:          attachment.Size,
+		Status:        attachment.Status,
+		PreviewStatus: attachment.PreviewStatus,
+		PageCount:     attachment.PageCount,
+		CreatedAt:     attachment.CreatedAt,
+	}
+	if attachment.ThumbnailPath != "" {
+		info.ThumbnailURL = fmt.Sprintf("/user/attachments/%d/thumbnail", attachment.Id)
+	}
+	if attachment.Application != nil {
+		info.ApplicationID = attachment.Application.Id
+	}
+	if attachment.Job != nil {
+		info.JobID = attachment.Job.Id
+	}
+	if attachment.PortfolioItem != nil {
+		info.PortfolioItemID = attachment.PortfolioItem.Id
+	}
+	return info
+}
+
+func attachmentList(attachments []models.Attachment) []types.Attachment {
+	attachmentList := []types.Attachment{}
+	for i := range attachments {
+		attachmentList = append(attachmentList, attachmentInfo(&attachments[i]))
+	}
+	return attachmentList
+}
+
+// firstAttachment returns the first attachment for the clients that show a single one
+func firstAttachment(attachments []types.Attachment) *types.Attachment {
+	if len(attachments) == 0 {
+		return nil
+	}
+	return &attachments[0]
+}
+
+const (
+	// maxHighlights is how many snippets of an attachment's text are highlighted
+	maxHighlights = 3
+	// highlightContext is how many characters around a keyword a snippet shows
+	highlightContext = 60
+)
+
+// textHighlights returns up to maxHighlights snippets of the text around the keywords. The snippets are
+// HTML escaped with the keywords wrapped in <mark>, so clients can show them as they are.
+func textHighlights(text string, keywords []string) []string {
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	// the ranges of the keywords in the text, in order and without overlaps
+	var matches [][2]int
+	for _, keyword := range keywords {
+		needle := []rune(keyword)
+		for i := 0; i+len(needle) <= len(lower); i++ {
+			if lower[i] == needle[0] && string(lower[i:i+len(needle)]) == keyword {
+				matches = append(matches, [2]int{i, i + len(needle)})
+				i += len(needle) - 1
+			}
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i][0] < matches[j][0] })
+
+	var highlights []string
+	for i := 0; i < len(matches) && len(highlights) < maxHighlights; {
+		start := max(0, matches[i][0]-highlightContext)
+		end := min(len(runes), matches[i][1]+highlightContext)
+
+		var snippet strings.Builder
+		if start > 0 {
+			snippet.WriteString("…")
+		}
+		position := start
+		for ; i < len(matches) && matches[i][0] < end; i++ {
+			if matches[i][0] < position {
+				continue
+			}
+			end = max(end, matches[i][1])
+			snippet.WriteString(html.EscapeString(string(runes[position:matches[i][0]])))
+			snippet.WriteString("<mark>" + html.EscapeString(string(runes[matches[i][0]:matches[i][1]])) + "</mark>")
+			position = matches[i][1]
+		}
+		snippet.WriteString(html.EscapeString(string(runes[position:end])))
+		if end < len(runes) {
+			snippet.WriteString("…")
+		}
+
+		highlights = append(highlights, snippet.String())
+	}
 
-	c.Ctx.Output.Download(attachment.FilePath, attachment.FileName)
+	return highlights
 }
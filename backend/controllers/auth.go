@@ -23,8 +23,7 @@ func (c *AuthController) RegisterHandler() {
 		return
 	}
 
-	user, err := models.GetUserByEmail(registerRequest.Email)
-	if user != nil || err == nil {
+	if models.IsEmailTaken(registerRequest.Email) {
 		c.Ctx.Output.SetStatus(http.StatusConflict)
 		c.Ctx.Output.JSON(map[string]string{"error": "Email already registered"}, false, false)
 		return
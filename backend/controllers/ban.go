@@ -0,0 +1,319 @@
+package controllers
+
+import (
+	"backend/models"
+	"backend/types"
+	"backend/validators"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/beego/beego/v2/server/web"
+)
+
+type BanController struct {
+	web.Controller
+}
+
+func banInfo(ban *models.Ban) *types.BanInfo {
+	adminID := 0
+	if ban.Admin != nil {
+		adminID = ban.Admin.Id
+	}
+
+	return &types.BanInfo{
+		ID:        ban.Id,
+		UserID:    ban.User.Id,
+		AdminID:   adminID,
+		Reason:    ban.Reason,
+		StartsAt:  ban.StartsAt,
+		EndsAt:    ban.EndsAt,
+		LiftedAt:  ban.LiftedAt,
+		Active:    ban.IsActive(time.Now()),
+		CreatedAt: ban.CreatedAt,
+	}
+}
+
+func banAppealInfo(appeal *models.BanAppeal) types.BanAppealInfo {
+	reviewedByID := 0
+	if appeal.ReviewedBy != nil {
+		reviewedByID = appeal.ReviewedBy.Id
+	}
+
+	appealInfo := types.BanAppealInfo{
+		ID:            appeal.Id,
+		BanID:         appeal.Ban.Id,
+		UserID:        appeal.User.Id,
+		Message:       appeal.Message,
+		Status:        appeal.Status,
+		AdminResponse: appeal.AdminResponse,
+		ReviewedByID:  reviewedByID,
+		ReviewedAt:    appeal.ReviewedAt,
+		CreatedAt:     appeal.CreatedAt,
+	}
+	if appeal.Ban.User != nil {
+		appealInfo.Ban = banInfo(appeal.Ban)
+	}
+
+	return appealInfo
+}
+
+// Reachable by banned users, see middleware.AppealAuthMiddleware
+func (c *BanController) SubmitAppealHandler() {
+
+	userID := c.Ctx.Input.GetData("id").(int)
+	user, err := models.GetUserById(userID)
+	if user == nil || err != nil {
+		c.Ctx.Output.SetStatus(http.StatusUnauthorized)
+		c.Ctx.Output.JSON(map[string]string{"error": "User not found"}, false, false)
+		return
+	}
+
+	submitBanAppealRequest, err := validators.SubmitBanAppealValidator(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	ban, err := models.GetActiveBanByUserID(userID)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching ban"}, false, false)
+		return
+	}
+
+	if ban == nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "You do not have an active ban to appeal"}, false, false)
+		return
+	}
+
+	_, err = models.CreateBanAppeal(ban, submitBanAppealRequest.Message)
+	if err != nil {
+		if err.Error() == "appeal already pending" {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Ctx.Output.JSON(map[string]string{"error": "You already have a pending appeal for this ban"}, false, false)
+		} else {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "Failed to submit appeal"}, false, false)
+		}
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusCreated)
+	c.Data["json"] = map[string]string{"message": "Appeal submitted successfully"}
+	c.ServeJSON()
+}
+
+// Reachable by banned users, see middleware.AppealAuthMiddleware
+func (c *BanController) GetAppealsHandler() {
+
+	userID := c.Ctx.Input.GetData("id").(int)
+	user, err := models.GetUserById(userID)
+	if user == nil || err != nil {
+		c.Ctx.Output.SetStatus(http.StatusUnauthorized)
+		c.Ctx.Output.JSON(map[string]string{"error": "User not found"}, false, false)
+		return
+	}
+
+	appeals, err := models.GetBanAppealsByUserID(userID)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching appeals"}, false, false)
+		return
+	}
+
+	var appealList []types.BanAppealInfo
+	for i := range appeals {
+		appealList = append(appealList, banAppealInfo(&appeals[i]))
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = appealList
+	c.ServeJSON()
+}
+
+// Admin function
+func (c *BanController) BanUserHandler() {
+
+	userID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid user ID"}, false, false)
+		return
+	}
+
+	adminID := c.Ctx.Input.GetData("id").(int)
+	if adminID == userID {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Admins cannot ban themselves"}, false, false)
+		return
+	}
+
+	createBanRequest, err := validators.CreateBanValidator(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	user, err := models.GetUserById(userID)
+	if user == nil || err != nil {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "User not found"}, false, false)
+		return
+	}
+
+	_, err = models.CreateBan(userID, adminID, createBanRequest.Reason, createBanRequest.EndsAt)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Failed to ban user"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusCreated)
+	c.Data["json"] = map[string]string{"message": "User banned successfully"}
+	c.ServeJSON()
+}
+
+// Admin function
+func (c *BanController) GetUserBansHandler() {
+
+	userID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid user ID"}, false, false)
+		return
+	}
+
+	bans, err := models.GetBansByUserID(userID)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching bans"}, false, false)
+		return
+	}
+
+	var banList []types.BanInfo
+	for i := range bans {
+		banList = append(banList, *banInfo(&bans[i]))
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = banList
+	c.ServeJSON()
+}
+
+// Admin function
+func (c *BanController) LiftBanHandler() {
+
+	banID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid ban ID"}, false, false)
+		return
+	}
+
+	ban, err := models.GetBanByID(banID)
+	if ban == nil || err != nil {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Ban not found"}, false, false)
+		return
+	}
+
+	if !ban.IsActive(time.Now()) {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Ban is no longer active"}, false, false)
+		return
+	}
+
+	err = models.LiftBan(ban)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Failed to lift ban"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": "Ban lifted successfully"}
+	c.ServeJSON()
+}
+
+// Admin function
+func (c *BanController) GetBanAppealsHandler() {
+
+	status := c.GetString("status", "pending")
+	if status == "all" {
+		status = ""
+	}
+
+	appeals, err := models.GetBanAppeals(status)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching appeals"}, false, false)
+		return
+	}
+
+	var appealList []types.BanAppealInfo
+	for i := range appeals {
+		appealList = append(appealList, banAppealInfo(&appeals[i]))
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = appealList
+	c.ServeJSON()
+}
+
+// Admin function
+func (c *BanController) ReviewBanAppealHandler() {
+
+	appealID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid appeal ID"}, false, false)
+		return
+	}
+
+	reviewBanAppealRequest, err := validators.ReviewBanAppealValidator(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	appeal, err := models.GetBanAppealByID(appealID)
+	if appeal == nil || err != nil {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Appeal not found"}, false, false)
+		return
+	}
+
+	if appeal.Status != "pending" {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Appeal has already been reviewed"}, false, false)
+		return
+	}
+
+	now := time.Now()
+	appeal.Status = reviewBanAppealRequest.Status
+	appeal.AdminResponse = reviewBanAppealRequest.Response
+	appeal.ReviewedBy = &models.User{Id: c.Ctx.Input.GetData("id").(int)}
+	appeal.ReviewedAt = &now
+
+	// an approved appeal lifts its ban together with the review
+	err = models.ReviewBanAppeal(appeal, appeal.Status == "approved" && appeal.Ban.IsActive(now))
+	if err != nil && err.Error() == "appeal already reviewed" {
+		c.Ctx.Output.SetStatus(http.StatusConflict)
+		c.Ctx.Output.JSON(map[string]string{"error": "Appeal has already been reviewed"}, false, false)
+		return
+	}
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Failed to update appeal"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": "Appeal reviewed successfully"}
+	c.ServeJSON()
+}
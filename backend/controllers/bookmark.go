@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"backend/models"
+	"backend/types"
+	"backend/validators"
+	"net/http"
+	"strconv"
+
+	"github.com/beego/beego/v2/server/web"
+)
+
+type BookmarkController struct {
+	web.Controller
+}
+
+func (c *BookmarkController) CreateJobBookmarkHandler() {
+	userID := c.Ctx.Input.GetData("id").(int)
+	user, err := models.GetUserById(userID)
+	if user == nil || err != nil {
+		c.Ctx.Output.SetStatus(http.StatusUnauthorized)
+		c.Ctx.Output.JSON(map[string]string{"error": "User not found"}, false, false)
+		return
+	}
+
+	if user.Role != "freelancer" {
+		c.Ctx.Output.SetStatus(http.StatusForbidden)
+		c.Ctx.Output.JSON(map[string]string{"error": "Only freelancers can bookmark jobs"}, false, false)
+		return
+	}
+
+	jobBookmarkRequest, err := validators.JobBookmarkValidator(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	job, err := models.GetJobByID(jobBookmarkRequest.JobID)
+	if err != nil || job.Status != "open" || !models.CanViewJob(job, user) {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Job not found"}, false, false)
+		return
+	}
+
+	if models.JobBookmarkExists(userID, job.Id) {
+		c.Ctx.Output.SetStatus(http.StatusConflict)
+		c.Ctx.Output.JSON(map[string]string{"error": "Job is already bookmarked"}, false, false)
+		return
+	}
+
+	err = models.CreateJobBookmark(userID, job.Id)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error bookmarking job"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusCreated)
+	c.Data["json"] = map[string]string{"message": "Job bookmarked successfully"}
+	c.ServeJSON()
+}
+
+// GetJobBookmarksHandler lists the freelancer's bookmarks, flagging jobs that closed or were deleted
+func (c *BookmarkController) GetJobBookmarksHandler() {
+	userID := c.Ctx.Input.GetData("id").(int)
+
+	bookmarks, err := models.GetJobBookmarksByUserID(userID)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching bookmarks"}, false, false)
+		return
+	}
+
+	bookmarkList := []types.JobBookmarkInfo{}
+	for _, bookmark := range bookmarks {
+		job := bookmark.Job
+
+		skillList, err := jobSkillList(job.Id)
+		if err != nil {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "Error fetching job skills"}, false, false)
+			return
+		}
+
+		applicationID := 0
+		application, err := models.GetApplicationByUserAndJob(userID, job.Id)
+		if err == nil && application != nil {
+			applicationID = application.Id
+		}
+
+		status := job.Status
+		if job.DeletedAt != nil {
+			status = "deleted"
+		}
+
+		bookmarkList = append(bookmarkList, types.JobBookmarkInfo{
+			Job: types.JobInfo{
+				ID:            job.Id,
+				Title:         job.Title,
+				Description:   job.Description,
+				Type:          job.Type,
+				Rate:          job.Rate,
+				Amount:        job.Amount,
+				Length:        job.Length,
+				HoursPerWeek:  job.HoursPerWeek,
+				Openings:      job.Openings,
+				ClientID:      job.Client.Id,
+				Skills:        skillList,
+				ApplicationID: applicationID,
+				Bookmarked:    true,
+			},
+			Status:    status,
+			Available: status == "open",
+			CreatedAt: bookmark.CreatedAt,
+		})
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = bookmarkList
+	c.ServeJSON()
+}
+
+func (c *BookmarkController) DeleteJobBookmarkHandler() {
+	jobID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid job ID"}, false, false)
+		return
+	}
+
+	userID := c.Ctx.Input.GetData("id").(int)
+
+	deleted, err := models.DeleteJobBookmark(userID, jobID)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error removing bookmark"}, false, false)
+		return
+	}
+	if !deleted {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Bookmark not found"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": "Bookmark removed successfully"}
+	c.ServeJSON()
+}
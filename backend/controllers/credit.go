@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"backend/models"
+	"backend/types"
+	"backend/validators"
+	"net/http"
+	"strconv"
+
+	"github.com/beego/beego/v2/server/web"
+)
+
+type CreditController struct {
+	web.Controller
+}
+
+// GetCreditsHandler returns the freelancer's balance and credit ledger, newest first
+func (c *CreditController) GetCreditsHandler() {
+	userID := c.Ctx.Input.GetData("id").(int)
+	user, err := models.GetUserById(userID)
+	if user == nil || err != nil {
+		c.Ctx.Output.SetStatus(http.StatusUnauthorized)
+		c.Ctx.Output.JSON(map[string]string{"error": "User not found"}, false, false)
+		return
+	}
+
+	if user.Role != "freelancer" {
+		c.Ctx.Output.SetStatus(http.StatusForbidden)
+		c.Ctx.Output.JSON(map[string]string{"error": "Only freelancers have credits"}, false, false)
+		return
+	}
+
+	limit, err := c.GetInt("limit", 50)
+	if err != nil || limit <= 0 || limit > 100 {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Limit must be between 1 and 100"}, false, false)
+		return
+	}
+
+	offset, err := c.GetInt("offset", 0)
+	if err != nil || offset < 0 {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid offset"}, false, false)
+		return
+	}
+
+	balance, err := models.GetCreditBalance(userID)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching credits"}, false, false)
+		return
+	}
+
+	transactions, err := models.GetCreditTransactions(userID, limit, offset)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching credits"}, false, false)
+		return
+	}
+
+	response := types.CreditsResponse{
+		Balance:          balance,
+		MonthlyAllowance: web.AppConfig.DefaultInt("credits_monthly_allowance", 60),
+		Transactions:     []types.CreditTransactionInfo{},
+	}
+	for i := range transactions {
+		response.Transactions = append(response.Transactions, creditTransactionInfo(&transactions[i]))
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = response
+	c.ServeJSON()
+}
+
+// Admin function
+func (c *CreditController) GrantCreditsHandler() {
+
+	userID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid user ID"}, false, false)
+		return
+	}
+
+	grantCreditsRequest, err := validators.GrantCreditsValidator(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	user, err := models.GetUserById(userID)
+	if user == nil || err != nil {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "User not found"}, false, false)
+		return
+	}
+
+	if user.Role != "freelancer" {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Credits can only be granted to freelancers"}, false, false)
+		return
+	}
+
+	adminID := c.Ctx.Input.GetData("id").(int)
+	transaction, err := models.GrantCredits(user.Id, adminID, grantCreditsRequest.Amount, grantCreditsRequest.Note)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error granting credits"}, false, false)
+		return
+	}
+
+	models.CreateNotification(user.Id, "credits", "You received credits",
+		strconv.Itoa(grantCreditsRequest.Amount)+" credits were added to your balance.", "/user/freelancer/credits")
+
+	c.Ctx.Output.SetStatus(http.StatusCreated)
+	c.Data["json"] = creditTransactionInfo(transaction)
+	c.ServeJSON()
+}
+
+func creditTransactionInfo(transaction *models.CreditTransaction) types.CreditTransactionInfo {
+	info := types.CreditTransactionInfo{
+		ID:        transaction.Id,
+		Type:      transaction.Type,
+		Amount:    transaction.Amount,
+		Balance:   transaction.Balance,
+		Note:      transaction.Note,
+		CreatedAt: transaction.CreatedAt,
+	}
+	if transaction.Job != nil {
+		info.JobID = transaction.Job.Id
+	}
+	if transaction.Application != nil {
+		info.ApplicationID = transaction.Application.Id
+	}
+	return info
+}
@@ -57,6 +57,26 @@ func (c *FreelancerController) UpdateFreelancerDataHandler() {
 }
 
 func (c *FreelancerController) GetFreelancersHandler() {
+	skillID, err := c.GetInt("skill_id", 0)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid skill ID"}, false, false)
+		return
+	}
+	minYears, err := c.GetInt("min_years", 0)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid minimum years of experience"}, false, false)
+		return
+	}
+
+	skillFilter, err := validators.FreelancerSkillFilterValidator(skillID, c.GetString("min_level"), minYears)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
 	users, err := models.GetUsersByRole("freelancer")
 	if err != nil {
 		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
@@ -64,20 +84,48 @@ func (c *FreelancerController) GetFreelancersHandler() {
 		return
 	}
 
+	// Only keep freelancers having the skill at the requested level
+	var matchingIDs map[int]bool
+	if skillFilter.SkillID != 0 {
+		userIDs, err := models.GetFreelancerUserIDsBySkill(skillFilter.SkillID, skillFilter.MinLevel, skillFilter.MinYears)
+		if err != nil {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "Error fetching freelancers"}, false, false)
+			return
+		}
+		matchingIDs = make(map[int]bool, len(userIDs))
+		for _, userID := range userIDs {
+			matchingIDs[userID] = true
+		}
+	}
+
+	// Clients see which of their talent lists each freelancer is in
+	talentListIDs, err := models.GetTalentListIDsByFreelancer(c.Ctx.Input.GetData("id").(int))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching talent lists"}, false, false)
+		return
+	}
+
 	var freelancers []types.FreelancerInfo
 
 	for _, user := range users {
+		if matchingIDs != nil && !matchingIDs[user.Id] {
+			continue
+		}
+
 		freelancerData, err := models.GetFreelancerDataByUserID(user.Id)
 		if err != nil || freelancerData == nil {
 			continue
 		}
 
 		freelancerInfo := types.FreelancerInfo{
-			ID:         user.Id,
-			Name:       user.Name,
-			Surname:    user.Surname,
-			Title:      freelancerData.Title,
-			HourlyRate: freelancerData.HourlyRate,
+			ID:            user.Id,
+			Name:          user.Name,
+			Surname:       user.Surname,
+			Title:         freelancerData.Title,
+			HourlyRate:    freelancerData.HourlyRate,
+			TalentListIDs: talentListIDs[user.Id],
 		}
 
 		freelancers = append(freelancers, freelancerInfo)
@@ -120,13 +168,18 @@ func (c *FreelancerController) GetFreelancerHandler() {
 	freelancerData, err := models.GetFreelancerDataByUserID(user.Id)
 	if err == nil && freelancerData != nil {
 
-		var skillList []types.Skill
+		skillList, err := freelancerSkillList(freelancerData.Id)
+		if err != nil {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "Error fetching freelancer skills"}, false, false)
+			return
+		}
 
-		for _, skill := range freelancerData.Skills {
-			skillList = append(skillList, types.Skill{
-				Id:   skill.Id,
-				Name: skill.Name,
-			})
+		portfolio, err := portfolioList(user.Id)
+		if err != nil {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "Error fetching portfolio"}, false, false)
+			return
 		}
 
 		response.FreelancerData = &types.FreelancerData{
@@ -135,6 +188,7 @@ func (c *FreelancerController) GetFreelancerHandler() {
 			Skills:       skillList,
 			HourlyRate:   freelancerData.HourlyRate,
 			HoursPerWeek: freelancerData.HoursPerWeek,
+			Portfolio:    portfolio,
 		}
 	}
 
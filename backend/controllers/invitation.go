@@ -0,0 +1,289 @@
+package controllers
+
+import (
+	"backend/models"
+	"backend/types"
+	"backend/validators"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/beego/beego/v2/server/web"
+)
+
+type InvitationController struct {
+	web.Controller
+}
+
+func (c *InvitationController) InviteFreelancerHandler() {
+	jobID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid job ID"}, false, false)
+		return
+	}
+
+	userID := c.Ctx.Input.GetData("id").(int)
+	user, err := models.GetUserById(userID)
+	if user == nil || err != nil {
+		c.Ctx.Output.SetStatus(http.StatusUnauthorized)
+		c.Ctx.Output.JSON(map[string]string{"error": "User not found"}, false, false)
+		return
+	}
+
+	if user.Role != "client" {
+		c.Ctx.Output.SetStatus(http.StatusForbidden)
+		c.Ctx.Output.JSON(map[string]string{"error": "Only clients can invite freelancers"}, false, false)
+		return
+	}
+
+	job, err := models.GetJobByID(jobID)
+	if err != nil || job.Client.Id != user.Id {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Job not found"}, false, false)
+		return
+	}
+
+	if job.Status != "open" {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Freelancers can only be invited to open jobs"}, false, false)
+		return
+	}
+
+	jobInvitationRequest, err := validators.JobInvitationValidator(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	freelancer, err := models.GetUserById(jobInvitationRequest.FreelancerID)
+	if freelancer == nil || err != nil || freelancer.Role != "freelancer" || freelancer.Ban {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Freelancer not found"}, false, false)
+		return
+	}
+
+	if models.JobInvitationExists(job.Id, freelancer.Id) {
+		c.Ctx.Output.SetStatus(http.StatusConflict)
+		c.Ctx.Output.JSON(map[string]string{"error": "Freelancer is already invited to this job"}, false, false)
+		return
+	}
+
+	if models.ApplicationExists(freelancer.Id, job.Id) {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Freelancer has already applied to this job"}, false, false)
+		return
+	}
+
+	invitationID, err := models.CreateJobInvitation(job.Id, freelancer.Id, jobInvitationRequest.Message)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error inviting freelancer"}, false, false)
+		return
+	}
+
+	// The invitation is saved even when the notification fails, the freelancer still sees it in their invitations
+	models.CreateNotification(freelancer.Id, "job-invitation", "You are invited to apply to \""+job.Title+"\"",
+		jobInvitationRequest.Message, "/user/freelancer/invitations")
+
+	c.Ctx.Output.SetStatus(http.StatusCreated)
+	c.Data["json"] = map[string]interface{}{"message": "Freelancer invited successfully", "id": invitationID}
+	c.ServeJSON()
+}
+
+func (c *InvitationController) GetFreelancerInvitationsHandler() {
+	userID := c.Ctx.Input.GetData("id").(int)
+
+	invitations, err := models.GetJobInvitationsByFreelancerID(userID)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching invitations"}, false, false)
+		return
+	}
+
+	invitationList := []types.FreelancerInvitationInfo{}
+	for _, invitation := range invitations {
+		job := invitation.Job
+
+		skillList, err := jobSkillList(job.Id)
+		if err != nil {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "Error fetching job skills"}, false, false)
+			return
+		}
+
+		applicationID := 0
+		if invitation.Application != nil {
+			applicationID = invitation.Application.Id
+		}
+
+		invitationList = append(invitationList, types.FreelancerInvitationInfo{
+			ID: invitation.Id,
+			Job: types.JobInfo{
+				ID:            job.Id,
+				Title:         job.Title,
+				Description:   job.Description,
+				Type:          job.Type,
+				Rate:          job.Rate,
+				Amount:        job.Amount,
+				Length:        job.Length,
+				HoursPerWeek:  job.HoursPerWeek,
+				Openings:      job.Openings,
+				ClientID:      job.Client.Id,
+				Skills:        skillList,
+				ApplicationID: applicationID,
+			},
+			JobStatus:     job.Status,
+			Message:       invitation.Message,
+			Status:        invitation.Status,
+			ApplicationID: applicationID,
+			CreatedAt:     invitation.CreatedAt,
+			RespondedAt:   invitation.RespondedAt,
+		})
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = invitationList
+	c.ServeJSON()
+}
+
+// AcceptInvitationHandler accepts the invitation and applies to the job on the freelancer's behalf
+func (c *InvitationController) AcceptInvitationHandler() {
+	invitation, freelancer, ok := c.getOwnPendingInvitation()
+	if !ok {
+		return
+	}
+
+	acceptJobInvitationRequest, err := validators.AcceptJobInvitationValidator(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	job, err := models.GetJobByID(invitation.Job.Id)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Job not found"}, false, false)
+		return
+	}
+
+	if job.Status != "open" {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Job is not open for applications"}, false, false)
+		return
+	}
+
+	if models.ApplicationExists(freelancer.Id, job.Id) {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "You have already applied"}, false, false)
+		return
+	}
+
+	questions, err := screeningQuestionList(job.Id)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching screening questions"}, false, false)
+		return
+	}
+	if err := validators.ScreeningAnswersValidator(questions, acceptJobInvitationRequest.Answers); err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	invitation.Job = job
+	applicationID, err := models.AcceptJobInvitation(invitation, freelancer, acceptJobInvitationRequest.Description, acceptJobInvitationRequest.Answers)
+	if err != nil && err.Error() == "invitation already answered" {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invitation was already answered"}, false, false)
+		return
+	}
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error accepting invitation"}, false, false)
+		return
+	}
+
+	models.CreateNotification(job.Client.Id, "job-invitation", freelancer.Name+" "+freelancer.Surname+" accepted your invitation",
+		fmt.Sprintf("An application to \"%s\" was created.", job.Title), fmt.Sprintf("/user/client/jobs/%d", job.Id))
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]interface{}{"message": "Invitation accepted successfully", "application_id": applicationID}
+	c.ServeJSON()
+}
+
+func (c *InvitationController) DeclineInvitationHandler() {
+	invitation, freelancer, ok := c.getOwnPendingInvitation()
+	if !ok {
+		return
+	}
+
+	err := models.DeclineJobInvitation(invitation)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error declining invitation"}, false, false)
+		return
+	}
+
+	models.CreateNotification(invitation.Job.Client.Id, "job-invitation", freelancer.Name+" "+freelancer.Surname+" declined your invitation",
+		fmt.Sprintf("The invitation to \"%s\" was declined.", invitation.Job.Title), fmt.Sprintf("/user/client/jobs/%d", invitation.Job.Id))
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": "Invitation declined successfully"}
+	c.ServeJSON()
+}
+
+// getOwnPendingInvitation loads the invitation from the :id parameter and writes the error response
+// when it does not exist, belongs to another freelancer or was already answered
+func (c *InvitationController) getOwnPendingInvitation() (*models.JobInvitation, *models.User, bool) {
+	invitationID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid invitation ID"}, false, false)
+		return nil, nil, false
+	}
+
+	userID := c.Ctx.Input.GetData("id").(int)
+	user, err := models.GetUserById(userID)
+	if user == nil || err != nil {
+		c.Ctx.Output.SetStatus(http.StatusUnauthorized)
+		c.Ctx.Output.JSON(map[string]string{"error": "User not found"}, false, false)
+		return nil, nil, false
+	}
+
+	invitation, err := models.GetJobInvitationByID(invitationID)
+	if err != nil || invitation.Freelancer.Id != userID || invitation.Job.DeletedAt != nil {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invitation not found"}, false, false)
+		return nil, nil, false
+	}
+
+	if invitation.Status != "pending" {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invitation was already answered"}, false, false)
+		return nil, nil, false
+	}
+
+	return invitation, user, true
+}
+
+func jobInvitationInfo(invitation *models.JobInvitation) types.JobInvitationInfo {
+	applicationID := 0
+	if invitation.Application != nil {
+		applicationID = invitation.Application.Id
+	}
+
+	return types.JobInvitationInfo{
+		ID:                invitation.Id,
+		FreelancerID:      invitation.Freelancer.Id,
+		FreelancerName:    invitation.Freelancer.Name,
+		FreelancerSurname: invitation.Freelancer.Surname,
+		Message:           invitation.Message,
+		Status:            invitation.Status,
+		ApplicationID:     applicationID,
+		CreatedAt:         invitation.CreatedAt,
+		RespondedAt:       invitation.RespondedAt,
+	}
+}
@@ -1,12 +1,17 @@
 package controllers
 
 import (
+	"backend/jobstate"
 	"backend/models"
 	"backend/types"
 	"backend/validators"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/beego/beego/v2/client/orm"
 	"github.com/beego/beego/v2/server/web"
 )
 
@@ -15,6 +20,20 @@ type JobController struct {
 }
 
 func (c *JobController) GetJobsHandler() {
+	matching, err := c.GetBool("matching", false)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid matching flag"}, false, false)
+		return
+	}
+
+	searchFilter, err := validators.JobSearchQueryValidator(c.GetString("skill_ids"), c.GetString("rate"), c.GetString("min_amount"), c.GetString("max_amount"), c.GetString("q"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
 	jobs, err := models.GetOpenJobs()
 	if err != nil {
 		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
@@ -22,16 +41,74 @@ func (c *JobController) GetJobsHandler() {
 		return
 	}
 
+	// With ?matching=true freelancers only see jobs whose required skills they have at the minimum level
+	var matchingIDs map[int]bool
+	if matching {
+		freelancerData, err := models.GetFreelancerDataByUserID(c.Ctx.Input.GetData("id").(int))
+		if err != nil || freelancerData == nil {
+			c.Ctx.Output.SetStatus(http.StatusForbidden)
+			c.Ctx.Output.JSON(map[string]string{"error": "Only freelancers can filter matching jobs"}, false, false)
+			return
+		}
+
+		jobIDs, err := models.GetMatchingOpenJobIDs(freelancerData.Id)
+		if err != nil {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "Error fetching jobs"}, false, false)
+			return
+		}
+		matchingIDs = make(map[int]bool, len(jobIDs))
+		for _, jobID := range jobIDs {
+			matchingIDs[jobID] = true
+		}
+	}
+
+	userID := c.Ctx.Input.GetData("id").(int)
+	user, err := models.GetUserById(userID)
+	if user == nil || err != nil {
+		c.Ctx.Output.SetStatus(http.StatusUnauthorized)
+		c.Ctx.Output.JSON(map[string]string{"error": "User not found"}, false, false)
+		return
+	}
+
+	viewer, err := models.NewJobViewer(user)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching jobs"}, false, false)
+		return
+	}
+
+	bookmarkedIDs, err := models.GetBookmarkedJobIDs(userID)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching bookmarks"}, false, false)
+		return
+	}
+
 	var jobList []types.JobInfo
 
 	for _, job := range jobs {
-		var skillList []types.Skill
+		if matchingIDs != nil && !matchingIDs[job.Id] {
+			continue
+		}
+		// Invite-only and restricted jobs are only listed for the invited and eligible freelancers
+		if !viewer.CanView(&job) {
+			continue
+		}
 
+		jobSkillIDs := make([]int, 0, len(job.Skills))
 		for _, skill := range job.Skills {
-			skillList = append(skillList, types.Skill{
-				Id:   skill.Id,
-				Name: skill.Name,
-			})
+			jobSkillIDs = append(jobSkillIDs, skill.Id)
+		}
+		if !models.JobMatchesSearch(&job, jobSkillIDs, searchFilter) {
+			continue
+		}
+
+		skillList, err := jobSkillList(job.Id)
+		if err != nil {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "Error fetching job skills"}, false, false)
+			return
 		}
 
 		jobInfo := types.JobInfo{
@@ -43,8 +120,11 @@ func (c *JobController) GetJobsHandler() {
 			Amount:       job.Amount,
 			Length:       job.Length,
 			HoursPerWeek: job.HoursPerWeek,
+			Openings:     job.Openings,
 			ClientID:     job.Client.Id,
 			Skills:       skillList,
+			Bookmarked:   bookmarkedIDs[job.Id],
+			ExpiresAt:    job.ExpiresAt,
 		}
 
 		jobList = append(jobList, jobInfo)
@@ -73,7 +153,7 @@ func (c *JobController) GetJobHandler() {
 	}
 
 	job, err := models.GetJobByID(jobID)
-	if err != nil {
+	if err != nil || !models.CanViewJob(job, user) {
 		c.Ctx.Output.SetStatus(http.StatusNotFound)
 		c.Ctx.Output.JSON(map[string]string{"error": "Job not found"}, false, false)
 		return
@@ -107,26 +187,45 @@ func (c *JobController) GetJobHandler() {
 		}
 	}
 
-	var skillList []types.Skill
-	for _, skill := range job.Skills {
-		skillList = append(skillList, types.Skill{
-			Id:   skill.Id,
-			Name: skill.Name,
-		})
+	skillList, err := jobSkillList(job.Id)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching job skills"}, false, false)
+		return
+	}
+
+	questions, err := screeningQuestionList(job.Id)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching screening questions"}, false, false)
+		return
+	}
+
+	attachments, err := models.GetAttachmentsByJobID(job.Id)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching attachments"}, false, false)
+		return
 	}
 
 	jobInfo := types.JobInfo{
-		ID:            job.Id,
-		Title:         job.Title,
-		Description:   job.Description,
-		Type:          job.Type,
-		Rate:          job.Rate,
-		Amount:        job.Amount,
-		Length:        job.Length,
-		HoursPerWeek:  job.HoursPerWeek,
-		ClientID:      job.Client.Id,
-		Skills:        skillList,
-		ApplicationID: applicationID,
+		ID:                 job.Id,
+		Title:              job.Title,
+		Description:        job.Description,
+		Type:               job.Type,
+		Rate:               job.Rate,
+		Amount:             job.Amount,
+		Length:             job.Length,
+		HoursPerWeek:       job.HoursPerWeek,
+		Openings:           job.Openings,
+		ClientID:           job.Client.Id,
+		Skills:             skillList,
+		ApplicationID:      applicationID,
+		Bookmarked:         models.JobBookmarkExists(userID, job.Id),
+		ExpiresAt:          job.ExpiresAt,
+		ScreeningQuestions: questions,
+		CreditCost:         models.ApplicationCreditCost(job),
+		Attachments:        attachmentList(attachments),
 	}
 
 	c.Ctx.Output.SetStatus(http.StatusOK)
@@ -157,8 +256,31 @@ func (c *JobController) CreateJobHandler() {
 		return
 	}
 
-	err = models.CreateJob(user, createJobRequest.Title, createJobRequest.Description, createJobRequest.Type, createJobRequest.Rate, createJobRequest.Length, createJobRequest.HoursPerWeek, createJobRequest.Amount, createJobRequest.Skills)
+	if createJobRequest.RestrictedSkillID != 0 {
+		if _, err := models.GetSkillById(createJobRequest.RestrictedSkillID); err != nil {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Ctx.Output.JSON(map[string]string{"error": "Restricted skill not found"}, false, false)
+			return
+		}
+	}
+
+	files, err := attachmentFiles(user.Id, createJobRequest.Files, nil, attachmentLimits())
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	attachments, err := storeAttachmentFiles(files)
 	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Failed to save attachment"}, false, false)
+		return
+	}
+
+	_, err = models.CreateJob(user, createJobRequest.Title, createJobRequest.Description, createJobRequest.Type, createJobRequest.Rate, createJobRequest.Length, createJobRequest.HoursPerWeek, createJobRequest.Amount, createJobRequest.Openings, createJobRequest.Skills, createJobRequest.ScreeningQuestions, createJobRequest.ExposedStages, &createJobRequest.JobVisibility, &createJobRequest.JobSchedule, attachments)
+	if err != nil {
+		deleteStoredFiles(attachments)
 		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
 		c.Ctx.Output.JSON(map[string]string{"error": "Error creating job"}, false, false)
 		return
@@ -195,9 +317,9 @@ func (c *JobController) UpdateClientJobHandler() {
 		return
 	}
 
-	if job.Status != "open" {
+	if job.Status != "open" && job.Status != "draft" && job.Status != "scheduled" {
 		c.Ctx.Output.SetStatus(http.StatusNotFound)
-		c.Ctx.Output.JSON(map[string]string{"error": "It is only possible to update open jobs"}, false, false)
+		c.Ctx.Output.JSON(map[string]string{"error": "It is only possible to update open, draft or scheduled jobs"}, false, false)
 		return
 	}
 
@@ -234,8 +356,60 @@ func (c *JobController) UpdateClientJobHandler() {
 	if updateJobRequest.HoursPerWeek != "" {
 		job.HoursPerWeek = updateJobRequest.HoursPerWeek
 	}
+	if updateJobRequest.Openings != 0 {
+		hired, err := models.CountJobAssignments(job.Id)
+		if err != nil {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "Error fetching hired freelancers"}, false, false)
+			return
+		}
+		// An open job needs an opening left, filling the last one starts the work
+		if updateJobRequest.Openings <= hired {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Ctx.Output.JSON(map[string]string{"error": "Openings must be more than the freelancers already hired"}, false, false)
+			return
+		}
+		job.Openings = updateJobRequest.Openings
+	}
+	if updateJobRequest.ScreeningQuestions != nil {
+		// Answers already given would no longer match the questions
+		applicationCount, err := models.GetApplicationCountForJob(job.Id)
+		if err != nil {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "Error fetching application count"}, false, false)
+			return
+		}
+		if applicationCount > 0 {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Ctx.Output.JSON(map[string]string{"error": "Screening questions cannot be changed after the job received applications"}, false, false)
+			return
+		}
+	}
+	if updateJobRequest.Visibility != "" {
+		if updateJobRequest.RestrictedSkillID != 0 {
+			if _, err := models.GetSkillById(updateJobRequest.RestrictedSkillID); err != nil {
+				c.Ctx.Output.SetStatus(http.StatusBadRequest)
+				c.Ctx.Output.JSON(map[string]string{"error": "Restricted skill not found"}, false, false)
+				return
+			}
+		}
+		job.SetVisibility(&updateJobRequest.JobVisibility)
+	}
+	if updateJobRequest.ExposedStages != nil {
+		job.SetExposedStages(*updateJobRequest.ExposedStages)
+	}
 
-	err = models.UpdateJobWithSkills(job, updateJobRequest.Skills)
+	err = models.UpdateJobWithSkills(job, updateJobRequest.Skills, updateJobRequest.ScreeningQuestions)
+	if err != nil && err.Error() == "job cannot be edited" {
+		c.Ctx.Output.SetStatus(http.StatusConflict)
+		c.Ctx.Output.JSON(map[string]string{"error": "The job is no longer open, draft or scheduled"}, false, false)
+		return
+	}
+	if err != nil && err.Error() == "openings already filled" {
+		c.Ctx.Output.SetStatus(http.StatusConflict)
+		c.Ctx.Output.JSON(map[string]string{"error": "Openings must be more than the freelancers already hired"}, false, false)
+		return
+	}
 	if err != nil {
 		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
 		c.Ctx.Output.JSON(map[string]string{"error": "Error updating job"}, false, false)
@@ -272,9 +446,17 @@ func (c *JobController) DeleteClientJobHandler() {
 		return
 	}
 
-	if job.Status != "open" {
+	hired, err := models.CountJobAssignments(job.Id)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching hired freelancers"}, false, false)
+		return
+	}
+
+	// Jobs with hired freelancers are still being worked on until they are cancelled
+	if job.Status == "in-progress" || job.Status == "completed" || (hired > 0 && job.Status != "cancelled") {
 		c.Ctx.Output.SetStatus(http.StatusNotFound)
-		c.Ctx.Output.JSON(map[string]string{"error": "It is only possible to delete open jobs"}, false, false)
+		c.Ctx.Output.JSON(map[string]string{"error": "It is only possible to delete jobs that are not in progress or completed"}, false, false)
 		return
 	}
 
@@ -322,17 +504,18 @@ func (c *JobController) GetClientJobsHandler() {
 
 	var jobList []types.ClientJobInfo
 	for _, job := range jobs {
-		var skillList []types.Skill
-		for _, skill := range job.Skills {
-			skillList = append(skillList, types.Skill{
-				Id:   skill.Id,
-				Name: skill.Name,
-			})
+		skillList, err := jobSkillList(job.Id)
+		if err != nil {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "Error fetching job skills"}, false, false)
+			return
 		}
 
-		freelancerID := 0
-		if job.Freelancer != nil {
-			freelancerID = job.Freelancer.Id
+		freelancerIDs, err := models.GetJobFreelancerIDs(job.Id)
+		if err != nil {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "Error fetching hired freelancers"}, false, false)
+			return
 		}
 
 		applicationCount, err := models.GetApplicationCountForJob(job.Id)
@@ -351,11 +534,15 @@ func (c *JobController) GetClientJobsHandler() {
 			Amount:           job.Amount,
 			Length:           job.Length,
 			HoursPerWeek:     job.HoursPerWeek,
+			Openings:         job.Openings,
 			Status:           job.Status,
 			ClientID:         job.Client.Id,
-			FreelancerID:     freelancerID,
+			FreelancerIDs:    freelancerIDs,
+			JobVisibility:    job.VisibilityInfo(),
 			Skills:           skillList,
 			ApplicationCount: applicationCount,
+			PublishAt:        job.PublishAt,
+			ExpiresAt:        job.ExpiresAt,
 		}
 
 		jobList = append(jobList, jobInfo)
@@ -396,73 +583,129 @@ func (c *JobController) GetClientJobHandler() {
 		return
 	}
 
-	freelancerID := 0
-	if job.Freelancer != nil {
-		freelancerID = job.Freelancer.Id
+	freelancerIDs, err := models.GetJobFreelancerIDs(job.Id)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching hired freelancers"}, false, false)
+		return
 	}
 
-	var skillList []types.Skill
-	for _, skill := range job.Skills {
-		skillList = append(skillList, types.Skill{
-			Id:   skill.Id,
-			Name: skill.Name,
-		})
+	skillList, err := jobSkillList(job.Id)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching job skills"}, false, false)
+		return
 	}
 
-	applications, err := models.GetApplicationsByJobID(jobID)
+	questions, err := screeningQuestionList(job.Id)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching screening questions"}, false, false)
+		return
+	}
+
+	// ?answer_<question id>=... keeps the applicants with matching answers
+	answerFilters, err := validators.ScreeningAnswerFilterValidator(c.Ctx.Request.URL.Query(), questions)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	// ?q=... keeps the applicants whose description or attachments contain every keyword
+	keywords, err := validators.ApplicationKeywordsValidator(c.GetString("q"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	applicationList, err := clientApplicationList(jobID, questions, answerFilters, keywords)
 	if err != nil {
 		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
 		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching applications"}, false, false)
 		return
 	}
 
-	var applicationList []types.Application
-	for _, application := range applications {
+	invitations, err := models.GetJobInvitationsByJobID(jobID)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching invitations"}, false, false)
+		return
+	}
 
-		attachment, err := models.GetAttachmentByApplicationID(application.Id)
-		if err != nil {
-			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
-			c.Ctx.Output.JSON(map[string]string{"error": "Error fetching attachment"}, false, false)
-			return
-		}
+	invitationList := []types.JobInvitationInfo{}
+	for i := range invitations {
+		invitationList = append(invitationList, jobInvitationInfo(&invitations[i]))
+	}
 
-		var attachmentInfo *types.Attachment
-		if attachment != nil {
-			attachmentInfo = &types.Attachment{
-				ID:            attachment.Id,
-				ApplicationID: attachment.Application.Id,
-				FileName:      attachment.FileName,
-				FilePath:      attachment.FilePath,
-				CreatedAt:     attachment.CreatedAt,
-			}
+	cancellations, err := models.GetCancellationRequestsByJobID(jobID)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching cancellation requests"}, false, false)
+		return
+	}
+
+	cancellationList := []types.CancellationRequestInfo{}
+	for i := range cancellations {
+		cancellationList = append(cancellationList, cancellationRequestInfo(&cancellations[i]))
+	}
+
+	history, err := models.GetJobStatusHistory(jobID)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching status history"}, false, false)
+		return
+	}
+
+	historyList := []types.JobStatusHistoryInfo{}
+	for _, change := range history {
+		actorID := 0
+		if change.Actor != nil {
+			actorID = change.Actor.Id
 		}
 
-		applicationList = append(applicationList, types.Application{
-			ID:              application.Id,
-			UserID:          application.User.Id,
-			JobID:           application.Job.Id,
-			Description:     application.Description,
-			RejectionReason: application.RejectionReason,
-			Status:          application.Status,
-			CreatedAt:       application.CreatedAt,
-			Attachment:      attachmentInfo,
+		historyList = append(historyList, types.JobStatusHistoryInfo{
+			FromStatus: change.FromStatus,
+			ToStatus:   change.ToStatus,
+			Event:      change.Event,
+			ActorID:    actorID,
+			Reason:     change.Reason,
+			CreatedAt:  change.CreatedAt,
 		})
 	}
 
+	attachments, err := models.GetAttachmentsByJobID(jobID)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching attachments"}, false, false)
+		return
+	}
+
 	jobInfo := types.ClientJobDetailedInfo{
-		ID:           job.Id,
-		Title:        job.Title,
-		Description:  job.Description,
-		Type:         job.Type,
-		Rate:         job.Rate,
-		Amount:       job.Amount,
-		Length:       job.Length,
-		HoursPerWeek: job.HoursPerWeek,
-		Status:       job.Status,
-		ClientID:     job.Client.Id,
-		FreelancerID: freelancerID,
-		Skills:       skillList,
-		Applications: applicationList,
+		ID:                   job.Id,
+		Title:                job.Title,
+		Description:          job.Description,
+		Type:                 job.Type,
+		Rate:                 job.Rate,
+		Amount:               job.Amount,
+		Length:               job.Length,
+		HoursPerWeek:         job.HoursPerWeek,
+		Openings:             job.Openings,
+		Status:               job.Status,
+		ClientID:             job.Client.Id,
+		FreelancerIDs:        freelancerIDs,
+		JobVisibility:        job.VisibilityInfo(),
+		Skills:               skillList,
+		Applications:         applicationList,
+		Invitations:          invitationList,
+		ScreeningQuestions:   questions,
+		ExposedStages:        job.ExposedStageList(),
+		CancellationRequests: cancellationList,
+		StatusHistory:        historyList,
+		Attachments:          attachmentList(attachments),
+		PublishAt:            job.PublishAt,
+		ExpiresAt:            job.ExpiresAt,
 	}
 
 	c.Ctx.Output.SetStatus(http.StatusOK)
@@ -495,12 +738,11 @@ func (c *JobController) GetFreelancerJobsHandler() {
 
 	var jobList []types.FreelancerJobInfo
 	for _, job := range jobs {
-		var skillList []types.Skill
-		for _, skill := range job.Skills {
-			skillList = append(skillList, types.Skill{
-				Id:   skill.Id,
-				Name: skill.Name,
-			})
+		skillList, err := jobSkillList(job.Id)
+		if err != nil {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "Error fetching job skills"}, false, false)
+			return
 		}
 
 		application, err := models.GetApplicationByUserAndJob(userID, job.Id)
@@ -519,6 +761,7 @@ func (c *JobController) GetFreelancerJobsHandler() {
 			Amount:        job.Amount,
 			Length:        job.Length,
 			HoursPerWeek:  job.HoursPerWeek,
+			Openings:      job.Openings,
 			Status:        job.Status,
 			ClientID:      job.Client.Id,
 			Skills:        skillList,
@@ -559,18 +802,17 @@ func (c *JobController) GetFreelancerJobHandler() {
 
 	job, err := models.GetJobByID(jobID)
 
-	if err != nil || job.Freelancer == nil || job.Freelancer.Id != user.Id {
+	if err != nil || !models.JobAssignmentExists(job.Id, user.Id) {
 		c.Ctx.Output.SetStatus(http.StatusNotFound)
 		c.Ctx.Output.JSON(map[string]string{"error": "Job not found"}, false, false)
 		return
 	}
 
-	var skillList []types.Skill
-	for _, skill := range job.Skills {
-		skillList = append(skillList, types.Skill{
-			Id:   skill.Id,
-			Name: skill.Name,
-		})
+	skillList, err := jobSkillList(job.Id)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching job skills"}, false, false)
+		return
 	}
 
 	application, err := models.GetApplicationByUserAndJob(userID, job.Id)
@@ -589,6 +831,7 @@ func (c *JobController) GetFreelancerJobHandler() {
 		Amount:        job.Amount,
 		Length:        job.Length,
 		HoursPerWeek:  job.HoursPerWeek,
+		Openings:      job.Openings,
 		Status:        job.Status,
 		ClientID:      job.Client.Id,
 		Skills:        skillList,
@@ -655,7 +898,7 @@ func (c *JobController) CompleteJobHandler() {
 		return
 	}
 
-	if job.Status != "in-progress" {
+	if jobstate.Can(job, jobstate.Complete) != nil {
 		c.Ctx.Output.SetStatus(http.StatusNotFound)
 		c.Ctx.Output.JSON(map[string]string{"error": "It is only possible to complete 'in-progress' jobs"}, false, false)
 		return
@@ -667,9 +910,12 @@ func (c *JobController) CompleteJobHandler() {
 		return
 	}
 
-	job.Status = "completed"
-
-	err = models.UpdateJob(job)
+	err = jobstate.Fire(job, jobstate.Change{Event: jobstate.Complete, ActorID: user.Id})
+	if errors.Is(err, jobstate.ErrNotAllowed) {
+		c.Ctx.Output.SetStatus(http.StatusConflict)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
 	if err != nil {
 		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
 		c.Ctx.Output.JSON(map[string]string{"error": "Error updating job"}, false, false)
@@ -681,3 +927,504 @@ func (c *JobController) CompleteJobHandler() {
 	c.ServeJSON()
 
 }
+
+// Admin function
+func (c *JobController) GetDeletedJobsHandler() {
+
+	jobs, err := models.GetDeletedJobs()
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching jobs"}, false, false)
+		return
+	}
+
+	var jobList []types.ClientJobInfo
+	for _, job := range jobs {
+		freelancerIDs, err := models.GetJobFreelancerIDs(job.Id)
+		if err != nil {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "Error fetching hired freelancers"}, false, false)
+			return
+		}
+
+		jobList = append(jobList, types.ClientJobInfo{
+			ID:            job.Id,
+			Title:         job.Title,
+			Description:   job.Description,
+			Type:          job.Type,
+			Rate:          job.Rate,
+			Amount:        job.Amount,
+			Length:        job.Length,
+			HoursPerWeek:  job.HoursPerWeek,
+			Openings:      job.Openings,
+			Status:        job.Status,
+			ClientID:      job.Client.Id,
+			FreelancerIDs: freelancerIDs,
+			JobVisibility: job.VisibilityInfo(),
+			DeletedAt:     job.DeletedAt,
+		})
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = jobList
+	c.ServeJSON()
+}
+
+// Admin function
+func (c *JobController) RestoreJobHandler() {
+
+	jobID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid job ID"}, false, false)
+		return
+	}
+
+	err = models.RestoreJobByID(jobID)
+	if err != nil {
+		if err == orm.ErrNoRows {
+			c.Ctx.Output.SetStatus(http.StatusNotFound)
+			c.Ctx.Output.JSON(map[string]string{"error": "Job not found"}, false, false)
+		} else if err.Error() == "job is not deleted" {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Ctx.Output.JSON(map[string]string{"error": "Job is not deleted"}, false, false)
+		} else if err.Error() == "job client is deleted" {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Ctx.Output.JSON(map[string]string{"error": "Restore the client before restoring their job"}, false, false)
+		} else {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "Error restoring job"}, false, false)
+		}
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": "Job restored successfully"}
+	c.ServeJSON()
+}
+
+// PublishJobHandler publishes a draft job now or schedules it, and can reschedule a scheduled job
+func (c *JobController) PublishJobHandler() {
+	job, ok := c.getOwnClientJob()
+	if !ok {
+		return
+	}
+
+	if jobstate.Can(job, jobstate.Publish) != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Only draft or scheduled jobs can be published"}, false, false)
+		return
+	}
+
+	publishJobRequest, err := validators.PublishJobValidator(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	job.PublishAt = publishJobRequest.PublishAt
+	if publishJobRequest.ExpiresAt != nil {
+		job.ExpiresAt = publishJobRequest.ExpiresAt
+	}
+	// A draft can keep the expiry it was created with, as long as it is still ahead
+	if job.ExpiresAt != nil && (!job.ExpiresAt.After(time.Now()) || (job.PublishAt != nil && !job.ExpiresAt.After(*job.PublishAt))) {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "expiry must be in the future and after the publish time"}, false, false)
+		return
+	}
+
+	// A publish time in the future schedules the job instead
+	event := jobstate.Publish
+	if job.PublishAt != nil && job.PublishAt.After(time.Now()) {
+		event = jobstate.Schedule
+	}
+
+	err = jobstate.Fire(job, jobstate.Change{Event: event, ActorID: job.Client.Id})
+	if errors.Is(err, jobstate.ErrNotAllowed) {
+		c.Ctx.Output.SetStatus(http.StatusConflict)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error publishing job"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": "Job published successfully", "status": job.Status}
+	c.ServeJSON()
+}
+
+// ExtendJobHandler moves the expiry of an open or scheduled job, an empty expiry keeps the job open
+func (c *JobController) ExtendJobHandler() {
+	job, ok := c.getOwnClientJob()
+	if !ok {
+		return
+	}
+
+	if job.Status != "open" && job.Status != "scheduled" {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Only open or scheduled jobs can be extended"}, false, false)
+		return
+	}
+
+	jobExpiryRequest, err := validators.JobExpiryValidator(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	if jobExpiryRequest.ExpiresAt != nil && job.PublishAt != nil && !jobExpiryRequest.ExpiresAt.After(*job.PublishAt) {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "expiry must be after the publish time"}, false, false)
+		return
+	}
+
+	err = models.ExtendJob(job, jobExpiryRequest.ExpiresAt)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error extending job"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": "Job extended successfully"}
+	c.ServeJSON()
+}
+
+// RepublishJobHandler opens an expired job again
+func (c *JobController) RepublishJobHandler() {
+	job, ok := c.getOwnClientJob()
+	if !ok {
+		return
+	}
+
+	if jobstate.Can(job, jobstate.Republish) != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Only expired jobs can be republished"}, false, false)
+		return
+	}
+
+	jobExpiryRequest, err := validators.JobExpiryValidator(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	job.ExpiresAt = jobExpiryRequest.ExpiresAt
+	err = jobstate.Fire(job, jobstate.Change{Event: jobstate.Republish, ActorID: job.Client.Id})
+	if errors.Is(err, jobstate.ErrNotAllowed) {
+		c.Ctx.Output.SetStatus(http.StatusConflict)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error republishing job"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": "Job republished successfully"}
+	c.ServeJSON()
+}
+
+func (c *JobController) CancelJobHandler() {
+	c.changeJobStatus(jobstate.Cancel, "Job cancelled successfully")
+}
+
+func (c *JobController) PauseJobHandler() {
+	c.changeJobStatus(jobstate.Pause, "Job paused successfully")
+}
+
+func (c *JobController) ResumeJobHandler() {
+	c.changeJobStatus(jobstate.Resume, "Job resumed successfully")
+}
+
+// ReopenJobHandler opens the job for applications again, removing the freelancer from the body
+// or every hired freelancer
+func (c *JobController) ReopenJobHandler() {
+	c.changeJobStatus(jobstate.Reopen, "Job reopened successfully")
+}
+
+// changeJobStatus fires the event on the client's job from the :id parameter, with the optional reason from the body
+func (c *JobController) changeJobStatus(event, message string) {
+	job, ok := c.getOwnClientJob()
+	if !ok {
+		return
+	}
+
+	jobStatusChangeRequest, err := validators.JobStatusChangeValidator(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	if err := jobstate.Can(job, event); err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	change := jobstate.Change{Event: event, ActorID: job.Client.Id, Reason: jobStatusChangeRequest.Reason}
+	if event == jobstate.Reopen && jobStatusChangeRequest.FreelancerID != 0 {
+		if !models.JobAssignmentExists(job.Id, jobStatusChangeRequest.FreelancerID) {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Ctx.Output.JSON(map[string]string{"error": "Freelancer is not hired for this job"}, false, false)
+			return
+		}
+		change.FreelancerID = jobStatusChangeRequest.FreelancerID
+	}
+
+	err = jobstate.Fire(job, change)
+	if errors.Is(err, jobstate.ErrNotAllowed) {
+		c.Ctx.Output.SetStatus(http.StatusConflict)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error changing job status"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": message, "status": job.Status}
+	c.ServeJSON()
+}
+
+// GetJobPipelineHandler lists the applications of the job grouped by stage,
+// it takes the same answer and keyword filters as GetClientJobHandler
+func (c *JobController) GetJobPipelineHandler() {
+
+	job, ok := c.getOwnClientJob()
+	if !ok {
+		return
+	}
+
+	questions, err := screeningQuestionList(job.Id)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching screening questions"}, false, false)
+		return
+	}
+
+	answerFilters, err := validators.ScreeningAnswerFilterValidator(c.Ctx.Request.URL.Query(), questions)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	keywords, err := validators.ApplicationKeywordsValidator(c.GetString("q"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	applicationList, err := clientApplicationList(job.Id, questions, answerFilters, keywords)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching applications"}, false, false)
+		return
+	}
+
+	byStage := make(map[string][]types.Application)
+	for _, application := range applicationList {
+		byStage[application.Status] = append(byStage[application.Status], application)
+	}
+
+	pipeline := []types.PipelineStage{}
+	for _, stage := range models.ApplicationStages {
+		applications := byStage[stage]
+		if applications == nil {
+			applications = []types.Application{}
+		}
+		pipeline = append(pipeline, types.PipelineStage{Stage: stage, Applications: applications})
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = pipeline
+	c.ServeJSON()
+}
+
+// getOwnClientJob loads the job from the :id parameter and writes the error response
+// when it does not exist or belongs to another client
+func (c *JobController) getOwnClientJob() (*models.Job, bool) {
+	jobID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid job ID"}, false, false)
+		return nil, false
+	}
+
+	userID := c.Ctx.Input.GetData("id").(int)
+	job, err := models.GetJobByID(jobID)
+	if err != nil || job.Client.Id != userID {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Job not found"}, false, false)
+		return nil, false
+	}
+
+	return job, true
+}
+
+func screeningQuestionList(jobID int) ([]types.ScreeningQuestion, error) {
+	questions, err := models.GetScreeningQuestions(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	questionList := []types.ScreeningQuestion{}
+	for i := range questions {
+		questionList = append(questionList, types.ScreeningQuestion{
+			ID:       questions[i].Id,
+			Type:     questions[i].Type,
+			Question: questions[i].Question,
+			Options:  questions[i].OptionList(),
+			Required: questions[i].Required,
+		})
+	}
+
+	return questionList, nil
+}
+
+// clientApplicationList returns the applications to the job as the client sees them, with their answers,
+// attachments and private notes, keeping those that pass the answer filters and contain every keyword in
+// their description or attachment text, with the attachments highlighting the keywords
+func clientApplicationList(jobID int, questions []types.ScreeningQuestion, answerFilters []types.ScreeningAnswerFilter, keywords []string) ([]types.Application, error) {
+	applications, err := models.GetApplicationsByJobID(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	answers, err := models.GetScreeningAnswersByJobID(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	var applicationList []types.Application
+	for _, application := range applications {
+		answerList := screeningAnswerList(answers[application.Id])
+		if !matchesScreeningFilters(questions, answerList, answerFilters) {
+			continue
+		}
+
+		attachments, err := models.GetAttachmentsByApplicationID(application.Id)
+		if err != nil {
+			return nil, err
+		}
+		if !matchesKeywords(&application, attachments, keywords) {
+			continue
+		}
+		attachmentInfos := attachmentList(attachments)
+		if len(keywords) > 0 {
+			for i := range attachmentInfos {
+				attachmentInfos[i].Highlights = textHighlights(attachments[i].Text, keywords)
+			}
+		}
+
+		applicationList = append(applicationList, types.Application{
+			ID:               application.Id,
+			UserID:           application.User.Id,
+			JobID:            application.Job.Id,
+			Description:      application.Description,
+			RejectionReason:  application.RejectionReason,
+			Status:           application.Status,
+			CreatedAt:        application.CreatedAt,
+			Attachment:       firstAttachment(attachmentInfos),
+			Attachments:      attachmentInfos,
+			Answers:          answerList,
+			Notes:            application.Notes,
+			WithdrawalReason: application.WithdrawalReason,
+			WithdrawnAt:      application.WithdrawnAt,
+		})
+	}
+
+	return applicationList, nil
+}
+
+func screeningAnswerList(answers []models.ScreeningAnswer) []types.ScreeningAnswer {
+	var answerList []types.ScreeningAnswer
+	for _, answer := range answers {
+		answerList = append(answerList, types.ScreeningAnswer{
+			QuestionID: answer.Question.Id,
+			Question:   answer.Question.Question,
+			Answer:     answer.Answer,
+		})
+	}
+	return answerList
+}
+
+// matchesKeywords reports whether every keyword appears in the description or the text of an attachment
+func matchesKeywords(application *models.Application, attachments []models.Attachment, keywords []string) bool {
+	if len(keywords) == 0 {
+		return true
+	}
+
+	texts := []string{application.Description}
+	for _, attachment := range attachments {
+		texts = append(texts, attachment.Text)
+	}
+	text := strings.ToLower(strings.Join(texts, "\n"))
+
+	for _, keyword := range keywords {
+		if !strings.Contains(text, keyword) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesScreeningFilters reports whether the answers pass every filter. Text answers only have to
+// contain the filter value, number answers are compared as numbers and the other answers must be equal.
+func matchesScreeningFilters(questions []types.ScreeningQuestion, answers []types.ScreeningAnswer, filters []types.ScreeningAnswerFilter) bool {
+	questionTypes := make(map[int]string, len(questions))
+	for _, question := range questions {
+		questionTypes[question.ID] = question.Type
+	}
+	answersByQuestion := make(map[int]string, len(answers))
+	for _, answer := range answers {
+		answersByQuestion[answer.QuestionID] = answer.Answer
+	}
+
+	for _, filter := range filters {
+		answer, ok := answersByQuestion[filter.QuestionID]
+		if !ok {
+			return false
+		}
+
+		switch questionTypes[filter.QuestionID] {
+		case "text":
+			if !strings.Contains(strings.ToLower(answer), strings.ToLower(filter.Value)) {
+				return false
+			}
+		case "number":
+			number, err := strconv.ParseFloat(answer, 64)
+			if err != nil {
+				return false
+			}
+			if filter.Value != "" {
+				value, err := strconv.ParseFloat(filter.Value, 64)
+				if err != nil || number != value {
+					return false
+				}
+			}
+			if (filter.Min != nil && number < *filter.Min) || (filter.Max != nil && number > *filter.Max) {
+				return false
+			}
+		default:
+			if !strings.EqualFold(answer, filter.Value) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
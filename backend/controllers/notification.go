@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"backend/models"
+	"backend/types"
+	"backend/validators"
+	"net/http"
+
+	"github.com/beego/beego/v2/server/web"
+)
+
+type NotificationController struct {
+	web.Controller
+}
+
+func (c *NotificationController) GetNotificationsHandler() {
+	userID := c.Ctx.Input.GetData("id").(int)
+
+	unreadOnly, err := c.GetBool("unread", false)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid unread flag"}, false, false)
+		return
+	}
+
+	notifications, err := models.GetNotificationsByUserID(userID, unreadOnly)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching notifications"}, false, false)
+		return
+	}
+
+	unreadCount, err := models.GetUnreadNotificationCount(userID)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching notifications"}, false, false)
+		return
+	}
+
+	response := types.NotificationsResponse{
+		UnreadCount:   unreadCount,
+		Notifications: []types.NotificationInfo{},
+	}
+	for _, notification := range notifications {
+		response.Notifications = append(response.Notifications, types.NotificationInfo{
+			ID:        notification.Id,
+			Type:      notification.Type,
+			Title:     notification.Title,
+			Message:   notification.Message,
+			Link:      notification.Link,
+			ReadAt:    notification.ReadAt,
+			CreatedAt: notification.CreatedAt,
+		})
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = response
+	c.ServeJSON()
+}
+
+func (c *NotificationController) MarkNotificationsReadHandler() {
+	userID := c.Ctx.Input.GetData("id").(int)
+
+	markReadRequest, err := validators.MarkNotificationsReadValidator(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	err = models.MarkNotificationsRead(userID, markReadRequest.IDs...)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error updating notifications"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": "Notifications marked as read"}
+	c.ServeJSON()
+}
@@ -0,0 +1,242 @@
+package controllers
+
+import (
+	"backend/jobstate"
+	"backend/models"
+	"backend/types"
+	"backend/validators"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/beego/beego/v2/client/orm"
+	"github.com/beego/beego/v2/server/web"
+)
+
+// OfferController handles the negotiation of an application's terms. The same handlers serve the
+// client and the freelancer of the application, each answering the offers of the other party.
+type OfferController struct {
+	web.Controller
+}
+
+func (c *OfferController) GetOffersHandler() {
+	application, _, ok := c.getOfferApplication()
+	if !ok {
+		return
+	}
+
+	offers, err := models.GetOffers(application.Id)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching offers"}, false, false)
+		return
+	}
+
+	offerList := []types.OfferInfo{}
+	for i := range offers {
+		offerList = append(offerList, offerInfo(&offers[i]))
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = offerList
+	c.ServeJSON()
+}
+
+// ProposeOfferHandler sends the client's offer, or a counter-offer to the pending offer of the other party
+func (c *OfferController) ProposeOfferHandler() {
+	application, user, ok := c.getOfferApplication()
+	if !ok {
+		return
+	}
+
+	offerTerms, err := validators.OfferValidator(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	if !application.InReview() {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Offers can only be made on applications under review"}, false, false)
+		return
+	}
+
+	if err := jobstate.Can(application.Job, jobstate.Hire); err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	pending, err := models.GetPendingOffer(application.Id)
+	if err != nil && err != orm.ErrNoRows {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching offers"}, false, false)
+		return
+	}
+
+	// Freelancers only counter, the client opens the negotiation
+	if user.Role == "freelancer" && pending == nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "There is no offer to counter"}, false, false)
+		return
+	}
+	if pending != nil && pending.ProposedBy == user.Role {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Your offer is waiting for an answer"}, false, false)
+		return
+	}
+
+	offer, err := models.ProposeOffer(application, user.Role, offerTerms)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error sending offer"}, false, false)
+		return
+	}
+
+	if user.Role == "client" {
+		title := fmt.Sprintf("You received an offer for \"%s\"", application.Job.Title)
+		if pending != nil {
+			title = fmt.Sprintf("The client countered your terms for \"%s\"", application.Job.Title)
+		}
+		models.CreateNotification(application.User.Id, "offer", title,
+			"Accept, decline or counter the offer.", fmt.Sprintf("/user/freelancer/applications/%d/offers", application.Id))
+	} else {
+		models.CreateNotification(application.Job.Client.Id, "offer", fmt.Sprintf("%s %s countered your offer for \"%s\"", user.Name, user.Surname, application.Job.Title),
+			"Accept, decline or counter the new terms.", fmt.Sprintf("/user/client/jobs/applications/%d/offers", application.Id))
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusCreated)
+	c.Data["json"] = map[string]interface{}{"message": "Offer sent successfully", "offer_id": offer.Id}
+	c.ServeJSON()
+}
+
+// AcceptOfferHandler accepts the pending offer of the other party, which hires the freelancer
+func (c *OfferController) AcceptOfferHandler() {
+	application, user, offer, ok := c.getOfferToAnswer()
+	if !ok {
+		return
+	}
+
+	if err := jobstate.Can(application.Job, jobstate.Hire); err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	// hiring accepts the application, the other applications under review are rejected once all openings are filled
+	err := jobstate.Fire(application.Job, jobstate.Change{Event: jobstate.Hire, ActorID: user.Id, Application: application, Offer: offer})
+	if err != nil && (errors.Is(err, jobstate.ErrNotAllowed) || err.Error() == "offer already answered") {
+		c.Ctx.Output.SetStatus(http.StatusConflict)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error accepting offer"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": "Offer accepted successfully", "job_status": application.Job.Status}
+	c.ServeJSON()
+}
+
+// DeclineOfferHandler declines the pending offer of the other party and ends the negotiation
+func (c *OfferController) DeclineOfferHandler() {
+	application, user, offer, ok := c.getOfferToAnswer()
+	if !ok {
+		return
+	}
+
+	err := models.DeclineOffer(offer, application)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error declining offer"}, false, false)
+		return
+	}
+
+	if user.Role == "client" {
+		models.CreateNotification(application.User.Id, "offer", fmt.Sprintf("The client declined your terms for \"%s\"", application.Job.Title),
+			"The negotiation ended.", fmt.Sprintf("/user/freelancer/applications/%d/offers", application.Id))
+	} else {
+		models.CreateNotification(application.Job.Client.Id, "offer", fmt.Sprintf("%s %s declined your offer for \"%s\"", user.Name, user.Surname, application.Job.Title),
+			"You can send a new offer or reject the application.", fmt.Sprintf("/user/client/jobs/applications/%d/offers", application.Id))
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": "Offer declined successfully"}
+	c.ServeJSON()
+}
+
+// getOfferApplication loads the application from the :id parameter and writes the error response
+// when the user is neither its freelancer nor the client of its job
+func (c *OfferController) getOfferApplication() (*models.Application, *models.User, bool) {
+	applicationID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid application ID"}, false, false)
+		return nil, nil, false
+	}
+
+	userID := c.Ctx.Input.GetData("id").(int)
+	user, err := models.GetUserById(userID)
+	if user == nil || err != nil {
+		c.Ctx.Output.SetStatus(http.StatusUnauthorized)
+		c.Ctx.Output.JSON(map[string]string{"error": "User not found"}, false, false)
+		return nil, nil, false
+	}
+
+	application, err := models.GetApplicationByID(applicationID)
+	if err != nil || application == nil ||
+		(user.Role == "client" && application.Job.Client.Id != user.Id) ||
+		(user.Role == "freelancer" && application.User.Id != user.Id) ||
+		(user.Role != "client" && user.Role != "freelancer") {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Application not found"}, false, false)
+		return nil, nil, false
+	}
+
+	return application, user, true
+}
+
+// getOfferToAnswer loads the application and its pending offer, and writes the error response
+// when no offer of the other party is waiting for the user's answer
+func (c *OfferController) getOfferToAnswer() (*models.Application, *models.User, *models.Offer, bool) {
+	application, user, ok := c.getOfferApplication()
+	if !ok {
+		return nil, nil, nil, false
+	}
+
+	offer, err := models.GetPendingOffer(application.Id)
+	if err != nil && err != orm.ErrNoRows {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching offers"}, false, false)
+		return nil, nil, nil, false
+	}
+	if offer == nil || offer.ProposedBy == user.Role {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "There is no offer waiting for your answer"}, false, false)
+		return nil, nil, nil, false
+	}
+
+	return application, user, offer, true
+}
+
+func offerInfo(offer *models.Offer) types.OfferInfo {
+	return types.OfferInfo{
+		ID:            offer.Id,
+		ApplicationID: offer.Application.Id,
+		Round:         offer.Round,
+		ProposedBy:    offer.ProposedBy,
+		Rate:          offer.Rate,
+		Amount:        offer.Amount,
+		StartDate:     offer.StartDate,
+		Milestones:    offer.MilestoneList(),
+		Message:       offer.Message,
+		Status:        offer.Status,
+		CreatedAt:     offer.CreatedAt,
+		RespondedAt:   offer.RespondedAt,
+	}
+}
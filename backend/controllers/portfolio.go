@@ -0,0 +1,452 @@
+package controllers
+
+import (
+	"backend/models"
+	"backend/storage"
+	"backend/types"
+	"backend/validators"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/beego/beego/v2/server/web"
+)
+
+const (
+	// maxPortfolioItems is how many items a freelancer's portfolio can have
+	maxPortfolioItems = 50
+	// maxFeaturedPortfolioItems is how many items can be featured at the top of the portfolio
+	maxFeaturedPortfolioItems = 3
+	// maxPortfolioFiles is how many images and PDFs a portfolio item can have
+	maxPortfolioFiles = 10
+)
+
+type PortfolioController struct {
+	web.Controller
+}
+
+func (c *PortfolioController) CreatePortfolioItemHandler() {
+	userID := c.Ctx.Input.GetData("id").(int)
+	user, err := models.GetUserById(userID)
+	if user == nil || err != nil {
+		c.Ctx.Output.SetStatus(http.StatusUnauthorized)
+		c.Ctx.Output.JSON(map[string]string{"error": "User not found"}, false, false)
+		return
+	}
+
+	if user.Role != "freelancer" {
+		c.Ctx.Output.SetStatus(http.StatusForbidden)
+		c.Ctx.Output.JSON(map[string]string{"error": "Only freelancers can add portfolio items"}, false, false)
+		return
+	}
+
+	portfolioItemRequest, err := validators.PortfolioItemValidator(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	items, err := models.GetPortfolioItemsByFreelancerID(userID)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching portfolio"}, false, false)
+		return
+	}
+	if len(items) >= maxPortfolioItems {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": fmt.Sprintf("A portfolio can have at most %d items", maxPortfolioItems)}, false, false)
+		return
+	}
+	if !c.checkFeaturedLimit(userID, 0, portfolioItemRequest.Featured) {
+		return
+	}
+
+	// Files are checked before the item is created so that a bad file creates nothing
+	files, err := attachmentFiles(userID, portfolioItemRequest.Files, nil, portfolioFileLimits())
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	item := models.PortfolioItem{Freelancer: user}
+	setPortfolioItemFields(&item, portfolioItemRequest)
+	if err := models.CreatePortfolioItem(&item, portfolioItemRequest.SkillIDs); err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error creating portfolio item"}, false, false)
+		return
+	}
+
+	for i := range files {
+		if _, err := savePortfolioItemAttachment(item.Id, &files[i]); err != nil {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "Failed to save attachment"}, false, false)
+			return
+		}
+	}
+
+	itemInfo, err := portfolioItemInfo(&item)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching portfolio item"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusCreated)
+	c.Data["json"] = itemInfo
+	c.ServeJSON()
+}
+
+func (c *PortfolioController) GetPortfolioItemsHandler() {
+	userID := c.Ctx.Input.GetData("id").(int)
+
+	portfolio, err := portfolioList(userID)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching portfolio"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = portfolio
+	c.ServeJSON()
+}
+
+func (c *PortfolioController) GetPortfolioItemHandler() {
+	item, ok := c.getOwnPortfolioItem()
+	if !ok {
+		return
+	}
+
+	itemInfo, err := portfolioItemInfo(item)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching portfolio item"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = itemInfo
+	c.ServeJSON()
+}
+
+// UpdatePortfolioItemHandler replaces the fields and skills of the item, its files are kept
+func (c *PortfolioController) UpdatePortfolioItemHandler() {
+	item, ok := c.getOwnPortfolioItem()
+	if !ok {
+		return
+	}
+
+	portfolioItemRequest, err := validators.PortfolioItemValidator(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+	if len(portfolioItemRequest.Files) > 0 {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Files are added to an existing item one by one"}, false, false)
+		return
+	}
+	if !c.checkFeaturedLimit(item.Freelancer.Id, item.Id, portfolioItemRequest.Featured) {
+		return
+	}
+
+	setPortfolioItemFields(item, portfolioItemRequest)
+	if err := models.UpdatePortfolioItem(item, portfolioItemRequest.SkillIDs); err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error updating portfolio item"}, false, false)
+		return
+	}
+
+	itemInfo, err := portfolioItemInfo(item)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching portfolio item"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = itemInfo
+	c.ServeJSON()
+}
+
+// DeletePortfolioItemHandler removes the item together with its files
+func (c *PortfolioController) DeletePortfolioItemHandler() {
+	item, ok := c.getOwnPortfolioItem()
+	if !ok {
+		return
+	}
+
+	attachments, err := models.GetAttachmentsByPortfolioItemID(item.Id)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching attachments"}, false, false)
+		return
+	}
+	for i := range attachments {
+		if err := deleteAttachment(&attachments[i]); err != nil {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "Failed to delete attachment"}, false, false)
+			return
+		}
+	}
+
+	if err := models.DeletePortfolioItemByID(item.Id); err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error deleting portfolio item"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": "Portfolio item deleted successfully"}
+	c.ServeJSON()
+}
+
+// ReorderPortfolioHandler sets the order of the items, featured items stay at the top
+func (c *PortfolioController) ReorderPortfolioHandler() {
+	userID := c.Ctx.Input.GetData("id").(int)
+
+	portfolioOrderRequest, err := validators.PortfolioOrderValidator(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	err = models.ReorderPortfolioItems(userID, portfolioOrderRequest.ItemIDs)
+	if err != nil && err.Error() == "portfolio order mismatch" {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "item_ids must list every portfolio item once"}, false, false)
+		return
+	}
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error reordering portfolio"}, false, false)
+		return
+	}
+
+	portfolio, err := portfolioList(userID)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching portfolio"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = portfolio
+	c.ServeJSON()
+}
+
+// AddPortfolioFileHandler adds an image or a PDF to the item, sent as JSON or multipart/form-data
+func (c *PortfolioController) AddPortfolioFileHandler() {
+	item, ok := c.getOwnPortfolioItem()
+	if !ok {
+		return
+	}
+
+	var attachmentUpload *types.AttachmentUpload
+	var err error
+	if c.Ctx.Input.IsUpload() {
+		attachmentUpload, err = validators.PortfolioFileFormValidator(c.Ctx.Request.MultipartForm)
+	} else {
+		attachmentUpload, err = validators.PortfolioFileUploadValidator(c.Ctx.Input.RequestBody)
+	}
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	attachments, err := models.GetAttachmentsByPortfolioItemID(item.Id)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching attachments"}, false, false)
+		return
+	}
+
+	files, err := attachmentFiles(item.Freelancer.Id, []types.AttachmentUpload{*attachmentUpload}, attachments, portfolioFileLimits())
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	attachment, err := savePortfolioItemAttachment(item.Id, &files[0])
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Failed to save attachment"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusCreated)
+	c.Data["json"] = attachmentInfo(attachment)
+	c.ServeJSON()
+}
+
+func (c *PortfolioController) DeletePortfolioFileHandler() {
+	item, ok := c.getOwnPortfolioItem()
+	if !ok {
+		return
+	}
+
+	attachmentID, err := strconv.Atoi(c.Ctx.Input.Param(":attachmentId"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid attachment ID"}, false, false)
+		return
+	}
+
+	attachment, err := models.GetAttachmentByID(attachmentID)
+	if err != nil || attachment.PortfolioItem == nil || attachment.PortfolioItem.Id != item.Id {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Attachment not found"}, false, false)
+		return
+	}
+
+	if err := deleteAttachment(attachment); err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Failed to delete attachment"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": "Attachment deleted successfully"}
+	c.ServeJSON()
+}
+
+// getOwnPortfolioItem loads the item from the :id parameter and writes the error response
+// when it is not the freelancer's own item
+func (c *PortfolioController) getOwnPortfolioItem() (*models.PortfolioItem, bool) {
+	itemID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid portfolio item ID"}, false, false)
+		return nil, false
+	}
+
+	userID := c.Ctx.Input.GetData("id").(int)
+	item, err := models.GetPortfolioItemByID(itemID)
+	if err != nil || item.Freelancer.Id != userID {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Portfolio item not found"}, false, false)
+		return nil, false
+	}
+
+	return item, true
+}
+
+// checkFeaturedLimit writes the error response when featuring the item would feature too many items
+func (c *PortfolioController) checkFeaturedLimit(freelancerID, itemID int, featured bool) bool {
+	if !featured {
+		return true
+	}
+
+	count, err := models.CountFeaturedPortfolioItems(freelancerID, itemID)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching portfolio"}, false, false)
+		return false
+	}
+	if count >= maxFeaturedPortfolioItems {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": fmt.Sprintf("At most %d portfolio items can be featured", maxFeaturedPortfolioItems)}, false, false)
+		return false
+	}
+
+	return true
+}
+
+// portfolioFileLimits are the limits on the files of a portfolio item, which are larger than
+// attachments so that big files can be sent as resumable uploads
+func portfolioFileLimits() fileLimits {
+	return fileLimits{
+		Files:     maxPortfolioFiles,
+		FileSize:  int64(web.AppConfig.DefaultInt("portfolio_max_file_size", 100)) << 20,
+		TotalSize: int64(web.AppConfig.DefaultInt("portfolio_max_total_size", 250)) << 20,
+		CheckName: validators.PortfolioFileNameValidator,
+	}
+}
+
+func setPortfolioItemFields(item *models.PortfolioItem, portfolioItemRequest *types.PortfolioItemRequest) {
+	item.Title = portfolioItemRequest.Title
+	item.Description = portfolioItemRequest.Description
+	item.Role = portfolioItemRequest.Role
+	item.ProjectURL = portfolioItemRequest.ProjectURL
+	item.StartDate = portfolioItemRequest.Start
+	item.EndDate = portfolioItemRequest.End
+	item.Featured = portfolioItemRequest.Featured
+}
+
+func savePortfolioItemAttachment(itemID int, file *uploadedFile) (*models.Attachment, error) {
+	key, err := storeAttachmentFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment, err := models.CreatePortfolioItemAttachment(itemID, file.FileName, key, file.Size)
+	if err != nil {
+		storage.Default().Delete(key)
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// portfolioList returns the freelancer's items, featured first and then in their order
+func portfolioList(freelancerID int) ([]types.PortfolioItemInfo, error) {
+	items, err := models.GetPortfolioItemsByFreelancerID(freelancerID)
+	if err != nil {
+		return nil, err
+	}
+
+	portfolio := []types.PortfolioItemInfo{}
+	for i := range items {
+		itemInfo, err := portfolioItemInfo(&items[i])
+		if err != nil {
+			return nil, err
+		}
+		portfolio = append(portfolio, itemInfo)
+	}
+
+	return portfolio, nil
+}
+
+func portfolioItemInfo(item *models.PortfolioItem) (types.PortfolioItemInfo, error) {
+	itemSkills, err := models.GetPortfolioItemSkills(item.Id)
+	if err != nil {
+		return types.PortfolioItemInfo{}, err
+	}
+	skillList := []types.Skill{}
+	for _, itemSkill := range itemSkills {
+		skillList = append(skillList, skillInfo(itemSkill.Skill))
+	}
+
+	attachments, err := models.GetAttachmentsByPortfolioItemID(item.Id)
+	if err != nil {
+		return types.PortfolioItemInfo{}, err
+	}
+
+	itemInfo := types.PortfolioItemInfo{
+		ID:          item.Id,
+		Title:       item.Title,
+		Description: item.Description,
+		Role:        item.Role,
+		ProjectURL:  item.ProjectURL,
+		Skills:      skillList,
+		Featured:    item.Featured,
+		Position:    item.Position,
+		Files:       attachmentList(attachments),
+		CreatedAt:   item.CreatedAt,
+		UpdatedAt:   item.UpdatedAt,
+	}
+	if item.StartDate != nil {
+		itemInfo.StartDate = item.StartDate.Format("2006-01-02")
+	}
+	if item.EndDate != nil {
+		itemInfo.EndDate = item.EndDate.Format("2006-01-02")
+	}
+
+	return itemInfo, nil
+}
@@ -0,0 +1,191 @@
+package controllers
+
+import (
+	"backend/models"
+	"backend/types"
+	"net/http"
+	"strconv"
+
+	"github.com/beego/beego/v2/server/web"
+)
+
+type RecommendationController struct {
+	web.Controller
+}
+
+func (c *RecommendationController) GetRecommendedJobsHandler() {
+	userID := c.Ctx.Input.GetData("id").(int)
+	user, err := models.GetUserById(userID)
+	if user == nil || err != nil {
+		c.Ctx.Output.SetStatus(http.StatusUnauthorized)
+		c.Ctx.Output.JSON(map[string]string{"error": "User not found"}, false, false)
+		return
+	}
+
+	if user.Role != "freelancer" {
+		c.Ctx.Output.SetStatus(http.StatusForbidden)
+		c.Ctx.Output.JSON(map[string]string{"error": "Only freelancers can get job recommendations"}, false, false)
+		return
+	}
+
+	limit, err := c.GetInt("limit", 20)
+	if err != nil || limit <= 0 || limit > 50 {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Limit must be between 1 and 50"}, false, false)
+		return
+	}
+
+	scores, err := models.GetRecommendedJobScores(userID, limit)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching recommended jobs"}, false, false)
+		return
+	}
+
+	bookmarkedIDs, err := models.GetBookmarkedJobIDs(userID)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching bookmarks"}, false, false)
+		return
+	}
+
+	viewer, err := models.NewJobViewer(user)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching recommended jobs"}, false, false)
+		return
+	}
+
+	recommendations := []types.RecommendedJob{}
+	for _, score := range scores {
+		job := score.Job
+		if !viewer.CanView(job) {
+			continue
+		}
+
+		skillList, err := jobSkillList(job.Id)
+		if err != nil {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "Error fetching job skills"}, false, false)
+			return
+		}
+
+		recommendations = append(recommendations, types.RecommendedJob{
+			Job: types.JobInfo{
+				ID:           job.Id,
+				Title:        job.Title,
+				Description:  job.Description,
+				Type:         job.Type,
+				Rate:         job.Rate,
+				Amount:       job.Amount,
+				Length:       job.Length,
+				HoursPerWeek: job.HoursPerWeek,
+				Openings:     job.Openings,
+				ClientID:     job.Client.Id,
+				Skills:       skillList,
+				Bookmarked:   bookmarkedIDs[job.Id],
+			},
+			Score:     score.Score,
+			Breakdown: matchBreakdown(&score),
+			Reasons:   score.ReasonList(),
+		})
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = recommendations
+	c.ServeJSON()
+}
+
+func (c *RecommendationController) GetRecommendedFreelancersHandler() {
+	jobID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid job ID"}, false, false)
+		return
+	}
+
+	userID := c.Ctx.Input.GetData("id").(int)
+	user, err := models.GetUserById(userID)
+	if user == nil || err != nil {
+		c.Ctx.Output.SetStatus(http.StatusUnauthorized)
+		c.Ctx.Output.JSON(map[string]string{"error": "User not found"}, false, false)
+		return
+	}
+
+	if user.Role != "client" {
+		c.Ctx.Output.SetStatus(http.StatusForbidden)
+		c.Ctx.Output.JSON(map[string]string{"error": "Only clients can get freelancer recommendations"}, false, false)
+		return
+	}
+
+	job, err := models.GetJobByID(jobID)
+	if err != nil || job.Client.Id != user.Id {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Job not found"}, false, false)
+		return
+	}
+
+	if job.Status != "open" {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Recommendations are only available for open jobs"}, false, false)
+		return
+	}
+
+	limit, err := c.GetInt("limit", 20)
+	if err != nil || limit <= 0 || limit > 50 {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Limit must be between 1 and 50"}, false, false)
+		return
+	}
+
+	scores, err := models.GetRecommendedFreelancerScores(jobID, limit)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching recommended freelancers"}, false, false)
+		return
+	}
+
+	talentListIDs, err := models.GetTalentListIDsByFreelancer(userID)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching talent lists"}, false, false)
+		return
+	}
+
+	recommendations := []types.RecommendedFreelancer{}
+	for _, score := range scores {
+		freelancer := score.Freelancer
+
+		freelancerData, err := models.GetFreelancerDataByUserID(freelancer.Id)
+		if err != nil || freelancerData == nil {
+			continue
+		}
+
+		recommendations = append(recommendations, types.RecommendedFreelancer{
+			Freelancer: types.FreelancerInfo{
+				ID:            freelancer.Id,
+				Name:          freelancer.Name,
+				Surname:       freelancer.Surname,
+				Title:         freelancerData.Title,
+				HourlyRate:    freelancerData.HourlyRate,
+				TalentListIDs: talentListIDs[freelancer.Id],
+			},
+			Score:     score.Score,
+			Breakdown: matchBreakdown(&score),
+			Reasons:   score.ReasonList(),
+		})
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = recommendations
+	c.ServeJSON()
+}
+
+func matchBreakdown(score *models.MatchScore) types.MatchBreakdown {
+	return types.MatchBreakdown{
+		Skills:  score.SkillScore,
+		Rate:    score.RateScore,
+		Hours:   score.HoursScore,
+		History: score.HistoryScore,
+	}
+}
@@ -0,0 +1,320 @@
+package controllers
+
+import (
+	"backend/models"
+	"backend/types"
+	"backend/validators"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/beego/beego/v2/server/web"
+)
+
+type ReportController struct {
+	web.Controller
+}
+
+func reportInfo(report *models.Report) types.ReportInfo {
+	resolvedByID := 0
+	if report.ResolvedBy != nil {
+		resolvedByID = report.ResolvedBy.Id
+	}
+
+	return types.ReportInfo{
+		ID:           report.Id,
+		ReporterID:   report.Reporter.Id,
+		TargetType:   report.TargetType,
+		TargetID:     report.TargetId,
+		Category:     report.Category,
+		Description:  report.Description,
+		Status:       report.Status,
+		Action:       report.Action,
+		Resolution:   report.Resolution,
+		ResolvedByID: resolvedByID,
+		ResolvedAt:   report.ResolvedAt,
+		CreatedAt:    report.CreatedAt,
+	}
+}
+
+func (c *ReportController) CreateReportHandler() {
+
+	userID := c.Ctx.Input.GetData("id").(int)
+	user, err := models.GetUserById(userID)
+	if user == nil || err != nil {
+		c.Ctx.Output.SetStatus(http.StatusUnauthorized)
+		c.Ctx.Output.JSON(map[string]string{"error": "User not found"}, false, false)
+		return
+	}
+
+	createReportRequest, err := validators.CreateReportValidator(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	switch createReportRequest.TargetType {
+	case "job":
+		job, err := models.GetJobByID(createReportRequest.TargetID)
+		if job == nil || err != nil {
+			c.Ctx.Output.SetStatus(http.StatusNotFound)
+			c.Ctx.Output.JSON(map[string]string{"error": "Job not found"}, false, false)
+			return
+		}
+		if job.Client.Id == userID {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Ctx.Output.JSON(map[string]string{"error": "You cannot report your own job"}, false, false)
+			return
+		}
+	case "application":
+		application, err := models.GetApplicationByID(createReportRequest.TargetID)
+		// Applications are only visible to the client who owns the job
+		if application == nil || err != nil || application.Job.Client.Id != userID {
+			c.Ctx.Output.SetStatus(http.StatusNotFound)
+			c.Ctx.Output.JSON(map[string]string{"error": "Application not found"}, false, false)
+			return
+		}
+	case "user":
+		reportedUser, err := models.GetUserById(createReportRequest.TargetID)
+		if reportedUser == nil || err != nil {
+			c.Ctx.Output.SetStatus(http.StatusNotFound)
+			c.Ctx.Output.JSON(map[string]string{"error": "User not found"}, false, false)
+			return
+		}
+		if reportedUser.Id == userID {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Ctx.Output.JSON(map[string]string{"error": "You cannot report yourself"}, false, false)
+			return
+		}
+	}
+
+	_, err = models.CreateReport(userID, createReportRequest.TargetType, createReportRequest.TargetID, createReportRequest.Category, createReportRequest.Description)
+	if err != nil {
+		if err.Error() == "report already open" {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Ctx.Output.JSON(map[string]string{"error": "You have already reported this"}, false, false)
+		} else {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "Failed to submit report"}, false, false)
+		}
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusCreated)
+	c.Data["json"] = map[string]string{"message": "Report submitted successfully"}
+	c.ServeJSON()
+}
+
+func (c *ReportController) GetUserReportsHandler() {
+
+	userID := c.Ctx.Input.GetData("id").(int)
+	user, err := models.GetUserById(userID)
+	if user == nil || err != nil {
+		c.Ctx.Output.SetStatus(http.StatusUnauthorized)
+		c.Ctx.Output.JSON(map[string]string{"error": "User not found"}, false, false)
+		return
+	}
+
+	reports, err := models.GetReportsByReporterID(userID)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching reports"}, false, false)
+		return
+	}
+
+	var reportList []types.ReportInfo
+	for i := range reports {
+		reportList = append(reportList, reportInfo(&reports[i]))
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = reportList
+	c.ServeJSON()
+}
+
+// Admin function
+func (c *ReportController) GetReportsHandler() {
+
+	status := c.GetString("status", "open")
+	if status == "all" {
+		status = ""
+	} else if !types.ValidReportStatuses[status] {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid status filter"}, false, false)
+		return
+	}
+
+	reports, err := models.GetReports(status, c.GetString("target_type"), c.GetString("category"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching reports"}, false, false)
+		return
+	}
+
+	var reportList []types.ReportInfo
+	for i := range reports {
+		info := reportInfo(&reports[i])
+
+		openCount, err := models.GetOpenReportCountForTarget(reports[i].TargetType, reports[i].TargetId)
+		if err != nil {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "Error fetching report count"}, false, false)
+			return
+		}
+		info.OpenReportsCount = openCount
+
+		reportList = append(reportList, info)
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = reportList
+	c.ServeJSON()
+}
+
+// Admin function
+func (c *ReportController) GetReportHandler() {
+
+	reportID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid report ID"}, false, false)
+		return
+	}
+
+	report, err := models.GetReportByID(reportID)
+	if report == nil || err != nil {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Report not found"}, false, false)
+		return
+	}
+
+	info := reportInfo(report)
+	openCount, err := models.GetOpenReportCountForTarget(report.TargetType, report.TargetId)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching report count"}, false, false)
+		return
+	}
+	info.OpenReportsCount = openCount
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = info
+	c.ServeJSON()
+}
+
+// Admin function
+func (c *ReportController) ResolveReportHandler() {
+
+	reportID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid report ID"}, false, false)
+		return
+	}
+
+	resolveReportRequest, err := validators.ResolveReportValidator(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	report, err := models.GetReportByID(reportID)
+	if report == nil || err != nil {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Report not found"}, false, false)
+		return
+	}
+
+	if report.Status != "open" {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Report has already been closed"}, false, false)
+		return
+	}
+
+	adminID := c.Ctx.Input.GetData("id").(int)
+
+	switch resolveReportRequest.Action {
+	case "remove-job":
+		if report.TargetType != "job" {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Ctx.Output.JSON(map[string]string{"error": "Only job reports can remove a job"}, false, false)
+			return
+		}
+
+		err = models.DeleteJobByID(report.TargetId)
+		if err != nil {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "Error deleting job"}, false, false)
+			return
+		}
+
+	case "remove-application":
+		if report.TargetType != "application" {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Ctx.Output.JSON(map[string]string{"error": "Only application reports can remove an application"}, false, false)
+			return
+		}
+
+		err = models.SoftDeleteApplicationByID(report.TargetId)
+		if err != nil {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "Failed to delete application"}, false, false)
+			return
+		}
+
+	case "ban-user":
+		// Ban the user behind the reported content
+		bannedUserID := 0
+		switch report.TargetType {
+		case "user":
+			bannedUserID = report.TargetId
+		case "job":
+			job, err := models.GetJobByID(report.TargetId)
+			if job == nil || err != nil {
+				c.Ctx.Output.SetStatus(http.StatusNotFound)
+				c.Ctx.Output.JSON(map[string]string{"error": "Job not found"}, false, false)
+				return
+			}
+			bannedUserID = job.Client.Id
+		case "application":
+			application, err := models.GetApplicationByID(report.TargetId)
+			if application == nil || err != nil {
+				c.Ctx.Output.SetStatus(http.StatusNotFound)
+				c.Ctx.Output.JSON(map[string]string{"error": "Application not found"}, false, false)
+				return
+			}
+			bannedUserID = application.User.Id
+		}
+
+		if bannedUserID == adminID {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Ctx.Output.JSON(map[string]string{"error": "Admins cannot ban themselves"}, false, false)
+			return
+		}
+
+		reason := resolveReportRequest.Resolution
+		if reason == "" {
+			reason = fmt.Sprintf("Banned after report #%d (%s)", report.Id, report.Category)
+		}
+
+		_, err = models.CreateBan(bannedUserID, adminID, reason, resolveReportRequest.BanEndsAt)
+		if err != nil {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "Failed to ban user"}, false, false)
+			return
+		}
+	}
+
+	err = models.CloseReport(report, adminID, resolveReportRequest.Status, resolveReportRequest.Action, resolveReportRequest.Resolution)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Failed to update report"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": "Report " + resolveReportRequest.Status + " successfully"}
+	c.ServeJSON()
+}
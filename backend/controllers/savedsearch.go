@@ -0,0 +1,251 @@
+package controllers
+
+import (
+	"backend/models"
+	"backend/types"
+	"backend/validators"
+	"net/http"
+	"strconv"
+
+	"github.com/beego/beego/v2/server/web"
+)
+
+type SavedSearchController struct {
+	web.Controller
+}
+
+func (c *SavedSearchController) CreateSavedSearchHandler() {
+	userID := c.Ctx.Input.GetData("id").(int)
+	user, err := models.GetUserById(userID)
+	if user == nil || err != nil {
+		c.Ctx.Output.SetStatus(http.StatusUnauthorized)
+		c.Ctx.Output.JSON(map[string]string{"error": "User not found"}, false, false)
+		return
+	}
+
+	if user.Role != "freelancer" {
+		c.Ctx.Output.SetStatus(http.StatusForbidden)
+		c.Ctx.Output.JSON(map[string]string{"error": "Only freelancers can save job searches"}, false, false)
+		return
+	}
+
+	savedSearchRequest, err := validators.SavedSearchValidator(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	savedSearch := &models.SavedSearch{User: user}
+	applySavedSearchRequest(savedSearch, savedSearchRequest)
+
+	savedSearchID, err := models.CreateSavedSearch(savedSearch, savedSearchRequest.SkillIDs)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error saving search"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusCreated)
+	c.Data["json"] = map[string]interface{}{"message": "Search saved successfully", "id": savedSearchID}
+	c.ServeJSON()
+}
+
+func (c *SavedSearchController) GetSavedSearchesHandler() {
+	userID := c.Ctx.Input.GetData("id").(int)
+
+	savedSearches, err := models.GetSavedSearchesByUserID(userID)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching saved searches"}, false, false)
+		return
+	}
+
+	savedSearchList := []types.SavedSearchInfo{}
+	for i := range savedSearches {
+		savedSearchList = append(savedSearchList, savedSearchInfo(&savedSearches[i]))
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = savedSearchList
+	c.ServeJSON()
+}
+
+func (c *SavedSearchController) UpdateSavedSearchHandler() {
+	savedSearch, ok := c.getOwnSavedSearch()
+	if !ok {
+		return
+	}
+
+	savedSearchRequest, err := validators.SavedSearchValidator(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	applySavedSearchRequest(savedSearch, savedSearchRequest)
+
+	err = models.UpdateSavedSearch(savedSearch, savedSearchRequest.SkillIDs)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error updating saved search"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": "Saved search updated successfully"}
+	c.ServeJSON()
+}
+
+func (c *SavedSearchController) DeleteSavedSearchHandler() {
+	savedSearch, ok := c.getOwnSavedSearch()
+	if !ok {
+		return
+	}
+
+	err := models.DeleteSavedSearchByID(savedSearch.Id)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error deleting saved search"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": "Saved search deleted successfully"}
+	c.ServeJSON()
+}
+
+// GetSavedSearchJobsHandler runs the saved search against the open jobs
+func (c *SavedSearchController) GetSavedSearchJobsHandler() {
+	savedSearch, ok := c.getOwnSavedSearch()
+	if !ok {
+		return
+	}
+
+	jobs, err := models.GetOpenJobs()
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching jobs"}, false, false)
+		return
+	}
+
+	bookmarkedIDs, err := models.GetBookmarkedJobIDs(savedSearch.User.Id)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching bookmarks"}, false, false)
+		return
+	}
+
+	user, err := models.GetUserById(savedSearch.User.Id)
+	if user == nil || err != nil {
+		c.Ctx.Output.SetStatus(http.StatusUnauthorized)
+		c.Ctx.Output.JSON(map[string]string{"error": "User not found"}, false, false)
+		return
+	}
+
+	viewer, err := models.NewJobViewer(user)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching jobs"}, false, false)
+		return
+	}
+
+	filter := savedSearch.SearchFilter()
+
+	jobList := []types.JobInfo{}
+	for _, job := range jobs {
+		if !viewer.CanView(&job) {
+			continue
+		}
+
+		jobSkillIDs := make([]int, 0, len(job.Skills))
+		for _, skill := range job.Skills {
+			jobSkillIDs = append(jobSkillIDs, skill.Id)
+		}
+		if !models.JobMatchesSearch(&job, jobSkillIDs, filter) {
+			continue
+		}
+
+		skillList, err := jobSkillList(job.Id)
+		if err != nil {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "Error fetching job skills"}, false, false)
+			return
+		}
+
+		jobList = append(jobList, types.JobInfo{
+			ID:           job.Id,
+			Title:        job.Title,
+			Description:  job.Description,
+			Type:         job.Type,
+			Rate:         job.Rate,
+			Amount:       job.Amount,
+			Length:       job.Length,
+			HoursPerWeek: job.HoursPerWeek,
+			Openings:     job.Openings,
+			ClientID:     job.Client.Id,
+			Skills:       skillList,
+			Bookmarked:   bookmarkedIDs[job.Id],
+		})
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = jobList
+	c.ServeJSON()
+}
+
+// getOwnSavedSearch loads the saved search from the :id parameter and writes the error response
+// when it does not exist or belongs to another user
+func (c *SavedSearchController) getOwnSavedSearch() (*models.SavedSearch, bool) {
+	savedSearchID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid saved search ID"}, false, false)
+		return nil, false
+	}
+
+	userID := c.Ctx.Input.GetData("id").(int)
+	savedSearch, err := models.GetSavedSearchByID(savedSearchID)
+	if err != nil || savedSearch.User.Id != userID {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Saved search not found"}, false, false)
+		return nil, false
+	}
+
+	return savedSearch, true
+}
+
+func applySavedSearchRequest(savedSearch *models.SavedSearch, savedSearchRequest *types.SavedSearchRequest) {
+	savedSearch.Name = savedSearchRequest.Name
+	savedSearch.Rate = savedSearchRequest.Rate
+	savedSearch.MinAmount = savedSearchRequest.MinAmount
+	savedSearch.MaxAmount = savedSearchRequest.MaxAmount
+	savedSearch.Keywords = savedSearchRequest.Keywords
+	savedSearch.Frequency = savedSearchRequest.Frequency
+	savedSearch.Channel = savedSearchRequest.Channel
+}
+
+func savedSearchInfo(savedSearch *models.SavedSearch) types.SavedSearchInfo {
+	skillList := []types.Skill{}
+	for _, skill := range savedSearch.Skills {
+		skillList = append(skillList, types.Skill{
+			Id:   skill.Id,
+			Name: skill.Name,
+		})
+	}
+
+	return types.SavedSearchInfo{
+		ID:         savedSearch.Id,
+		Name:       savedSearch.Name,
+		Skills:     skillList,
+		Rate:       savedSearch.Rate,
+		MinAmount:  savedSearch.MinAmount,
+		MaxAmount:  savedSearch.MaxAmount,
+		Keywords:   savedSearch.Keywords,
+		Frequency:  savedSearch.Frequency,
+		Channel:    savedSearch.Channel,
+		LastSentAt: savedSearch.LastSentAt,
+		CreatedAt:  savedSearch.CreatedAt,
+	}
+}
@@ -53,7 +53,12 @@ func (c *SkillController) AddFreelancerSkillHandler() {
 		}
 	}
 
-	err = models.AddSkillToFreelancerData(freelancerData, skill)
+	level := addSkillRequest.Level
+	if level == "" {
+		level = "beginner"
+	}
+
+	err = models.AddSkillToFreelancerData(freelancerData, skill, level, addSkillRequest.YearsOfExperience)
 	if err != nil {
 		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
 		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
@@ -64,6 +69,45 @@ func (c *SkillController) AddFreelancerSkillHandler() {
 	c.Ctx.Output.JSON(map[string]string{"message": "Skill added successfully"}, false, false)
 }
 
+func (c *SkillController) UpdateFreelancerSkillHandler() {
+	userID := c.Ctx.Input.GetData("id").(int)
+
+	updateSkillRequest, err := validators.AddDeleteSkillValidator(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	if updateSkillRequest.Level == "" {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Missing required fields: level"}, false, false)
+		return
+	}
+
+	freelancerData, err := models.GetFreelancerDataByUserID(userID)
+	if err != nil || freelancerData == nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Freelancer data not found"}, false, false)
+		return
+	}
+
+	err = models.UpdateFreelancerSkill(freelancerData, updateSkillRequest.SkillID, updateSkillRequest.Level, updateSkillRequest.YearsOfExperience)
+	if err != nil {
+		if err.Error() == "skill not found" {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Ctx.Output.JSON(map[string]string{"error": "Freelancer does not have this skill"}, false, false)
+		} else {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "Failed to update skill"}, false, false)
+		}
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Ctx.Output.JSON(map[string]string{"message": "Skill updated successfully"}, false, false)
+}
+
 func (c *SkillController) DeleteFreelancerSkillHandler() {
 
 	userID := c.Ctx.Input.GetData("id").(int)
@@ -120,13 +164,24 @@ func (c *SkillController) GetSkillsHandler() {
 		return
 	}
 
+	aliases, err := models.GetAllSkillAliases()
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Failed to fetch skills"}, false, false)
+		return
+	}
+
+	aliasesBySkill := make(map[int][]string)
+	for _, alias := range aliases {
+		aliasesBySkill[alias.Skill.Id] = append(aliasesBySkill[alias.Skill.Id], alias.Name)
+	}
+
 	var skillsResponse []types.Skill
 
 	for _, skill := range skills {
-		skillsResponse = append(skillsResponse, types.Skill{
-			Id:   skill.Id,
-			Name: skill.Name,
-		})
+		skillResponse := skillInfo(&skill)
+		skillResponse.Aliases = aliasesBySkill[skill.Id]
+		skillsResponse = append(skillsResponse, skillResponse)
 	}
 
 	c.Ctx.Output.SetStatus(http.StatusOK)
@@ -142,7 +197,21 @@ func (c *SkillController) AddSkillHandler() {
 		return
 	}
 
-	err = models.CreateSkill(updateSkillRequest.SkillName)
+	if models.IsSkillNameTaken(updateSkillRequest.SkillName, 0) {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Skill already exists"}, false, false)
+		return
+	}
+
+	if updateSkillRequest.CategoryID != 0 {
+		if _, err := models.GetSkillCategoryByID(updateSkillRequest.CategoryID); err != nil {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Ctx.Output.JSON(map[string]string{"error": "Category not found"}, false, false)
+			return
+		}
+	}
+
+	err = models.CreateSkill(updateSkillRequest.SkillName, updateSkillRequest.CategoryID)
 	if err != nil {
 		if strings.Contains(err.Error(), "unique constraint") {
 			c.Ctx.Output.SetStatus(http.StatusBadRequest)
@@ -212,10 +281,25 @@ func (c *SkillController) UpdateSkillHandler() {
 		return
 	}
 
+	if models.IsSkillNameTaken(updateSkillRequest.SkillName, skill.Id) {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Skill already exists"}, false, false)
+		return
+	}
+
 	if updateSkillRequest.SkillName != "" {
 		skill.Name = updateSkillRequest.SkillName
 	}
 
+	if updateSkillRequest.CategoryID != 0 {
+		if _, err := models.GetSkillCategoryByID(updateSkillRequest.CategoryID); err != nil {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Ctx.Output.JSON(map[string]string{"error": "Category not found"}, false, false)
+			return
+		}
+		skill.Category = &models.SkillCategory{Id: updateSkillRequest.CategoryID}
+	}
+
 	err = models.UpdateSkill(skill)
 	if err != nil {
 		if strings.Contains(err.Error(), "unique constraint") {
@@ -229,7 +313,355 @@ func (c *SkillController) UpdateSkillHandler() {
 	}
 
 	c.Ctx.Output.SetStatus(http.StatusOK)
-	c.Data["json"] = map[string]string{"message": "Skill name updated successfully"}
+	c.Data["json"] = map[string]string{"message": "Skill updated successfully"}
+	c.ServeJSON()
+
+}
+
+func (c *SkillController) SearchSkillsHandler() {
+	query := strings.TrimSpace(c.GetString("q"))
+	if query == "" {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Missing search query"}, false, false)
+		return
+	}
+
+	limit, err := c.GetInt("limit", 10)
+	if err != nil || limit <= 0 || limit > 50 {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Limit must be between 1 and 50"}, false, false)
+		return
+	}
+
+	skills, err := models.SearchSkills(query, limit)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Failed to search skills"}, false, false)
+		return
+	}
+
+	skillsResponse := []types.Skill{}
+	for _, skill := range skills {
+		skillResponse := skillInfo(&skill)
+		aliases, err := models.GetAliasesBySkillID(skill.Id)
+		if err == nil {
+			for _, alias := range aliases {
+				skillResponse.Aliases = append(skillResponse.Aliases, alias.Name)
+			}
+		}
+		skillsResponse = append(skillsResponse, skillResponse)
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Ctx.Output.JSON(skillsResponse, false, false)
+}
+
+func (c *SkillController) GetSkillCategoriesHandler() {
+	categories, err := models.GetAllSkillCategories()
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Failed to fetch skill categories"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Ctx.Output.JSON(skillCategoryTree(categories, 0), false, false)
+}
+
+// Admin function
+func (c *SkillController) AddSkillCategoryHandler() {
+	categoryRequest, err := validators.SkillCategoryValidator(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	if categoryRequest.ParentID != 0 {
+		if _, err := models.GetSkillCategoryByID(categoryRequest.ParentID); err != nil {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Ctx.Output.JSON(map[string]string{"error": "Parent category not found"}, false, false)
+			return
+		}
+	}
+
+	categoryID, err := models.CreateSkillCategory(categoryRequest.Name, categoryRequest.ParentID)
+	if err != nil {
+		if strings.Contains(err.Error(), "unique constraint") {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Ctx.Output.JSON(map[string]string{"error": "Category already exists"}, false, false)
+		} else {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		}
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]interface{}{"message": "Category added successfully", "id": categoryID}
+	c.ServeJSON()
+}
+
+// Admin function
+func (c *SkillController) UpdateSkillCategoryHandler() {
+	categoryID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid category ID"}, false, false)
+		return
+	}
+
+	categoryRequest, err := validators.SkillCategoryValidator(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	category, err := models.GetSkillCategoryByID(categoryID)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Category not found"}, false, false)
+		return
+	}
+
+	category.Name = categoryRequest.Name
+	category.Parent = nil
+	if categoryRequest.ParentID != 0 {
+		if _, err := models.GetSkillCategoryByID(categoryRequest.ParentID); err != nil {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Ctx.Output.JSON(map[string]string{"error": "Parent category not found"}, false, false)
+			return
+		}
+		category.Parent = &models.SkillCategory{Id: categoryRequest.ParentID}
+	}
+
+	err = models.UpdateSkillCategory(category)
+	if err != nil {
+		if err.Error() == "category cycle" {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Ctx.Output.JSON(map[string]string{"error": "Category cannot be nested under itself"}, false, false)
+		} else if strings.Contains(err.Error(), "unique constraint") {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Ctx.Output.JSON(map[string]string{"error": "Category already exists"}, false, false)
+		} else {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		}
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": "Category updated successfully"}
+	c.ServeJSON()
+}
+
+// Admin function
+func (c *SkillController) DeleteSkillCategoryHandler() {
+	categoryID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid category ID"}, false, false)
+		return
+	}
+
+	if _, err := models.GetSkillCategoryByID(categoryID); err != nil {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Category not found"}, false, false)
+		return
+	}
+
+	err = models.DeleteSkillCategoryByID(categoryID)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Failed to delete category"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": "Category deleted successfully"}
+	c.ServeJSON()
+}
+
+// Admin function
+func (c *SkillController) AddSkillAliasHandler() {
+	skillID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid skill ID"}, false, false)
+		return
+	}
+
+	aliasRequest, err := validators.SkillAliasValidator(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	skill, err := models.GetSkillById(skillID)
+	if skill == nil || err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Skill not found"}, false, false)
+		return
+	}
+
+	if models.IsSkillNameTaken(aliasRequest.Alias, 0) {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Name is already used by a skill or alias"}, false, false)
+		return
+	}
+
+	err = models.CreateSkillAlias(skill.Id, aliasRequest.Alias)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": "Alias added successfully"}
+	c.ServeJSON()
+}
+
+// Admin function
+func (c *SkillController) DeleteSkillAliasHandler() {
+	aliasID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid alias ID"}, false, false)
+		return
+	}
+
+	if _, err := models.GetSkillAliasByID(aliasID); err != nil {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Alias not found"}, false, false)
+		return
+	}
+
+	err = models.DeleteSkillAliasByID(aliasID)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Failed to delete alias"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": "Alias deleted successfully"}
+	c.ServeJSON()
+}
+
+// Admin function
+func (c *SkillController) MergeSkillHandler() {
+	skillID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid skill ID"}, false, false)
+		return
+	}
+
+	mergeRequest, err := validators.MergeSkillValidator(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	if skillID == mergeRequest.TargetSkillID {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Cannot merge a skill into itself"}, false, false)
+		return
+	}
+
+	if _, err := models.GetSkillById(skillID); err != nil {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Skill not found"}, false, false)
+		return
+	}
+
+	if _, err := models.GetSkillById(mergeRequest.TargetSkillID); err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Target skill not found"}, false, false)
+		return
+	}
+
+	err = models.MergeSkills(skillID, mergeRequest.TargetSkillID)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Failed to merge skills"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": "Skills merged successfully"}
 	c.ServeJSON()
+}
+
+func skillInfo(skill *models.Skill) types.Skill {
+	info := types.Skill{
+		Id:   skill.Id,
+		Name: skill.Name,
+	}
+	if skill.Category != nil {
+		info.CategoryID = skill.Category.Id
+	}
+	return info
+}
+
+// freelancerSkillList returns the skills of a freelancer with their proficiency
+func freelancerSkillList(freelancerDataID int) ([]types.Skill, error) {
+	freelancerSkills, err := models.GetFreelancerSkills(freelancerDataID)
+	if err != nil {
+		return nil, err
+	}
+
+	var skillList []types.Skill
+	for _, freelancerSkill := range freelancerSkills {
+		skill := skillInfo(freelancerSkill.Skill)
+		skill.Level = freelancerSkill.Level
+		skill.YearsOfExperience = freelancerSkill.YearsOfExperience
+		skillList = append(skillList, skill)
+	}
+
+	return skillList, nil
+}
+
+// jobSkillList returns the skills of a job with whether they are required and the minimum level
+func jobSkillList(jobID int) ([]types.Skill, error) {
+	jobSkills, err := models.GetJobSkills(jobID)
+	if err != nil {
+		return nil, err
+	}
 
+	var skillList []types.Skill
+	for _, jobSkill := range jobSkills {
+		skill := skillInfo(jobSkill.Skill)
+		required := jobSkill.Required
+		skill.Required = &required
+		skill.MinLevel = jobSkill.MinLevel
+		skillList = append(skillList, skill)
+	}
+
+	return skillList, nil
+}
+
+// skillCategoryTree nests the flat category list under the category with parentID, 0 being the root
+func skillCategoryTree(categories []models.SkillCategory, parentID int) []types.SkillCategory {
+	tree := []types.SkillCategory{}
+	for _, category := range categories {
+		categoryParentID := 0
+		if category.Parent != nil {
+			categoryParentID = category.Parent.Id
+		}
+		if categoryParentID != parentID {
+			continue
+		}
+		tree = append(tree, types.SkillCategory{
+			Id:       category.Id,
+			Name:     category.Name,
+			ParentID: categoryParentID,
+			Children: skillCategoryTree(categories, category.Id),
+		})
+	}
+	return tree
 }
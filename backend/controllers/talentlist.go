@@ -0,0 +1,268 @@
+package controllers
+
+import (
+	"backend/models"
+	"backend/types"
+	"backend/validators"
+	"net/http"
+	"strconv"
+
+	"github.com/beego/beego/v2/server/web"
+)
+
+type TalentListController struct {
+	web.Controller
+}
+
+func (c *TalentListController) CreateTalentListHandler() {
+	userID := c.Ctx.Input.GetData("id").(int)
+	user, err := models.GetUserById(userID)
+	if user == nil || err != nil {
+		c.Ctx.Output.SetStatus(http.StatusUnauthorized)
+		c.Ctx.Output.JSON(map[string]string{"error": "User not found"}, false, false)
+		return
+	}
+
+	if user.Role != "client" {
+		c.Ctx.Output.SetStatus(http.StatusForbidden)
+		c.Ctx.Output.JSON(map[string]string{"error": "Only clients can create talent lists"}, false, false)
+		return
+	}
+
+	talentListRequest, err := validators.TalentListValidator(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	talentListID, err := models.CreateTalentList(userID, talentListRequest.Name)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error creating talent list"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusCreated)
+	c.Data["json"] = map[string]interface{}{"message": "Talent list created successfully", "id": talentListID}
+	c.ServeJSON()
+}
+
+func (c *TalentListController) GetTalentListsHandler() {
+	userID := c.Ctx.Input.GetData("id").(int)
+
+	talentLists, err := models.GetTalentListsByClientID(userID)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching talent lists"}, false, false)
+		return
+	}
+
+	entryCounts, err := models.CountTalentListEntries(userID)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching talent lists"}, false, false)
+		return
+	}
+
+	talentListList := []types.TalentListInfo{}
+	for _, talentList := range talentLists {
+		talentListList = append(talentListList, types.TalentListInfo{
+			ID:              talentList.Id,
+			Name:            talentList.Name,
+			FreelancerCount: entryCounts[talentList.Id],
+			CreatedAt:       talentList.CreatedAt,
+		})
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = talentListList
+	c.ServeJSON()
+}
+
+func (c *TalentListController) GetTalentListHandler() {
+	talentList, ok := c.getOwnTalentList()
+	if !ok {
+		return
+	}
+
+	entries, err := models.GetTalentListEntries(talentList.Id)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching talent list"}, false, false)
+		return
+	}
+
+	talentListIDs, err := models.GetTalentListIDsByFreelancer(talentList.Client.Id)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error fetching talent list"}, false, false)
+		return
+	}
+
+	response := types.TalentListDetailedInfo{
+		ID:          talentList.Id,
+		Name:        talentList.Name,
+		CreatedAt:   talentList.CreatedAt,
+		Freelancers: []types.TalentListEntryInfo{},
+	}
+	for _, entry := range entries {
+		freelancer := entry.Freelancer
+
+		freelancerData, err := models.GetFreelancerDataByUserID(freelancer.Id)
+		if err != nil || freelancerData == nil {
+			continue
+		}
+
+		response.Freelancers = append(response.Freelancers, types.TalentListEntryInfo{
+			Freelancer: types.FreelancerInfo{
+				ID:            freelancer.Id,
+				Name:          freelancer.Name,
+				Surname:       freelancer.Surname,
+				Title:         freelancerData.Title,
+				HourlyRate:    freelancerData.HourlyRate,
+				TalentListIDs: talentListIDs[freelancer.Id],
+			},
+			Note:    entry.Note,
+			AddedAt: entry.CreatedAt,
+		})
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = response
+	c.ServeJSON()
+}
+
+func (c *TalentListController) UpdateTalentListHandler() {
+	talentList, ok := c.getOwnTalentList()
+	if !ok {
+		return
+	}
+
+	talentListRequest, err := validators.TalentListValidator(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	talentList.Name = talentListRequest.Name
+
+	err = models.UpdateTalentList(talentList)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error updating talent list"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": "Talent list updated successfully"}
+	c.ServeJSON()
+}
+
+func (c *TalentListController) DeleteTalentListHandler() {
+	talentList, ok := c.getOwnTalentList()
+	if !ok {
+		return
+	}
+
+	err := models.DeleteTalentListByID(talentList.Id)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error deleting talent list"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": "Talent list deleted successfully"}
+	c.ServeJSON()
+}
+
+func (c *TalentListController) AddTalentListFreelancerHandler() {
+	talentList, ok := c.getOwnTalentList()
+	if !ok {
+		return
+	}
+
+	talentListEntryRequest, err := validators.TalentListEntryValidator(c.Ctx.Input.RequestBody)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	freelancer, err := models.GetUserById(talentListEntryRequest.FreelancerID)
+	if freelancer == nil || err != nil || freelancer.Role != "freelancer" {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Freelancer not found"}, false, false)
+		return
+	}
+
+	if models.TalentListEntryExists(talentList.Id, freelancer.Id) {
+		c.Ctx.Output.SetStatus(http.StatusConflict)
+		c.Ctx.Output.JSON(map[string]string{"error": "Freelancer is already in the talent list"}, false, false)
+		return
+	}
+
+	err = models.AddFreelancerToTalentList(talentList.Id, freelancer.Id, talentListEntryRequest.Note)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error adding freelancer to talent list"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusCreated)
+	c.Data["json"] = map[string]string{"message": "Freelancer added to talent list successfully"}
+	c.ServeJSON()
+}
+
+func (c *TalentListController) RemoveTalentListFreelancerHandler() {
+	talentList, ok := c.getOwnTalentList()
+	if !ok {
+		return
+	}
+
+	freelancerID, err := strconv.Atoi(c.Ctx.Input.Param(":freelancerId"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid freelancer ID"}, false, false)
+		return
+	}
+
+	removed, err := models.RemoveFreelancerFromTalentList(talentList.Id, freelancerID)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Error removing freelancer from talent list"}, false, false)
+		return
+	}
+	if !removed {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Freelancer not found in talent list"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Data["json"] = map[string]string{"message": "Freelancer removed from talent list successfully"}
+	c.ServeJSON()
+}
+
+// getOwnTalentList loads the talent list from the :id parameter and writes the error response
+// when it does not exist or belongs to another client
+func (c *TalentListController) getOwnTalentList() (*models.TalentList, bool) {
+	talentListID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid talent list ID"}, false, false)
+		return nil, false
+	}
+
+	userID := c.Ctx.Input.GetData("id").(int)
+	talentList, err := models.GetTalentListByID(talentListID)
+	if err != nil || talentList.Client.Id != userID {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Talent list not found"}, false, false)
+		return nil, false
+	}
+
+	return talentList, true
+}
@@ -0,0 +1,206 @@
+package controllers
+
+import (
+	"backend/models"
+	"backend/storage"
+	"backend/types"
+	"backend/validators"
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/beego/beego/v2/server/web"
+)
+
+const tusVersion = "1.0.0"
+
+// UploadController receives resumable uploads of large files in the style of the tus protocol. The client
+// creates the upload with its length, sends the file in PATCH chunks and asks for the received offset with
+// HEAD to continue after a broken connection. Every chunk is stored as a part, so that any backend
+// container can receive the next one, and the last chunk joins the parts into the file. Completed
+// uploads are attached with their ID.
+type UploadController struct {
+	web.Controller
+}
+
+func (c *UploadController) CreateUploadHandler() {
+	createUploadRequest, err := validators.CreateUploadValidator(c.Ctx.Input.Header("Upload-Length"), c.Ctx.Input.Header("Upload-Metadata"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	maxSize := int64(web.AppConfig.DefaultInt("upload_max_size", 100)) << 20
+	if createUploadRequest.Length > maxSize {
+		c.Ctx.Output.SetStatus(http.StatusRequestEntityTooLarge)
+		c.Ctx.Output.JSON(map[string]string{"error": fmt.Sprintf("Uploads cannot be larger than %d MB", maxSize>>20)}, false, false)
+		return
+	}
+
+	userID := c.Ctx.Input.GetData("id").(int)
+	expiresAt := time.Now().Add(time.Duration(web.AppConfig.DefaultInt("upload_expiry_hours", 24)) * time.Hour)
+	upload, err := models.CreateUpload(userID, createUploadRequest.FileName, newStorageKey(createUploadRequest.FileName), createUploadRequest.Length, expiresAt)
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Failed to create upload"}, false, false)
+		return
+	}
+
+	c.setUploadHeaders(upload)
+	c.Ctx.Output.Header("Location", fmt.Sprintf("/user/uploads/%d", upload.Id))
+	c.Ctx.Output.SetStatus(http.StatusCreated)
+	c.Data["json"] = uploadInfo(upload)
+	c.ServeJSON()
+}
+
+// GetUploadHandler answers HEAD requests with the offset the client continues from
+func (c *UploadController) GetUploadHandler() {
+	upload, ok := c.getOwnUpload()
+	if !ok {
+		return
+	}
+
+	c.setUploadHeaders(upload)
+	c.Ctx.Output.Header("Cache-Control", "no-store")
+	c.Ctx.Output.SetStatus(http.StatusOK)
+	c.Ctx.Output.Body(nil)
+}
+
+// PatchUploadHandler stores the chunk at the Upload-Offset, which must be the offset received so far
+func (c *UploadController) PatchUploadHandler() {
+	upload, ok := c.getOwnUpload()
+	if !ok {
+		return
+	}
+
+	offset, err := validators.UploadChunkValidator(c.Ctx.Input.Header("Content-Type"), c.Ctx.Input.Header("Upload-Offset"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": err.Error()}, false, false)
+		return
+	}
+
+	chunk := c.Ctx.Input.RequestBody
+	if offset+int64(len(chunk)) > upload.Length {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "The chunk goes past the Upload-Length"}, false, false)
+		return
+	}
+
+	upload, err = models.ReceiveUploadChunk(upload.Id, offset, func(upload *models.Upload) (int64, error) {
+		return storeUploadChunk(upload, chunk)
+	})
+	if err != nil && err.Error() == "upload offset mismatch" {
+		c.Ctx.Output.SetStatus(http.StatusConflict)
+		c.Ctx.Output.JSON(map[string]string{"error": "Upload-Offset does not match the received offset"}, false, false)
+		return
+	}
+	if err != nil && err.Error() == "upload is completed" {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Upload is already completed"}, false, false)
+		return
+	}
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Failed to save upload chunk"}, false, false)
+		return
+	}
+
+	c.setUploadHeaders(upload)
+	c.Ctx.Output.SetStatus(http.StatusNoContent)
+	c.Ctx.Output.Body(nil)
+}
+
+// DeleteUploadHandler cancels the upload and removes what was received
+func (c *UploadController) DeleteUploadHandler() {
+	upload, ok := c.getOwnUpload()
+	if !ok {
+		return
+	}
+
+	for _, key := range append(upload.PartKeys(), upload.FilePath) {
+		if err := storage.Default().Delete(key); err != nil {
+			c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+			c.Ctx.Output.JSON(map[string]string{"error": "Failed to delete upload"}, false, false)
+			return
+		}
+	}
+
+	if err := models.DeleteUpload(upload.Id); err != nil {
+		c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+		c.Ctx.Output.JSON(map[string]string{"error": "Failed to delete upload"}, false, false)
+		return
+	}
+
+	c.Ctx.Output.Header("Tus-Resumable", tusVersion)
+	c.Ctx.Output.SetStatus(http.StatusNoContent)
+	c.Ctx.Output.Body(nil)
+}
+
+// getOwnUpload loads the user's upload from the :id parameter and writes the error response
+// when it does not exist or has expired
+func (c *UploadController) getOwnUpload() (*models.Upload, bool) {
+	uploadID, err := strconv.Atoi(c.Ctx.Input.Param(":id"))
+	if err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Ctx.Output.JSON(map[string]string{"error": "Invalid upload ID"}, false, false)
+		return nil, false
+	}
+
+	userID := c.Ctx.Input.GetData("id").(int)
+	upload, err := models.GetUpload(uploadID, userID)
+	if err != nil || upload.ExpiresAt.Before(time.Now()) {
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+		c.Ctx.Output.JSON(map[string]string{"error": "Upload not found"}, false, false)
+		return nil, false
+	}
+
+	return upload, true
+}
+
+func (c *UploadController) setUploadHeaders(upload *models.Upload) {
+	c.Ctx.Output.Header("Tus-Resumable", tusVersion)
+	c.Ctx.Output.Header("Upload-Offset", strconv.FormatInt(upload.Received, 10))
+	c.Ctx.Output.Header("Upload-Length", strconv.FormatInt(upload.Length, 10))
+	c.Ctx.Output.Header("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+}
+
+// storeUploadChunk stores the chunk as the next part of the upload. The last chunk joins the parts
+// into the upload's file and removes them, a failed join leaves the parts for the client to send the
+// last chunk again.
+func storeUploadChunk(upload *models.Upload, chunk []byte) (int64, error) {
+	store := storage.Default()
+	size := int64(len(chunk))
+	if err := store.Put(upload.PartKey(upload.Chunks), bytes.NewReader(chunk), size); err != nil {
+		return 0, err
+	}
+	if upload.Received+size < upload.Length {
+		return size, nil
+	}
+
+	partKeys := append(upload.PartKeys(), upload.PartKey(upload.Chunks))
+	parts := storage.ConcatReader(store, partKeys)
+	defer parts.Close()
+	if err := store.Put(upload.FilePath, parts, upload.Length); err != nil {
+		return 0, err
+	}
+
+	for _, key := range partKeys {
+		store.Delete(key)
+	}
+	return size, nil
+}
+
+func uploadInfo(upload *models.Upload) types.UploadInfo {
+	return types.UploadInfo{
+		ID:        upload.Id,
+		FileName:  upload.FileName,
+		Length:    upload.Length,
+		Offset:    upload.Received,
+		Status:    upload.Status,
+		ExpiresAt: upload.ExpiresAt,
+	}
+}
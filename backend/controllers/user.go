@@ -35,13 +35,11 @@ func (c *UserController) GetUserHandler() {
 	case "freelancer":
 		freelancerData, err := models.GetFreelancerDataByUserID(user.Id)
 		if err == nil && freelancerData != nil {
-			var skillList []types.Skill
-
-			for _, skill := range freelancerData.Skills {
-				skillList = append(skillList, types.Skill{
-					Id:   skill.Id,
-					Name: skill.Name,
-				})
+			skillList, err := freelancerSkillList(freelancerData.Id)
+			if err != nil {
+				c.Ctx.Output.SetStatus(http.StatusInternalServerError)
+				c.Ctx.Output.JSON(map[string]string{"error": "Error fetching freelancer skills"}, false, false)
+				return
 			}
 
 			response.FreelancerData = &types.FreelancerData{
@@ -96,8 +94,7 @@ func (c *UserController) UpdateUserHandler() {
 
 	if updateUserRequest.Email != "" {
 
-		existingUser, err := models.GetUserByEmail(updateUserRequest.Email)
-		if existingUser != nil || err == nil {
+		if models.IsEmailTaken(updateUserRequest.Email) {
 			c.Ctx.Output.SetStatus(http.StatusConflict)
 			c.Ctx.Output.JSON(map[string]string{"error": "Email already registered"}, false, false)
 			return
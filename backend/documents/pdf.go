@@ -0,0 +1,109 @@
+package documents
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidDocument is returned when the PDF tools cannot read the file, trying again does not help
+var ErrInvalidDocument = errors.New("document cannot be read")
+
+var pagesRegex = regexp.MustCompile(`(?m)^Pages:\s+([0-9]+)`)
+
+// Preview of a PDF
+type Preview struct {
+	Text      string // text of every page, cut at the maximum length
+	PageCount int
+	Thumbnail []byte // PNG of the first page
+}
+
+// PreviewPDF copies the PDF to a temporary directory and runs the poppler tools on it: pdfinfo for the
+// page count, pdftotext for the text and pdftoppm for the first page, scaled so that its longer side is
+// thumbnailSize pixels. Text longer than maxTextLength bytes is cut.
+func PreviewPDF(ctx context.Context, r io.Reader, maxTextLength, thumbnailSize int) (*Preview, error) {
+	dir, err := os.MkdirTemp("", "preview")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	pdfPath := filepath.Join(dir, "document.pdf")
+	file, err := os.Create(pdfPath)
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.Copy(file, r)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := run(ctx, "pdfinfo", pdfPath)
+	if err != nil {
+		return nil, err
+	}
+	match := pagesRegex.FindSubmatch(info)
+	if match == nil {
+		return nil, fmt.Errorf("%w: pdfinfo shows no page count", ErrInvalidDocument)
+	}
+	pageCount, _ := strconv.Atoi(string(match[1]))
+
+	text, err := run(ctx, "pdftotext", "-q", "-enc", "UTF-8", pdfPath, "-")
+	if err != nil {
+		return nil, err
+	}
+	if len(text) > maxTextLength {
+		text = text[:maxTextLength]
+	}
+
+	thumbnailPrefix := filepath.Join(dir, "thumbnail")
+	_, err = run(ctx, "pdftoppm", "-q", "-png", "-f", "1", "-l", "1", "-singlefile",
+		"-scale-to", strconv.Itoa(thumbnailSize), pdfPath, thumbnailPrefix)
+	if err != nil {
+		return nil, err
+	}
+	thumbnail, err := os.ReadFile(thumbnailPrefix + ".png")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Preview{
+		// PostgreSQL text cannot hold NUL bytes or invalid UTF-8, which a cut can leave at the end
+		Text:      strings.ToValidUTF8(strings.ReplaceAll(string(text), "\x00", ""), ""),
+		PageCount: pageCount,
+		Thumbnail: thumbnail,
+	}, nil
+}
+
+// run returns the output of the tool. A tool that fails or runs out of time means the document
+// cannot be read, a tool that is not installed is a plain error.
+func run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("%w: %s timed out", ErrInvalidDocument, name)
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return nil, fmt.Errorf("%w: %s: %s", ErrInvalidDocument, name, strings.TrimSpace(stderr.String()))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
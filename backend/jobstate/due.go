@@ -0,0 +1,42 @@
+package jobstate
+
+import (
+	"backend/models"
+	"log"
+)
+
+// PublishDueJobs opens the scheduled jobs whose publish time has come and returns how many were published.
+// A job that fails is logged and left for the next run, so it does not hold up the others.
+func PublishDueJobs() (int, error) {
+	jobs, err := models.GetDueScheduledJobs()
+	if err != nil {
+		return 0, err
+	}
+
+	return fireAll(jobs, Publish), nil
+}
+
+// ExpireDueJobs closes the open jobs past their expiry and returns how many jobs expired.
+// A job that fails is logged and left for the next run, so it does not hold up the others.
+func ExpireDueJobs() (int, error) {
+	jobs, err := models.GetDueExpiredJobs()
+	if err != nil {
+		return 0, err
+	}
+
+	return fireAll(jobs, Expire), nil
+}
+
+// fireAll fires the event on every job and returns how many jobs transitioned
+func fireAll(jobs []models.Job, event string) int {
+	fired := 0
+	for i := range jobs {
+		if err := Fire(&jobs[i], Change{Event: event}); err != nil {
+			log.Printf("Error firing %s on job %d: %v", event, jobs[i].Id, err)
+			continue
+		}
+		fired++
+	}
+
+	return fired
+}
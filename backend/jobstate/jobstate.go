@@ -0,0 +1,338 @@
+// Package jobstate is the state machine of job statuses. Every status change of a job goes
+// through Fire, which checks the transition is allowed, applies its side effects on
+// applications and records it in the job's status history.
+package jobstate
+
+import (
+	"backend/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// Job statuses
+const (
+	Draft      = "draft"
+	Scheduled  = "scheduled"
+	Open       = "open"
+	Paused     = "paused"
+	InProgress = "in-progress"
+	Completed  = "completed"
+	Expired    = "expired"
+	Cancelled  = "cancelled"
+)
+
+// Events that change the status of a job
+const (
+	Publish   = "publish"   // a draft or scheduled job opens now
+	Schedule  = "schedule"  // a draft or scheduled job opens at its publish time
+	Hire      = "hire"      // an offer is mutually accepted, the job is in progress once all openings are filled
+	Complete  = "complete"  // the client marks the work as done
+	Expire    = "expire"    // an open job reaches its expiry
+	Republish = "republish" // an expired job opens again
+	Cancel    = "cancel"    // the client stops the job for good
+	Pause     = "pause"     // the client puts the job on hold
+	Resume    = "resume"    // a paused job returns to the status it had before the pause
+	Reopen    = "reopen"    // the job looks for freelancers again, after a freelancer dropped out or a cancellation
+	Withdraw  = "withdraw"  // the client acknowledges a hired freelancer's cancellation request, the opening is free again
+)
+
+// The queries the guards and targets need, tests replace them to check transitions without a database
+var (
+	countJobAssignments = models.CountJobAssignments
+	statusBeforePause   = models.GetStatusBeforePause
+)
+
+// ErrNotAllowed is returned by Fire when the job changed since it was read and the event is no longer allowed
+var ErrNotAllowed = errors.New("the job changed in the meantime")
+
+type transition struct {
+	from  []string
+	to    string                  // empty when the target depends on the job, see target
+	guard func(*models.Job) error // extra condition on top of the source status
+}
+
+var transitions = map[string]transition{
+	Publish:   {from: []string{Draft, Scheduled}, to: Open},
+	Schedule:  {from: []string{Draft, Scheduled}, to: Scheduled, guard: publishTimeAhead},
+	Hire:      {from: []string{Open}, guard: hasOpenings},
+	Complete:  {from: []string{InProgress}, to: Completed, guard: hasFreelancers},
+	Expire:    {from: []string{Open}, to: Expired},
+	Republish: {from: []string{Expired}, to: Open},
+	Cancel:    {from: []string{Draft, Scheduled, Open, Paused, InProgress}, to: Cancelled},
+	Pause:     {from: []string{Open, InProgress}, to: Paused},
+	Resume:    {from: []string{Paused}},
+	Reopen:    {from: []string{InProgress, Cancelled}, to: Open},
+	Withdraw:  {from: []string{Open, InProgress}, to: Open},
+}
+
+func publishTimeAhead(job *models.Job) error {
+	if job.PublishAt == nil || !job.PublishAt.After(time.Now()) {
+		return fmt.Errorf("publish time must be in the future to schedule a job")
+	}
+	return nil
+}
+
+func hasOpenings(job *models.Job) error {
+	hired, err := countJobAssignments(job.Id)
+	if err != nil {
+		return err
+	}
+	if hired >= job.Openings {
+		return fmt.Errorf("all openings of the job are filled")
+	}
+	return nil
+}
+
+func hasFreelancers(job *models.Job) error {
+	hired, err := countJobAssignments(job.Id)
+	if err != nil {
+		return err
+	}
+	if hired == 0 {
+		return fmt.Errorf("job has no hired freelancers")
+	}
+	return nil
+}
+
+// Change is a status change requested on a job
+type Change struct {
+	Event        string
+	ActorID      int                         // user making the change, 0 for the scheduler
+	Reason       string                      // optional, shown in the status history and notifications
+	Application  *models.Application         // hire: the application being accepted
+	Offer        *models.Offer               // hire: the accepted offer
+	FreelancerID int                         // reopen: the freelancer leaving the job, 0 for every hired freelancer
+	Cancellation *models.CancellationRequest // withdraw: the acknowledged request, with its application
+}
+
+// Can reports whether the event is allowed for the job in its current status.
+// The error explains why not and can be shown to the user.
+func Can(job *models.Job, event string) error {
+	t, ok := transitions[event]
+	if !ok {
+		return fmt.Errorf("unknown job event '%s'", event)
+	}
+
+	allowed := false
+	for _, status := range t.from {
+		if job.Status == status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("cannot %s a job that is %s", event, job.Status)
+	}
+
+	if t.guard != nil {
+		return t.guard(job)
+	}
+	return nil
+}
+
+// target returns the status the event moves the job to
+func target(job *models.Job, event string, hired []int) (string, error) {
+	switch event {
+	case Hire:
+		// The job keeps taking applications until the last opening is filled
+		if len(hired)+1 >= job.Openings {
+			return InProgress, nil
+		}
+		return Open, nil
+
+	case Resume:
+		status, err := statusBeforePause(job.Id)
+		if err != nil && err != orm.ErrNoRows {
+			return "", err
+		}
+		if status == "" {
+			return Open, nil
+		}
+		return status, nil
+	}
+
+	return transitions[event].to, nil
+}
+
+// Fire moves the job to the status of the change's event. The status, the side effects on
+// applications and the history record are saved together, notifications and queued work follow.
+// The job is locked and checked again inside the transaction, so that concurrent changes, such
+// as two freelancers taking the last opening, are applied one after the other.
+func Fire(job *models.Job, change Change) error {
+	original := job.Status
+	var from string
+	var hired []int
+
+	o := orm.NewOrm()
+	err := o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		current, err := models.LockJob(txOrm, job.Id)
+		if err != nil {
+			return err
+		}
+		job.Status = current.Status
+		job.Openings = current.Openings
+		from = current.Status
+
+		// The checks read after the lock is taken, a change that held it before is committed by now
+		if err := Can(job, change.Event); err != nil {
+			return fmt.Errorf("%w: %v", ErrNotAllowed, err)
+		}
+
+		hired, err = models.GetJobFreelancerIDs(job.Id)
+		if err != nil {
+			return err
+		}
+
+		to, err := target(job, change.Event, hired)
+		if err != nil {
+			return err
+		}
+		job.Status = to
+
+		columns, err := apply(txOrm, job, change)
+		if err != nil {
+			return err
+		}
+
+		if _, err := txOrm.Update(job, append(columns, "Status")...); err != nil {
+			return err
+		}
+
+		return models.CreateJobStatusHistory(txOrm, job.Id, from, to, change.Event, change.ActorID, change.Reason)
+	})
+	if err != nil {
+		job.Status = original
+		return err
+	}
+
+	after(job, change, from, hired)
+	return nil
+}
+
+// apply makes the changes the event needs besides the status and returns the job columns it changed
+func apply(o orm.QueryExecutor, job *models.Job, change Change) ([]string, error) {
+	now := time.Now()
+
+	switch change.Event {
+	case Publish:
+		// Scheduled jobs opened by the scheduler keep their publish time
+		if job.PublishAt == nil || job.PublishAt.After(now) {
+			job.PublishAt = &now
+		}
+		return []string{"PublishAt", "ExpiresAt"}, nil
+
+	case Schedule:
+		return []string{"PublishAt", "ExpiresAt"}, nil
+
+	case Republish:
+		job.PublishAt = &now
+		return []string{"PublishAt", "ExpiresAt"}, nil
+
+	case Hire:
+		if change.Offer == nil {
+			return nil, fmt.Errorf("hiring needs an accepted offer")
+		}
+		if err := models.AcceptOffer(o, change.Offer); err != nil {
+			return nil, err
+		}
+		if err := models.AcceptApplication(o, change.Application); err != nil {
+			return nil, err
+		}
+		if err := models.CreateJobAssignment(o, job.Id, change.Application.User.Id, change.Application.Id, change.Offer); err != nil {
+			return nil, err
+		}
+		if job.Status == InProgress {
+			return nil, models.RejectPendingApplications(o, job.Id, 0,
+				"Your application was automatically rejected because all openings of the job were filled.")
+		}
+
+	case Expire:
+		err := models.RejectPendingApplications(o, job.Id, 0, "Your application was automatically rejected because the job expired.")
+		if err != nil {
+			return nil, err
+		}
+		return nil, models.RefundExpiredJobCredits(o, job.Id)
+
+	case Cancel:
+		return nil, models.RejectPendingApplications(o, job.Id, 0, "Your application was automatically rejected because the job was cancelled.")
+
+	case Reopen:
+		err := models.RejectAcceptedApplications(o, job.Id, change.FreelancerID, "The client reopened the job for other freelancers.")
+		if err != nil {
+			return nil, err
+		}
+		return nil, models.DeleteJobAssignments(o, job.Id, change.FreelancerID)
+
+	case Withdraw:
+		if err := models.AcknowledgeCancellationRequest(o, change.Cancellation); err != nil {
+			return nil, err
+		}
+		return nil, models.DeleteJobAssignments(o, job.Id, change.Cancellation.Application.User.Id)
+	}
+
+	return nil, nil
+}
+
+// after notifies the people involved and queues the work that follows the change. Failures are
+// not returned, the status change is already saved.
+func after(job *models.Job, change Change, from string, hired []int) {
+	if job.Status == Open || from == Open {
+		models.QueueJobMatchScoreRefresh(job.Id)
+	}
+
+	link := fmt.Sprintf("/user/client/jobs/%d", job.Id)
+	freelancerLink := fmt.Sprintf("/user/freelancer/jobs/%d", job.Id)
+
+	message := change.Reason
+	if message == "" {
+		message = fmt.Sprintf("The job is now %s.", job.Status)
+	}
+
+	switch change.Event {
+	case Publish, Republish:
+		models.QueueJobAlerts(job.Id)
+
+	case Hire:
+		if change.ActorID == change.Application.User.Id {
+			models.CreateNotification(job.Client.Id, "job-status", fmt.Sprintf("Your offer for \"%s\" was accepted", job.Title),
+				"The freelancer accepted the offer and is hired.", link)
+		} else {
+			models.CreateNotification(change.Application.User.Id, "job-status", fmt.Sprintf("You were hired for \"%s\"", job.Title),
+				"The client accepted your terms.", freelancerLink)
+		}
+
+	case Complete:
+		// The completed job adds to the freelancers' history
+		for _, freelancerID := range hired {
+			models.QueueFreelancerMatchScoreRefresh(freelancerID)
+		}
+
+	case Expire:
+		models.CreateNotification(job.Client.Id, "job-expired", fmt.Sprintf("\"%s\" expired", job.Title),
+			"The job is no longer open for applications. You can republish it with a new expiry.", link)
+
+	case Cancel, Pause, Resume:
+		// Only hired freelancers are affected, applicants see the job close through their applications
+		for _, freelancerID := range hired {
+			models.CreateNotification(freelancerID, "job-status", fmt.Sprintf("\"%s\" is now %s", job.Title, job.Status),
+				message, freelancerLink)
+		}
+
+	case Reopen:
+		for _, freelancerID := range hired {
+			if change.FreelancerID != 0 && freelancerID != change.FreelancerID {
+				continue
+			}
+			models.CreateNotification(freelancerID, "job-status", fmt.Sprintf("You were removed from \"%s\"", job.Title),
+				message, "/user/freelancer/applications")
+		}
+
+	case Withdraw:
+		models.CreateNotification(change.Cancellation.Application.User.Id, "job-status", fmt.Sprintf("You left \"%s\"", job.Title),
+			"The client acknowledged your cancellation request.", "/user/freelancer/applications")
+	}
+}
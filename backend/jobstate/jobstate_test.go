@@ -0,0 +1,175 @@
+package jobstate
+
+import (
+	"backend/models"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+)
+
+var statuses = []string{Draft, Scheduled, Open, Paused, InProgress, Completed, Expired, Cancelled}
+
+// allowed lists the statuses every event starts from, written out apart from the transitions table
+var allowed = map[string][]string{
+	Publish:   {Draft, Scheduled},
+	Schedule:  {Draft, Scheduled},
+	Hire:      {Open},
+	Complete:  {InProgress},
+	Expire:    {Open},
+	Republish: {Expired},
+	Cancel:    {Draft, Scheduled, Open, Paused, InProgress},
+	Pause:     {Open, InProgress},
+	Resume:    {Paused},
+	Reopen:    {InProgress, Cancelled},
+	Withdraw:  {Open, InProgress},
+}
+
+// fakeAssignments makes the guards see the number of hired freelancers
+func fakeAssignments(t *testing.T, hired int) {
+	countJobAssignments = func(jobID int) (int, error) { return hired, nil }
+	t.Cleanup(func() { countJobAssignments = models.CountJobAssignments })
+}
+
+func TestCan(t *testing.T) {
+	// One of two openings filled, so the guards of Hire and Complete pass
+	fakeAssignments(t, 1)
+	publishAt := time.Now().Add(time.Hour)
+
+	if len(allowed) != len(transitions) {
+		t.Fatalf("the test covers %d events, the state machine has %d", len(allowed), len(transitions))
+	}
+
+	for event, from := range allowed {
+		for _, status := range statuses {
+			job := &models.Job{Id: 1, Status: status, Openings: 2, PublishAt: &publishAt}
+			want := false
+			for _, s := range from {
+				want = want || s == status
+			}
+
+			err := Can(job, event)
+			if want && err != nil {
+				t.Errorf("Can(%s, %s) = %v, want allowed", status, event, err)
+			}
+			if !want && err == nil {
+				t.Errorf("Can(%s, %s) allowed, want rejected", status, event)
+			}
+		}
+	}
+}
+
+func TestCanGuards(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name  string
+		event string
+		job   models.Job
+		hired int
+		ok    bool
+	}{
+		{"schedule in the future", Schedule, models.Job{Status: Draft, PublishAt: &future}, 0, true},
+		{"schedule in the past", Schedule, models.Job{Status: Draft, PublishAt: &past}, 0, false},
+		{"schedule without a publish time", Schedule, models.Job{Status: Scheduled}, 0, false},
+		{"hire with free openings", Hire, models.Job{Status: Open, Openings: 3}, 2, true},
+		{"hire with every opening filled", Hire, models.Job{Status: Open, Openings: 3}, 3, false},
+		{"hire the only opening", Hire, models.Job{Status: Open, Openings: 1}, 0, true},
+		{"complete with a hired freelancer", Complete, models.Job{Status: InProgress, Openings: 1}, 1, true},
+		{"complete without hired freelancers", Complete, models.Job{Status: InProgress, Openings: 1}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeAssignments(t, tt.hired)
+
+			err := Can(&tt.job, tt.event)
+			if tt.ok && err != nil {
+				t.Errorf("Can = %v, want allowed", err)
+			}
+			if !tt.ok && err == nil {
+				t.Error("Can allowed, want rejected")
+			}
+		})
+	}
+}
+
+func TestCanUnknownEvent(t *testing.T) {
+	if err := Can(&models.Job{Status: Open}, "archive"); err == nil {
+		t.Error("Can allowed an unknown event")
+	}
+}
+
+func TestTargetFixed(t *testing.T) {
+	tests := map[string]string{
+		Publish:   Open,
+		Schedule:  Scheduled,
+		Complete:  Completed,
+		Expire:    Expired,
+		Republish: Open,
+		Cancel:    Cancelled,
+		Pause:     Paused,
+		Reopen:    Open,
+		Withdraw:  Open,
+	}
+
+	for event, want := range tests {
+		got, err := target(&models.Job{Id: 1, Status: allowed[event][0], Openings: 1}, event, nil)
+		if err != nil || got != want {
+			t.Errorf("target(%s) = %s, %v, want %s", event, got, err, want)
+		}
+	}
+}
+
+func TestTargetHire(t *testing.T) {
+	tests := []struct {
+		openings int
+		hired    []int
+		want     string
+	}{
+		{1, nil, InProgress},
+		{2, nil, Open},
+		{2, []int{7}, InProgress},
+		{3, []int{7}, Open},
+		{3, []int{7, 8}, InProgress},
+	}
+
+	for _, tt := range tests {
+		got, err := target(&models.Job{Id: 1, Status: Open, Openings: tt.openings}, Hire, tt.hired)
+		if err != nil || got != tt.want {
+			t.Errorf("target(hire) with %d of %d openings filled = %s, %v, want %s", len(tt.hired), tt.openings, got, err, tt.want)
+		}
+	}
+}
+
+func TestTargetResume(t *testing.T) {
+	dbErr := errors.New("connection refused")
+
+	tests := []struct {
+		name    string
+		before  string
+		err     error
+		want    string
+		wantErr error
+	}{
+		{"paused while open", Open, nil, Open, nil},
+		{"paused while in progress", InProgress, nil, InProgress, nil},
+		{"no pause in the history", "", orm.ErrNoRows, Open, nil},
+		{"empty status in the history", "", nil, Open, nil},
+		{"history unavailable", "", dbErr, "", dbErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statusBeforePause = func(jobID int) (string, error) { return tt.before, tt.err }
+			defer func() { statusBeforePause = models.GetStatusBeforePause }()
+
+			got, err := target(&models.Job{Id: 1, Status: Paused}, Resume, nil)
+			if err != tt.wantErr || got != tt.want {
+				t.Errorf("target(resume) = %s, %v, want %s, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
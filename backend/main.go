@@ -4,6 +4,7 @@ import (
 	"backend/database"
 	_ "backend/routers"
 	"backend/seeder"
+	"backend/tasks"
 
 	"github.com/beego/beego/v2/server/web"
 	"github.com/beego/beego/v2/server/web/filter/cors"
@@ -16,12 +17,15 @@ func main() {
 	// Seed database with initial data
 	seeder.SeedDatabase()
 
+	// Start background tasks
+	tasks.StartTasks()
+
 	// Initialize CORS
 	web.InsertFilter("*", web.BeforeRouter, cors.Allow(&cors.Options{
 		AllowOrigins:     []string{"*"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length", "Content-Disposition"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "HEAD", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Tus-Resumable", "Upload-Length", "Upload-Metadata", "Upload-Offset"},
+		ExposeHeaders:    []string{"Content-Length", "Content-Disposition", "Location", "Tus-Resumable", "Upload-Length", "Upload-Offset", "Upload-Expires"},
 		AllowCredentials: true,
 	}))
 
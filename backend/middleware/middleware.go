@@ -2,28 +2,40 @@ package middleware
 
 import (
 	"backend/models"
+	"backend/types"
 	"backend/utils"
+	"errors"
 	"net/http"
 	"strings"
 
+	"github.com/beego/beego/v2/server/web"
 	"github.com/beego/beego/v2/server/web/context"
 )
 
+// multipartMemory is how much of a multipart request's files is kept in memory, the rest is
+// spooled to temporary files
+const multipartMemory = 1 << 20
+
 // A middleware to protect routes with JWT authentication
 func UserAuthMiddleware(ctx *context.Context) {
 
+	// Uploads are already authenticated by MultipartUploadChain
+	if _, ok := ctx.Input.GetData("id").(int); ok {
+		return
+	}
+
 	if ctx.Request.Method == "OPTIONS" {
 		ctx.ResponseWriter.Header().Set("Access-Control-Allow-Origin", "*")
-		ctx.ResponseWriter.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		ctx.ResponseWriter.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization")
+		ctx.ResponseWriter.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, HEAD, DELETE, OPTIONS")
+		ctx.ResponseWriter.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization, Tus-Resumable, Upload-Length, Upload-Metadata, Upload-Offset")
 		ctx.ResponseWriter.Header().Set("Access-Control-Allow-Credentials", "true")
 		ctx.Output.SetStatus(http.StatusOK)
 		return
 	}
 
 	ctx.ResponseWriter.Header().Set("Access-Control-Allow-Origin", "*")
-	ctx.ResponseWriter.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-	ctx.ResponseWriter.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization")
+	ctx.ResponseWriter.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, HEAD, DELETE, OPTIONS")
+	ctx.ResponseWriter.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization, Tus-Resumable, Upload-Length, Upload-Metadata, Upload-Offset")
 	ctx.ResponseWriter.Header().Set("Access-Control-Allow-Credentials", "true")
 
 	// Get the token from the Authorization header
@@ -44,6 +56,14 @@ func UserAuthMiddleware(ctx *context.Context) {
 		return
 	}
 
+	// Fetching the active ban first lifts any ban that has already expired
+	ban, err := models.GetActiveBanByUserID(claims.Id)
+	if err != nil {
+		ctx.Output.SetStatus(http.StatusInternalServerError)
+		ctx.Output.JSON(map[string]string{"error": "Error fetching user data"}, false, false)
+		return
+	}
+
 	banStatus, err := models.IsUserBanned(claims.Id)
 	if err != nil {
 		ctx.Output.SetStatus(http.StatusInternalServerError)
@@ -52,8 +72,19 @@ func UserAuthMiddleware(ctx *context.Context) {
 	}
 
 	if banStatus {
+		response := types.BannedUserResponse{
+			Error:     "User is banned",
+			Permanent: true,
+		}
+		if ban != nil {
+			response.BanID = ban.Id
+			response.Reason = ban.Reason
+			response.BannedUntil = ban.EndsAt
+			response.Permanent = ban.EndsAt == nil
+		}
+
 		ctx.Output.SetStatus(http.StatusForbidden)
-		ctx.Output.JSON(map[string]string{"error": "User is banned"}, false, false)
+		ctx.Output.JSON(response, false, false)
 		return
 	}
 
@@ -103,3 +134,100 @@ func AdminAuthMiddleware(ctx *context.Context) {
 	// Attach user id to the context for further use
 	ctx.Input.SetData("id", claims.Id)
 }
+
+// A middleware to protect routes with JWT authentication without checking the ban status
+// This middleware is used for routes banned users still need, such as submitting a ban appeal
+func AppealAuthMiddleware(ctx *context.Context) {
+
+	if ctx.Request.Method == "OPTIONS" {
+		ctx.ResponseWriter.Header().Set("Access-Control-Allow-Origin", "*")
+		ctx.ResponseWriter.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		ctx.ResponseWriter.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization")
+		ctx.ResponseWriter.Header().Set("Access-Control-Allow-Credentials", "true")
+		ctx.Output.SetStatus(http.StatusOK)
+		return
+	}
+
+	ctx.ResponseWriter.Header().Set("Access-Control-Allow-Origin", "*")
+	ctx.ResponseWriter.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+	ctx.ResponseWriter.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization")
+	ctx.ResponseWriter.Header().Set("Access-Control-Allow-Credentials", "true")
+
+	tokenString := strings.TrimPrefix(ctx.Input.Header("Authorization"), "Bearer ")
+
+	if tokenString == "" {
+		ctx.Output.SetStatus(http.StatusUnauthorized)
+		ctx.Output.JSON(map[string]string{"error": "Missing access token"}, false, false)
+		return
+	}
+
+	claims, err := utils.ValidateAccessToken(tokenString)
+	if err != nil {
+		ctx.Output.SetStatus(http.StatusUnauthorized)
+		ctx.Output.JSON(map[string]string{"error": "Invalid access token"}, false, false)
+		return
+	}
+
+	// Attach user id to the context for further use
+	ctx.Input.SetData("id", claims.Id)
+}
+
+// A filter chain that parses multipart/form-data uploads before the router does. Beego would keep
+// the files in memory up to MaxMemory, here they are spooled to temporary files that the handlers
+// stream to the file storage, and requests over upload_max_request_size are refused. Beego parses
+// the body before the BeforeRouter filters run, so the user is authenticated here before any of it is read.
+func MultipartUploadChain(next web.FilterFunc) web.FilterFunc {
+	return func(ctx *context.Context) {
+		if ctx.Input.IsUpload() {
+			UserAuthMiddleware(ctx)
+			if ctx.ResponseWriter.Started {
+				return
+			}
+
+			maxSize := int64(web.AppConfig.DefaultInt("upload_max_request_size", 30)) << 20
+			ctx.Request.Body = http.MaxBytesReader(ctx.ResponseWriter, ctx.Request.Body, maxSize)
+
+			if err := ctx.Request.ParseMultipartForm(multipartMemory); err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					ctx.Output.SetStatus(http.StatusRequestEntityTooLarge)
+					ctx.Output.JSON(map[string]string{"error": "Upload is too large"}, false, false)
+					return
+				}
+				ctx.Output.SetStatus(http.StatusBadRequest)
+				ctx.Output.JSON(map[string]string{"error": "Invalid multipart form"}, false, false)
+				return
+			}
+		}
+
+		next(ctx)
+	}
+}
+
+// A filter chain that limits the chunks of resumable uploads to upload_max_request_size. Beego copies
+// the body into memory before the BeforeRouter filters run, so the user is authenticated and the
+// Content-Length checked here before any of it is read.
+func UploadChunkChain(next web.FilterFunc) web.FilterFunc {
+	return func(ctx *context.Context) {
+		if ctx.Request.Method == http.MethodPatch {
+			UserAuthMiddleware(ctx)
+			if ctx.ResponseWriter.Started {
+				return
+			}
+
+			maxSize := int64(web.AppConfig.DefaultInt("upload_max_request_size", 30)) << 20
+			if ctx.Request.ContentLength < 0 {
+				ctx.Output.SetStatus(http.StatusLengthRequired)
+				ctx.Output.JSON(map[string]string{"error": "Content-Length is required"}, false, false)
+				return
+			}
+			if ctx.Request.ContentLength > maxSize {
+				ctx.Output.SetStatus(http.StatusRequestEntityTooLarge)
+				ctx.Output.JSON(map[string]string{"error": "Upload chunk is too large"}, false, false)
+				return
+			}
+		}
+
+		next(ctx)
+	}
+}
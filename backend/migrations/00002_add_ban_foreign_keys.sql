@@ -0,0 +1,25 @@
+-- +goose Up
+ALTER TABLE bans
+  ADD CONSTRAINT fk_ban_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  ADD CONSTRAINT fk_ban_admin FOREIGN KEY (admin_id) REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE ban_appeals
+  ADD CONSTRAINT fk_ban_appeal_ban FOREIGN KEY (ban_id) REFERENCES bans(id) ON DELETE CASCADE,
+  ADD CONSTRAINT fk_ban_appeal_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  ADD CONSTRAINT fk_ban_appeal_reviewer FOREIGN KEY (reviewed_by_id) REFERENCES users(id) ON DELETE SET NULL;
+
+-- Users banned before ban records existed get a permanent ban record
+INSERT INTO bans (user_id, reason, starts_at, created_at)
+  SELECT id, '', NOW(), NOW() FROM users WHERE ban = true;
+
+
+
+-- +goose Down
+ALTER TABLE bans
+  DROP CONSTRAINT fk_ban_user,
+  DROP CONSTRAINT fk_ban_admin;
+
+ALTER TABLE ban_appeals
+  DROP CONSTRAINT fk_ban_appeal_ban,
+  DROP CONSTRAINT fk_ban_appeal_user,
+  DROP CONSTRAINT fk_ban_appeal_reviewer;
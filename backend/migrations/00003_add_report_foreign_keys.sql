@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE reports
+  ADD CONSTRAINT fk_report_reporter FOREIGN KEY (reporter_id) REFERENCES users(id) ON DELETE CASCADE,
+  ADD CONSTRAINT fk_report_resolver FOREIGN KEY (resolved_by_id) REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_report_target ON reports (target_type, target_id);
+
+
+
+-- +goose Down
+DROP INDEX idx_report_target;
+
+ALTER TABLE reports
+  DROP CONSTRAINT fk_report_reporter,
+  DROP CONSTRAINT fk_report_resolver;
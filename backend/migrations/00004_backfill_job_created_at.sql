@@ -0,0 +1,15 @@
+-- +goose Up
+-- Jobs posted before created_at existed fall back to their earliest application, or the migration time
+UPDATE jobs j SET created_at = COALESCE(
+  (SELECT MIN(a.created_at) FROM applications a WHERE a.job_id = j.id),
+  NOW()
+) WHERE j.created_at IS NULL;
+
+CREATE INDEX idx_job_created_at ON jobs (created_at);
+CREATE INDEX idx_application_created_at ON applications (created_at);
+
+
+
+-- +goose Down
+DROP INDEX idx_job_created_at;
+DROP INDEX idx_application_created_at;
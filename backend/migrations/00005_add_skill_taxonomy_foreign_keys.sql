@@ -0,0 +1,27 @@
+-- +goose Up
+ALTER TABLE skill_categories
+  ADD CONSTRAINT fk_skill_category_parent FOREIGN KEY (parent_id) REFERENCES skill_categories(id) ON DELETE SET NULL;
+
+ALTER TABLE skills
+  ADD CONSTRAINT fk_skill_category FOREIGN KEY (category_id) REFERENCES skill_categories(id) ON DELETE SET NULL;
+
+ALTER TABLE skill_aliases
+  ADD CONSTRAINT fk_skill_alias_skill FOREIGN KEY (skill_id) REFERENCES skills(id) ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX idx_skill_name_lower ON skills (LOWER(name));
+CREATE UNIQUE INDEX idx_skill_alias_name_lower ON skill_aliases (LOWER(name));
+
+
+
+-- +goose Down
+DROP INDEX idx_skill_alias_name_lower;
+DROP INDEX idx_skill_name_lower;
+
+ALTER TABLE skill_aliases
+  DROP CONSTRAINT fk_skill_alias_skill;
+
+ALTER TABLE skills
+  DROP CONSTRAINT fk_skill_category;
+
+ALTER TABLE skill_categories
+  DROP CONSTRAINT fk_skill_category_parent;
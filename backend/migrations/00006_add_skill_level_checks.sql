@@ -0,0 +1,23 @@
+-- +goose Up
+ALTER TABLE freelancer_skills
+  ADD CONSTRAINT chk_freelancer_skill_level CHECK (level IN ('beginner', 'intermediate', 'advanced', 'expert')),
+  ADD CONSTRAINT chk_freelancer_skill_years CHECK (years_of_experience >= 0);
+
+ALTER TABLE job_skills
+  ADD CONSTRAINT chk_job_skill_min_level CHECK (min_level IS NULL OR min_level IN ('', 'beginner', 'intermediate', 'advanced', 'expert'));
+
+CREATE INDEX idx_freelancer_skill_skill ON freelancer_skills (skills_id);
+CREATE INDEX idx_job_skill_skill ON job_skills (skills_id);
+
+
+
+-- +goose Down
+DROP INDEX idx_job_skill_skill;
+DROP INDEX idx_freelancer_skill_skill;
+
+ALTER TABLE job_skills
+  DROP CONSTRAINT chk_job_skill_min_level;
+
+ALTER TABLE freelancer_skills
+  DROP CONSTRAINT chk_freelancer_skill_level,
+  DROP CONSTRAINT chk_freelancer_skill_years;
@@ -0,0 +1,17 @@
+-- +goose Up
+ALTER TABLE match_scores
+  ADD CONSTRAINT fk_match_score_job FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE,
+  ADD CONSTRAINT fk_match_score_freelancer FOREIGN KEY (freelancer_id) REFERENCES users(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_match_score_freelancer ON match_scores (freelancer_id, score DESC);
+CREATE INDEX idx_match_score_job ON match_scores (job_id, score DESC);
+
+
+
+-- +goose Down
+DROP INDEX idx_match_score_job;
+DROP INDEX idx_match_score_freelancer;
+
+ALTER TABLE match_scores
+  DROP CONSTRAINT fk_match_score_job,
+  DROP CONSTRAINT fk_match_score_freelancer;
@@ -0,0 +1,41 @@
+-- +goose Up
+ALTER TABLE notifications
+  ADD CONSTRAINT fk_notification_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE saved_searches
+  ADD CONSTRAINT fk_saved_search_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE saved_search_skills
+  ADD CONSTRAINT fk_saved_search_skill_search FOREIGN KEY (saved_searches_id) REFERENCES saved_searches(id) ON DELETE CASCADE,
+  ADD CONSTRAINT fk_saved_search_skill_skill FOREIGN KEY (skills_id) REFERENCES skills(id) ON DELETE CASCADE;
+
+ALTER TABLE saved_search_matches
+  ADD CONSTRAINT fk_saved_search_match_search FOREIGN KEY (saved_search_id) REFERENCES saved_searches(id) ON DELETE CASCADE,
+  ADD CONSTRAINT fk_saved_search_match_job FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE;
+
+ALTER TABLE pending_job_alerts
+  ADD CONSTRAINT fk_pending_job_alert_job FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_notification_user ON notifications (user_id, created_at DESC);
+
+
+
+-- +goose Down
+DROP INDEX idx_notification_user;
+
+ALTER TABLE pending_job_alerts
+  DROP CONSTRAINT fk_pending_job_alert_job;
+
+ALTER TABLE saved_search_matches
+  DROP CONSTRAINT fk_saved_search_match_search,
+  DROP CONSTRAINT fk_saved_search_match_job;
+
+ALTER TABLE saved_search_skills
+  DROP CONSTRAINT fk_saved_search_skill_search,
+  DROP CONSTRAINT fk_saved_search_skill_skill;
+
+ALTER TABLE saved_searches
+  DROP CONSTRAINT fk_saved_search_user;
+
+ALTER TABLE notifications
+  DROP CONSTRAINT fk_notification_user;
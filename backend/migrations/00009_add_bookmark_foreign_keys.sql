@@ -0,0 +1,31 @@
+-- +goose Up
+ALTER TABLE job_bookmarks
+  ADD CONSTRAINT fk_job_bookmark_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  ADD CONSTRAINT fk_job_bookmark_job FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE;
+
+ALTER TABLE talent_lists
+  ADD CONSTRAINT fk_talent_list_client FOREIGN KEY (client_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE talent_list_entries
+  ADD CONSTRAINT fk_talent_list_entry_list FOREIGN KEY (talent_list_id) REFERENCES talent_lists(id) ON DELETE CASCADE,
+  ADD CONSTRAINT fk_talent_list_entry_freelancer FOREIGN KEY (freelancer_id) REFERENCES users(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_job_bookmark_job ON job_bookmarks (job_id);
+CREATE INDEX idx_talent_list_client ON talent_lists (client_id);
+
+
+
+-- +goose Down
+DROP INDEX idx_talent_list_client;
+DROP INDEX idx_job_bookmark_job;
+
+ALTER TABLE talent_list_entries
+  DROP CONSTRAINT fk_talent_list_entry_list,
+  DROP CONSTRAINT fk_talent_list_entry_freelancer;
+
+ALTER TABLE talent_lists
+  DROP CONSTRAINT fk_talent_list_client;
+
+ALTER TABLE job_bookmarks
+  DROP CONSTRAINT fk_job_bookmark_user,
+  DROP CONSTRAINT fk_job_bookmark_job;
@@ -0,0 +1,23 @@
+-- +goose Up
+ALTER TABLE job_invitations
+  ADD CONSTRAINT fk_job_invitation_job FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE,
+  ADD CONSTRAINT fk_job_invitation_freelancer FOREIGN KEY (freelancer_id) REFERENCES users(id) ON DELETE CASCADE,
+  ADD CONSTRAINT fk_job_invitation_application FOREIGN KEY (application_id) REFERENCES applications(id) ON DELETE SET NULL;
+
+ALTER TABLE jobs
+  ADD CONSTRAINT chk_job_visibility CHECK (visibility IN ('public', 'invite-only'));
+
+CREATE INDEX idx_job_invitation_freelancer ON job_invitations (freelancer_id);
+
+
+
+-- +goose Down
+DROP INDEX idx_job_invitation_freelancer;
+
+ALTER TABLE jobs
+  DROP CONSTRAINT chk_job_visibility;
+
+ALTER TABLE job_invitations
+  DROP CONSTRAINT fk_job_invitation_job,
+  DROP CONSTRAINT fk_job_invitation_freelancer,
+  DROP CONSTRAINT fk_job_invitation_application;
@@ -0,0 +1,17 @@
+-- +goose Up
+ALTER TABLE jobs
+  DROP CONSTRAINT chk_job_visibility,
+  ADD CONSTRAINT chk_job_visibility CHECK (visibility IN ('public', 'invite-only', 'restricted')),
+  ADD CONSTRAINT chk_job_restricted_skill_level CHECK (restricted_skill_level IS NULL OR restricted_skill_level IN ('', 'beginner', 'intermediate', 'advanced', 'expert')),
+  ADD CONSTRAINT chk_job_min_completed_jobs CHECK (min_completed_jobs >= 0),
+  ADD CONSTRAINT fk_job_restricted_skill FOREIGN KEY (restricted_skill_id) REFERENCES skills(id) ON DELETE SET NULL;
+
+
+
+-- +goose Down
+ALTER TABLE jobs
+  DROP CONSTRAINT fk_job_restricted_skill,
+  DROP CONSTRAINT chk_job_min_completed_jobs,
+  DROP CONSTRAINT chk_job_restricted_skill_level,
+  DROP CONSTRAINT chk_job_visibility,
+  ADD CONSTRAINT chk_job_visibility CHECK (visibility IN ('public', 'invite-only'));
@@ -0,0 +1,12 @@
+-- +goose Up
+-- Jobs posted before scheduling existed were published when they were created
+UPDATE jobs SET publish_at = created_at WHERE publish_at IS NULL AND status <> 'draft';
+
+CREATE INDEX idx_job_scheduled_publish ON jobs (publish_at) WHERE status = 'scheduled' AND deleted_at IS NULL;
+CREATE INDEX idx_job_open_expiry ON jobs (expires_at) WHERE status = 'open' AND deleted_at IS NULL;
+
+
+
+-- +goose Down
+DROP INDEX idx_job_open_expiry;
+DROP INDEX idx_job_scheduled_publish;
@@ -0,0 +1,21 @@
+-- +goose Up
+ALTER TABLE job_status_history
+  ADD CONSTRAINT fk_job_status_history_job FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE,
+  ADD CONSTRAINT fk_job_status_history_actor FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_job_status_history_job ON job_status_history (job_id, created_at);
+
+ALTER TABLE jobs
+  ADD CONSTRAINT chk_job_status CHECK (status IN ('draft', 'scheduled', 'open', 'paused', 'in-progress', 'completed', 'expired', 'cancelled'));
+
+
+
+-- +goose Down
+ALTER TABLE jobs
+  DROP CONSTRAINT chk_job_status;
+
+DROP INDEX idx_job_status_history_job;
+
+ALTER TABLE job_status_history
+  DROP CONSTRAINT fk_job_status_history_job,
+  DROP CONSTRAINT fk_job_status_history_actor;
@@ -0,0 +1,36 @@
+-- +goose Up
+ALTER TABLE job_assignments
+  ADD CONSTRAINT fk_job_assignment_job FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE,
+  ADD CONSTRAINT fk_job_assignment_freelancer FOREIGN KEY (freelancer_id) REFERENCES users(id) ON DELETE CASCADE,
+  ADD CONSTRAINT fk_job_assignment_application FOREIGN KEY (application_id) REFERENCES applications(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_job_assignment_freelancer ON job_assignments (freelancer_id);
+
+ALTER TABLE jobs
+  ADD CONSTRAINT chk_job_openings CHECK (openings >= 1);
+
+-- Hires made before jobs had several openings move to the assignments
+INSERT INTO job_assignments (job_id, freelancer_id, application_id, created_at)
+SELECT j.id, j.freelancer_id, a.id, COALESCE(a.accepted_at, j.created_at, NOW())
+FROM jobs j
+LEFT JOIN applications a ON a.job_id = j.id AND a.user_id = j.freelancer_id
+WHERE j.freelancer_id IS NOT NULL;
+
+UPDATE jobs SET freelancer_id = NULL WHERE freelancer_id IS NOT NULL;
+
+
+
+-- +goose Down
+UPDATE jobs j SET freelancer_id = (
+  SELECT ja.freelancer_id FROM job_assignments ja WHERE ja.job_id = j.id ORDER BY ja.id LIMIT 1
+);
+
+ALTER TABLE jobs
+  DROP CONSTRAINT chk_job_openings;
+
+DROP INDEX idx_job_assignment_freelancer;
+
+ALTER TABLE job_assignments
+  DROP CONSTRAINT fk_job_assignment_job,
+  DROP CONSTRAINT fk_job_assignment_freelancer,
+  DROP CONSTRAINT fk_job_assignment_application;
@@ -0,0 +1,25 @@
+-- +goose Up
+ALTER TABLE screening_questions
+  ADD CONSTRAINT fk_screening_question_job FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE,
+  ADD CONSTRAINT chk_screening_question_type CHECK (type IN ('text', 'yes-no', 'multiple-choice', 'number'));
+
+ALTER TABLE screening_answers
+  ADD CONSTRAINT fk_screening_answer_application FOREIGN KEY (application_id) REFERENCES applications(id) ON DELETE CASCADE,
+  ADD CONSTRAINT fk_screening_answer_question FOREIGN KEY (question_id) REFERENCES screening_questions(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_screening_question_job ON screening_questions (job_id, position);
+CREATE INDEX idx_screening_answer_question ON screening_answers (question_id);
+
+
+
+-- +goose Down
+DROP INDEX idx_screening_answer_question;
+DROP INDEX idx_screening_question_job;
+
+ALTER TABLE screening_answers
+  DROP CONSTRAINT fk_screening_answer_application,
+  DROP CONSTRAINT fk_screening_answer_question;
+
+ALTER TABLE screening_questions
+  DROP CONSTRAINT fk_screening_question_job,
+  DROP CONSTRAINT chk_screening_question_type;
@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE applications
+  ADD CONSTRAINT chk_application_status CHECK (status IN ('pending', 'shortlisted', 'interviewing', 'offer-sent', 'offer-declined', 'accepted', 'rejected'));
+
+CREATE INDEX idx_application_job_status ON applications (job_id, status);
+
+
+
+-- +goose Down
+DROP INDEX idx_application_job_status;
+
+ALTER TABLE applications
+  DROP CONSTRAINT chk_application_status;
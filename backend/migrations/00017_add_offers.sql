@@ -0,0 +1,28 @@
+-- +goose Up
+ALTER TABLE offers
+  ADD CONSTRAINT fk_offer_application FOREIGN KEY (application_id) REFERENCES applications(id) ON DELETE CASCADE,
+  ADD CONSTRAINT chk_offer_proposed_by CHECK (proposed_by IN ('client', 'freelancer')),
+  ADD CONSTRAINT chk_offer_rate CHECK (rate IN ('hourly', 'fixed')),
+  ADD CONSTRAINT chk_offer_status CHECK (status IN ('pending', 'countered', 'accepted', 'declined', 'withdrawn'));
+
+ALTER TABLE job_assignments
+  ADD CONSTRAINT fk_job_assignment_offer FOREIGN KEY (offer_id) REFERENCES offers(id) ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX idx_offer_application_round ON offers (application_id, round);
+-- a negotiation waits on one offer at a time
+CREATE UNIQUE INDEX idx_offer_application_pending ON offers (application_id) WHERE status = 'pending';
+
+
+
+-- +goose Down
+DROP INDEX idx_offer_application_pending;
+DROP INDEX idx_offer_application_round;
+
+ALTER TABLE job_assignments
+  DROP CONSTRAINT fk_job_assignment_offer;
+
+ALTER TABLE offers
+  DROP CONSTRAINT fk_offer_application,
+  DROP CONSTRAINT chk_offer_proposed_by,
+  DROP CONSTRAINT chk_offer_rate,
+  DROP CONSTRAINT chk_offer_status;
@@ -0,0 +1,26 @@
+-- +goose Up
+ALTER TABLE applications
+  DROP CONSTRAINT chk_application_status,
+  ADD CONSTRAINT chk_application_status CHECK (status IN ('pending', 'shortlisted', 'interviewing', 'offer-sent', 'offer-declined', 'accepted', 'rejected', 'withdrawn'));
+
+ALTER TABLE cancellation_requests
+  ADD CONSTRAINT fk_cancellation_request_application FOREIGN KEY (application_id) REFERENCES applications(id) ON DELETE CASCADE,
+  ADD CONSTRAINT chk_cancellation_request_status CHECK (status IN ('pending', 'acknowledged'));
+
+-- a hired freelancer waits on one cancellation request at a time
+CREATE UNIQUE INDEX idx_cancellation_request_pending ON cancellation_requests (application_id) WHERE status = 'pending';
+
+
+
+-- +goose Down
+DROP INDEX idx_cancellation_request_pending;
+
+ALTER TABLE cancellation_requests
+  DROP CONSTRAINT fk_cancellation_request_application,
+  DROP CONSTRAINT chk_cancellation_request_status;
+
+UPDATE applications SET status = 'rejected' WHERE status = 'withdrawn';
+
+ALTER TABLE applications
+  DROP CONSTRAINT chk_application_status,
+  ADD CONSTRAINT chk_application_status CHECK (status IN ('pending', 'shortlisted', 'interviewing', 'offer-sent', 'offer-declined', 'accepted', 'rejected'));
@@ -0,0 +1,28 @@
+-- +goose Up
+ALTER TABLE credit_transactions
+  ADD CONSTRAINT fk_credit_transaction_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  ADD CONSTRAINT fk_credit_transaction_job FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE SET NULL,
+  ADD CONSTRAINT fk_credit_transaction_application FOREIGN KEY (application_id) REFERENCES applications(id) ON DELETE SET NULL,
+  ADD CONSTRAINT fk_credit_transaction_admin FOREIGN KEY (admin_id) REFERENCES users(id) ON DELETE SET NULL,
+  ADD CONSTRAINT chk_credit_transaction_type CHECK (type IN ('allowance', 'application', 'refund', 'grant')),
+  ADD CONSTRAINT chk_credit_transaction_balance CHECK (balance >= 0);
+
+CREATE INDEX idx_credit_transaction_user ON credit_transactions (user_id, id);
+CREATE INDEX idx_credit_transaction_job ON credit_transactions (job_id, type);
+-- an application is refunded once
+CREATE UNIQUE INDEX idx_credit_transaction_refund ON credit_transactions (application_id) WHERE type = 'refund';
+
+
+
+-- +goose Down
+DROP INDEX idx_credit_transaction_refund;
+DROP INDEX idx_credit_transaction_job;
+DROP INDEX idx_credit_transaction_user;
+
+ALTER TABLE credit_transactions
+  DROP CONSTRAINT fk_credit_transaction_user,
+  DROP CONSTRAINT fk_credit_transaction_job,
+  DROP CONSTRAINT fk_credit_transaction_application,
+  DROP CONSTRAINT fk_credit_transaction_admin,
+  DROP CONSTRAINT chk_credit_transaction_type,
+  DROP CONSTRAINT chk_credit_transaction_balance;
@@ -0,0 +1,23 @@
+-- +goose Up
+ALTER TABLE attachments
+  ALTER COLUMN application_id DROP NOT NULL,
+  ADD CONSTRAINT fk_attachment_job FOREIGN KEY (job_id) REFERENCES jobs(id) ON DELETE CASCADE,
+  ADD CONSTRAINT chk_attachment_owner CHECK ((application_id IS NULL) <> (job_id IS NULL)),
+  ADD CONSTRAINT chk_attachment_size CHECK (size >= 0);
+
+CREATE INDEX idx_attachment_application ON attachments (application_id);
+CREATE INDEX idx_attachment_job ON attachments (job_id);
+
+
+
+-- +goose Down
+DROP INDEX idx_attachment_application;
+DROP INDEX idx_attachment_job;
+
+DELETE FROM attachments WHERE job_id IS NOT NULL;
+
+ALTER TABLE attachments
+  DROP CONSTRAINT fk_attachment_job,
+  DROP CONSTRAINT chk_attachment_owner,
+  DROP CONSTRAINT chk_attachment_size,
+  ALTER COLUMN application_id SET NOT NULL;
@@ -0,0 +1,18 @@
+-- +goose Up
+ALTER TABLE uploads
+  ADD CONSTRAINT fk_upload_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  ADD CONSTRAINT chk_upload_status CHECK (status IN ('uploading', 'completed')),
+  ADD CONSTRAINT chk_upload_received CHECK (received >= 0 AND received <= length);
+
+-- the cleanup task looks for expired uploads
+CREATE INDEX idx_upload_expires_at ON uploads (expires_at);
+
+
+
+-- +goose Down
+DROP INDEX idx_upload_expires_at;
+
+ALTER TABLE uploads
+  DROP CONSTRAINT fk_upload_user,
+  DROP CONSTRAINT chk_upload_status,
+  DROP CONSTRAINT chk_upload_received;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- file paths become keys relative to the storage, the local storage keeps the uploads directory
+UPDATE attachments SET file_path = substr(file_path, length('uploads/') + 1) WHERE file_path LIKE 'uploads/%';
+UPDATE uploads SET file_path = substr(file_path, length('uploads/') + 1) WHERE file_path LIKE 'uploads/%';
+
+ALTER TABLE uploads
+  ADD CONSTRAINT chk_upload_chunks CHECK (chunks >= 0);
+
+
+
+-- +goose Down
+ALTER TABLE uploads
+  DROP CONSTRAINT chk_upload_chunks;
+
+UPDATE uploads SET file_path = 'uploads/' || file_path;
+UPDATE attachments SET file_path = 'uploads/' || file_path;
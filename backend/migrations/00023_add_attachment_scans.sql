@@ -0,0 +1,15 @@
+-- +goose Up
+-- existing attachments start quarantined too and are scanned by the scan-attachments task
+ALTER TABLE attachments
+  ADD CONSTRAINT chk_attachment_status CHECK (status IN ('quarantined', 'clean', 'infected', 'rejected'));
+
+-- the scan task looks for quarantined attachments
+CREATE INDEX idx_attachment_quarantined ON attachments (id) WHERE status = 'quarantined';
+
+
+
+-- +goose Down
+DROP INDEX idx_attachment_quarantined;
+
+ALTER TABLE attachments
+  DROP CONSTRAINT chk_attachment_status;
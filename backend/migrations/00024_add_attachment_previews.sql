@@ -0,0 +1,16 @@
+-- +goose Up
+ALTER TABLE attachments
+  ADD CONSTRAINT chk_attachment_preview_status CHECK (preview_status IN ('pending', 'ready', 'failed')),
+  ADD CONSTRAINT chk_attachment_page_count CHECK (page_count >= 0);
+
+-- the process task looks for clean attachments without a preview
+CREATE INDEX idx_attachment_preview_pending ON attachments (id) WHERE status = 'clean' AND preview_status = 'pending';
+
+
+
+-- +goose Down
+DROP INDEX idx_attachment_preview_pending;
+
+ALTER TABLE attachments
+  DROP CONSTRAINT chk_attachment_preview_status,
+  DROP CONSTRAINT chk_attachment_page_count;
@@ -0,0 +1,40 @@
+-- +goose Up
+ALTER TABLE portfolio_items
+  ADD CONSTRAINT fk_portfolio_item_freelancer FOREIGN KEY (freelancer_id) REFERENCES users(id) ON DELETE CASCADE,
+  ADD CONSTRAINT chk_portfolio_item_dates CHECK (end_date IS NULL OR (start_date IS NOT NULL AND end_date >= start_date)),
+  ADD CONSTRAINT chk_portfolio_item_position CHECK (position >= 0);
+
+ALTER TABLE portfolio_item_skills
+  ADD CONSTRAINT fk_portfolio_item_skill_item FOREIGN KEY (portfolio_item_id) REFERENCES portfolio_items(id) ON DELETE CASCADE,
+  ADD CONSTRAINT fk_portfolio_item_skill_skill FOREIGN KEY (skill_id) REFERENCES skills(id) ON DELETE CASCADE;
+
+-- an attachment belongs to exactly one application, job or portfolio item
+ALTER TABLE attachments
+  ADD CONSTRAINT fk_attachment_portfolio_item FOREIGN KEY (portfolio_item_id) REFERENCES portfolio_items(id) ON DELETE CASCADE,
+  DROP CONSTRAINT chk_attachment_owner,
+  ADD CONSTRAINT chk_attachment_owner CHECK (num_nonnulls(application_id, job_id, portfolio_item_id) = 1);
+
+CREATE INDEX idx_portfolio_item_freelancer ON portfolio_items (freelancer_id, position);
+CREATE INDEX idx_attachment_portfolio_item ON attachments (portfolio_item_id);
+
+
+
+-- +goose Down
+DROP INDEX idx_attachment_portfolio_item;
+DROP INDEX idx_portfolio_item_freelancer;
+
+DELETE FROM attachments WHERE portfolio_item_id IS NOT NULL;
+
+ALTER TABLE attachments
+  DROP CONSTRAINT fk_attachment_portfolio_item,
+  DROP CONSTRAINT chk_attachment_owner,
+  ADD CONSTRAINT chk_attachment_owner CHECK ((application_id IS NULL) <> (job_id IS NULL));
+
+ALTER TABLE portfolio_item_skills
+  DROP CONSTRAINT fk_portfolio_item_skill_item,
+  DROP CONSTRAINT fk_portfolio_item_skill_skill;
+
+ALTER TABLE portfolio_items
+  DROP CONSTRAINT fk_portfolio_item_freelancer,
+  DROP CONSTRAINT chk_portfolio_item_dates,
+  DROP CONSTRAINT chk_portfolio_item_position;
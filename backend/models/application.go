@@ -1,19 +1,27 @@
 package models
 
 import (
+	"backend/types"
+	"context"
+	"errors"
 	"time"
 
 	"github.com/beego/beego/v2/client/orm"
 )
 
 type Application struct {
-	Id              int       `orm:"pk;auto"`
-	User            *User     `orm:"rel(fk);on_delete(cascade)"`
-	Job             *Job      `orm:"rel(fk);on_delete(cascade)"`
-	Description     string    `orm:"type(text);null"`
-	RejectionReason string    `orm:"type(text);null"`           // Reason for rejection, if applicable
-	Status          string    `orm:"size(30);default(pending)"` // "pending", "accepted", "rejected"
-	CreatedAt       time.Time `orm:"auto_now_add;type(datetime)"`
+	Id               int        `orm:"pk;auto"`
+	User             *User      `orm:"rel(fk);on_delete(cascade)"`
+	Job              *Job       `orm:"rel(fk);on_delete(cascade)"`
+	Description      string     `orm:"type(text);null"`
+	RejectionReason  string     `orm:"type(text);null"`           // Reason for rejection, if applicable
+	Status           string     `orm:"size(30);default(pending)"` // "pending", "shortlisted", "interviewing", "offer-sent", "offer-declined", "accepted", "rejected", "withdrawn"
+	Notes            string     `orm:"type(text);null"`           // the client's private notes on the applicant
+	CreatedAt        time.Time  `orm:"auto_now_add;type(datetime)"`
+	AcceptedAt       *time.Time `orm:"type(timestamp);null"`
+	WithdrawalReason string     `orm:"type(text);null"` // the freelancer's reason, if withdrawn
+	WithdrawnAt      *time.Time `orm:"type(timestamp);null"`
+	DeletedAt        *time.Time `orm:"type(timestamp);null"` // soft deletion time, purged after the retention period
 }
 
 func (s *Application) TableUnique() [][]string {
@@ -33,7 +41,7 @@ func (s *Application) TableName() string {
 func GetApplicationByUserAndJob(userID, jobID int) (*Application, error) {
 	o := orm.NewOrm()
 	var application Application
-	err := o.QueryTable("applications").Filter("User__Id", userID).Filter("Job__Id", jobID).One(&application)
+	err := o.QueryTable("applications").Filter("User__Id", userID).Filter("Job__Id", jobID).Filter("DeletedAt__isnull", true).One(&application)
 	if err != nil {
 		if err == orm.ErrNoRows {
 			return nil, nil
@@ -43,9 +51,15 @@ func GetApplicationByUserAndJob(userID, jobID int) (*Application, error) {
 	return &application, nil
 }
 
+// ApplicationExists also checks deleted applications, a user can only apply to a job once
+func ApplicationExists(userID, jobID int) bool {
+	o := orm.NewOrm()
+	return o.QueryTable(new(Application)).Filter("User__Id", userID).Filter("Job__Id", jobID).Exist()
+}
+
 func GetApplicationCountForJob(jobID int) (int, error) {
 	o := orm.NewOrm()
-	count, err := o.QueryTable(new(Application)).Filter("Job__Id", jobID).Count()
+	count, err := o.QueryTable(new(Application)).Filter("Job__Id", jobID).Filter("DeletedAt__isnull", true).Count()
 	if err != nil {
 		return 0, err
 	}
@@ -57,7 +71,7 @@ func GetApplicationsByJobID(jobID int) ([]Application, error) {
 	var applications []Application
 
 	_, err := o.QueryTable(new(Application)).
-		Filter("Job__Id", jobID).OrderBy("id").All(&applications)
+		Filter("Job__Id", jobID).Filter("DeletedAt__isnull", true).OrderBy("id").All(&applications)
 
 	if err != nil {
 		return nil, err
@@ -66,9 +80,26 @@ func GetApplicationsByJobID(jobID int) ([]Application, error) {
 	return applications, nil
 }
 
-func CreateApplication(user *User, job *Job, description string) (int, error) {
+// CreateApplication saves the application together with its answers to the job's screening questions
+// and its attachments, whose files are already stored, and spends the freelancer's credits for it
+func CreateApplication(user *User, job *Job, description string, answers []types.ScreeningAnswer, attachments []Attachment) (int, error) {
 	o := orm.NewOrm()
+	var applicationID int
+
+	err := o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		var err error
+		applicationID, err = createApplication(txOrm, user, job, description, answers, attachments, true)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
 
+	return applicationID, nil
+}
+
+// createApplication saves the application in the caller's transaction
+func createApplication(o orm.QueryExecutor, user *User, job *Job, description string, answers []types.ScreeningAnswer, attachments []Attachment, spendCredits bool) (int, error) {
 	application := Application{
 		User:        user,
 		Job:         job,
@@ -77,12 +108,66 @@ func CreateApplication(user *User, job *Job, description string) (int, error) {
 		CreatedAt:   time.Now(),
 	}
 
-	id, err := o.Insert(&application)
-	if err != nil {
+	if _, err := o.Insert(&application); err != nil {
+		return 0, err
+	}
+
+	if spendCredits {
+		if err := spendApplicationCredits(o, &application); err != nil {
+			return 0, err
+		}
+	}
+
+	for i := range attachments {
+		attachments[i].Application = &application
+		if _, err := o.Insert(&attachments[i]); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := createScreeningAnswers(o, application.Id, answers); err != nil {
 		return 0, err
 	}
 
-	return int(id), nil
+	return application.Id, nil
+}
+
+// RejectPendingApplications rejects the applications of the job still under review with the reason
+// and withdraws their offers, except for the application with exceptApplicationID
+func RejectPendingApplications(o orm.QueryExecutor, jobID, exceptApplicationID int, reason string) error {
+	if err := withdrawJobOffers(o, jobID, exceptApplicationID); err != nil {
+		return err
+	}
+
+	qs := o.QueryTable(new(Application)).Filter("Job__Id", jobID).Filter("Status__in", reviewStages).Filter("DeletedAt__isnull", true)
+	if exceptApplicationID != 0 {
+		qs = qs.Exclude("Id", exceptApplicationID)
+	}
+
+	_, err := qs.Update(orm.Params{"status": "rejected", "rejection_reason": reason})
+	return err
+}
+
+// AcceptApplication marks the application as accepted now
+func AcceptApplication(o orm.QueryExecutor, application *Application) error {
+	acceptedAt := time.Now()
+	application.Status = "accepted"
+	application.AcceptedAt = &acceptedAt
+
+	_, err := o.Update(application, "Status", "AcceptedAt")
+	return err
+}
+
+// RejectAcceptedApplications rejects the accepted application of the freelancer, or of every hired
+// freelancer when freelancerID is 0, used when freelancers leave the job
+func RejectAcceptedApplications(o orm.QueryExecutor, jobID, freelancerID int, reason string) error {
+	qs := o.QueryTable(new(Application)).Filter("Job__Id", jobID).Filter("Status", "accepted").Filter("DeletedAt__isnull", true)
+	if freelancerID != 0 {
+		qs = qs.Filter("User__Id", freelancerID)
+	}
+
+	_, err := qs.Update(orm.Params{"status": "rejected", "rejection_reason": reason})
+	return err
 }
 
 func GetApplicationByID(applicationID int) (*Application, error) {
@@ -93,6 +178,9 @@ func GetApplicationByID(applicationID int) (*Application, error) {
 	if err != nil {
 		return nil, err
 	}
+	if application.DeletedAt != nil {
+		return nil, orm.ErrNoRows
+	}
 
 	_, err = o.LoadRelated(&application, "User")
 	if err != nil {
@@ -112,7 +200,7 @@ func GetApplicationsByUserID(userID int) ([]Application, error) {
 	var applications []Application
 
 	_, err := o.QueryTable(new(Application)).
-		Filter("User__Id", userID).OrderBy("id").All(&applications)
+		Filter("User__Id", userID).Filter("DeletedAt__isnull", true).OrderBy("id").All(&applications)
 
 	if err != nil {
 		return nil, err
@@ -139,15 +227,70 @@ func UpdateApplication(application *Application) error {
 	return nil
 }
 
-func DeleteApplicationByID(applicationID int) error {
+// WithdrawApplication withdraws an application under review with the freelancer's reason,
+// a pending offer on it is withdrawn. The application stays in the job's history.
+func WithdrawApplication(application *Application, reason string) error {
+	o := orm.NewOrm()
+
+	return o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		if err := WithdrawPendingOffer(txOrm, application.Id); err != nil {
+			return err
+		}
+
+		return withdrawApplication(txOrm, application, reason)
+	})
+}
+
+func withdrawApplication(o orm.QueryExecutor, application *Application, reason string) error {
+	withdrawnAt := time.Now()
+	application.Status = "withdrawn"
+	application.WithdrawalReason = reason
+	application.WithdrawnAt = &withdrawnAt
+
+	_, err := o.Update(application, "Status", "WithdrawalReason", "WithdrawnAt")
+	return err
+}
+
+// SoftDeleteApplicationByID hides the application until it is restored or purged
+func SoftDeleteApplicationByID(applicationID int) error {
+	o := orm.NewOrm()
+
+	_, err := o.QueryTable(new(Application)).Filter("Id", applicationID).Filter("DeletedAt__isnull", true).
+		Update(orm.Params{"deleted_at": time.Now()})
+
+	return err
+}
 
+func RestoreApplicationByID(applicationID int) error {
 	o := orm.NewOrm()
+
 	application := Application{Id: applicationID}
+	if err := o.Read(&application); err != nil {
+		return err
+	}
+	if application.DeletedAt == nil {
+		return errors.New("application is not deleted")
+	}
+
+	if _, err := GetJobByID(application.Job.Id); err != nil {
+		return errors.New("application job is deleted")
+	}
+	if _, err := GetUserById(application.User.Id); err != nil {
+		return errors.New("application user is deleted")
+	}
+
+	_, err := o.QueryTable(new(Application)).Filter("Id", applicationID).Update(orm.Params{"deleted_at": nil})
+	return err
+}
+
+func GetDeletedApplications() ([]Application, error) {
+	o := orm.NewOrm()
+	var applications []Application
 
-	_, err := o.Delete(&application)
+	_, err := o.QueryTable(new(Application)).Filter("DeletedAt__isnull", false).OrderBy("-deleted_at").All(&applications)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return applications, nil
 }
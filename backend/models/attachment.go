@@ -1,18 +1,37 @@
 package models
 
 import (
+	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/beego/beego/v2/client/orm"
 )
 
+// Attachment is a file of an application, of a job, like the job's spec documents, or of a
+// freelancer's portfolio item. Exactly one of Application, Job and PortfolioItem is set.
+// New files are quarantined until the scanner finds them clean, infected files are removed
+// from the storage and keep the row with the signature in ScanResult. Clean PDFs are then
+// processed for their text, page count and a thumbnail of the first page.
 type Attachment struct {
-	Id          int          `orm:"pk;auto"`
-	Application *Application `orm:"rel(fk);on_delete(cascade)"`
-	FileName    string       `orm:"size(255)"` // Original filename
-	FilePath    string       `orm:"size(255)"` // Path with unique name
-	CreatedAt   time.Time    `orm:"auto_now_add;type(timestamp)"`
+	Id               int            `orm:"pk;auto"`
+	Application      *Application   `orm:"rel(fk);null;on_delete(cascade)"`
+	Job              *Job           `orm:"rel(fk);null;on_delete(cascade)"`
+	PortfolioItem    *PortfolioItem `orm:"rel(fk);null;on_delete(cascade)"`
+	FileName         string         `orm:"size(255)"`                     // Sanitized original filename
+	FilePath         string         `orm:"size(255)"`                     // Storage key with unique name
+	Size             int64          `orm:"default(0)"`                    // bytes
+	Status           string         `orm:"size(20);default(quarantined)"` // quarantined, clean, infected, rejected
+	ScanResult       string         `orm:"size(255);default()"`           // signature of infected files, reason of rejected ones
+	ScanClaimedAt    *time.Time     `orm:"null;type(timestamp)"`          // when a scan of the file started
+	ScannedAt        *time.Time     `orm:"null;type(timestamp)"`
+	PreviewStatus    string         `orm:"size(20);default(pending)"` // pending, ready, failed
+	Text             string         `orm:"type(text);null"`           // text of the PDF for search
+	PageCount        int            `orm:"default(0)"`
+	ThumbnailPath    string         `orm:"size(255);default()"`  // storage key of the first page as PNG
+	PreviewClaimedAt *time.Time     `orm:"null;type(timestamp)"` // when processing of the file started
+	CreatedAt        time.Time      `orm:"auto_now_add;type(timestamp)"`
 }
 
 func init() {
@@ -23,46 +42,142 @@ func (s *Attachment) TableName() string {
 	return "attachments"
 }
 
-func GetAttachmentByApplicationID(applicationID int) (*Attachment, error) {
+func GetAttachmentsByApplicationID(applicationID int) ([]Attachment, error) {
 	o := orm.NewOrm()
-	var attachment Attachment
+	var attachments []Attachment
 
-	err := o.QueryTable(new(Attachment)).
-		Filter("Application__Id", applicationID).
-		One(&attachment)
+	_, err := o.QueryTable(new(Attachment)).Filter("Application__Id", applicationID).OrderBy("id").Limit(-1).All(&attachments)
+	if err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+func GetAttachmentsByJobID(jobID int) ([]Attachment, error) {
+	o := orm.NewOrm()
+	var attachments []Attachment
 
+	_, err := o.QueryTable(new(Attachment)).Filter("Job__Id", jobID).OrderBy("id").Limit(-1).All(&attachments)
 	if err != nil {
-		if err == orm.ErrNoRows {
-			return nil, nil
-		}
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+func GetAttachmentsByPortfolioItemID(itemID int) ([]Attachment, error) {
+	o := orm.NewOrm()
+	var attachments []Attachment
+
+	_, err := o.QueryTable(new(Attachment)).Filter("PortfolioItem__Id", itemID).OrderBy("id").Limit(-1).All(&attachments)
+	if err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// AttachmentsSize returns the total size of the attachments in bytes
+func AttachmentsSize(attachments []Attachment) int64 {
+	var size int64
+	for _, attachment := range attachments {
+		size += attachment.Size
+	}
+	return size
+}
+
+// NewAttachment returns the quarantined attachment of a stored file, for an application or a job
+// that is created together with its attachments
+func NewAttachment(fileName, filePath string, size int64) Attachment {
+	return Attachment{
+		FileName:      fileName,
+		FilePath:      filePath,
+		Size:          size,
+		Status:        "quarantined",
+		PreviewStatus: "pending",
+		CreatedAt:     time.Now(),
+	}
+}
+
+func CreateApplicationAttachment(applicationID int, fileName, filePath string, size int64) (*Attachment, error) {
+	o := orm.NewOrm()
+
+	exists := o.QueryTable(new(Application)).Filter("Id", applicationID).Exist()
+	if !exists {
+		return nil, errors.New("application not found")
+	}
+
+	attachment := Attachment{
+		Application:   &Application{Id: applicationID},
+		FileName:      fileName,
+		FilePath:      filePath,
+		Size:          size,
+		Status:        "quarantined",
+		PreviewStatus: "pending",
+		CreatedAt:     time.Now(),
+	}
+	if _, err := o.Insert(&attachment); err != nil {
 		return nil, err
 	}
 
 	return &attachment, nil
 }
 
-func CreateAttachment(applicationId int, fileName, filePath string) error {
+func CreateJobAttachment(jobID int, fileName, filePath string, size int64) (*Attachment, error) {
 	o := orm.NewOrm()
 
-	exists := o.QueryTable(new(Application)).Filter("Id", applicationId).Exist()
+	exists := o.QueryTable(new(Job)).Filter("Id", jobID).Exist()
 	if !exists {
-		return errors.New("application not found")
+		return nil, errors.New("job not found")
 	}
 
 	attachment := Attachment{
-		Application: &Application{Id: applicationId},
-		FileName:    fileName,
-		FilePath:    filePath,
-		CreatedAt:   time.Now(),
+		Job:           &Job{Id: jobID},
+		FileName:      fileName,
+		FilePath:      filePath,
+		Size:          size,
+		Status:        "quarantined",
+		PreviewStatus: "pending",
+		CreatedAt:     time.Now(),
 	}
-	_, err := o.Insert(&attachment)
-	if err != nil {
-		return err
+	if _, err := o.Insert(&attachment); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return &attachment, nil
+}
+
+// CreatePortfolioItemAttachment adds a file to the portfolio item. Images need no preview, only PDFs wait for one.
+func CreatePortfolioItemAttachment(itemID int, fileName, filePath string, size int64) (*Attachment, error) {
+	o := orm.NewOrm()
+
+	exists := o.QueryTable(new(PortfolioItem)).Filter("Id", itemID).Exist()
+	if !exists {
+		return nil, errors.New("portfolio item not found")
+	}
+
+	attachment := Attachment{
+		PortfolioItem: &PortfolioItem{Id: itemID},
+		FileName:      fileName,
+		FilePath:      filePath,
+		Size:          size,
+		Status:        "quarantined",
+		PreviewStatus: "pending",
+		CreatedAt:     time.Now(),
+	}
+	if !strings.HasSuffix(strings.ToLower(fileName), ".pdf") {
+		attachment.PreviewStatus = "ready"
+	}
+	if _, err := o.Insert(&attachment); err != nil {
+		return nil, err
+	}
+
+	return &attachment, nil
 }
 
+// GetAttachmentByID returns the attachment with its application, job or portfolio item loaded,
+// orm.ErrNoRows when the owner is soft-deleted
 func GetAttachmentByID(attachmentID int) (*Attachment, error) {
 	o := orm.NewOrm()
 	attachment := Attachment{Id: attachmentID}
@@ -73,13 +188,59 @@ func GetAttachmentByID(attachmentID int) (*Attachment, error) {
 		return nil, err
 	}
 
-	if _, err := o.LoadRelated(&attachment, "Application"); err != nil {
-		return nil, err
+	if attachment.Application != nil {
+		if _, err := o.LoadRelated(&attachment, "Application"); err != nil {
+			return nil, err
+		}
+		if attachment.Application.DeletedAt != nil {
+			return nil, orm.ErrNoRows
+		}
+	} else if attachment.PortfolioItem != nil {
+		if _, err := o.LoadRelated(&attachment, "PortfolioItem"); err != nil {
+			return nil, err
+		}
+		if _, err := GetUserById(attachment.PortfolioItem.Freelancer.Id); err != nil {
+			return nil, orm.ErrNoRows
+		}
+	} else {
+		if _, err := o.LoadRelated(&attachment, "Job"); err != nil {
+			return nil, err
+		}
+		if attachment.Job.DeletedAt != nil {
+			return nil, orm.ErrNoRows
+		}
 	}
 
 	return &attachment, nil
 }
 
+// ReplaceApplicationAttachments creates the attachment of the application and deletes its other attachments
+// in the same transaction. The deleted attachments are returned so that the caller removes their files.
+func ReplaceApplicationAttachments(applicationID int, attachment *Attachment) ([]Attachment, error) {
+	o := orm.NewOrm()
+	var replaced []Attachment
+
+	err := o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		if _, err := txOrm.QueryTable(new(Attachment)).Filter("Application__Id", applicationID).All(&replaced); err != nil {
+			return err
+		}
+
+		attachment.Application = &Application{Id: applicationID}
+		if _, err := txOrm.Insert(attachment); err != nil {
+			return err
+		}
+
+		_, err := txOrm.QueryTable(new(Attachment)).Filter("Application__Id", applicationID).
+			Exclude("Id", attachment.Id).Delete()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return replaced, nil
+}
+
 func DeleteAttachmentByID(attachmentID int) error {
 
 	o := orm.NewOrm()
@@ -92,3 +253,96 @@ func DeleteAttachmentByID(attachmentID int) error {
 
 	return nil
 }
+
+// ClaimQuarantinedAttachments takes up to limit quarantined attachments for scanning. Claims older
+// than claimTimeout are taken again, so the files of a scan that never finished are not stuck, and
+// backends scanning at the same time skip each other's rows.
+func ClaimQuarantinedAttachments(limit int, now time.Time, claimTimeout time.Duration) ([]Attachment, error) {
+	o := orm.NewOrm()
+
+	var ids orm.ParamsList
+	_, err := o.Raw(`UPDATE attachments SET scan_claimed_at = ? WHERE id IN (
+			SELECT id FROM attachments
+			WHERE status = 'quarantined' AND (scan_claimed_at IS NULL OR scan_claimed_at < ?)
+			ORDER BY id LIMIT ? FOR UPDATE SKIP LOCKED)
+		RETURNING id`,
+		now, now.Add(-claimTimeout), limit).ValuesFlat(&ids)
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	var attachments []Attachment
+	_, err = o.QueryTable(new(Attachment)).Filter("Id__in", ids).OrderBy("id").Limit(-1).All(&attachments)
+	if err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// SetAttachmentScanResult records the scan of a quarantined attachment: clean, infected or rejected
+func SetAttachmentScanResult(attachmentID int, status, result string) error {
+	o := orm.NewOrm()
+
+	_, err := o.QueryTable(new(Attachment)).Filter("Id", attachmentID).Filter("Status", "quarantined").Update(orm.Params{
+		"Status":     status,
+		"ScanResult": result,
+		"ScannedAt":  time.Now(),
+	})
+	return err
+}
+
+// ClaimUnprocessedAttachments takes up to limit clean attachments waiting for their preview, claims work
+// like in ClaimQuarantinedAttachments
+func ClaimUnprocessedAttachments(limit int, now time.Time, claimTimeout time.Duration) ([]Attachment, error) {
+	o := orm.NewOrm()
+
+	var ids orm.ParamsList
+	_, err := o.Raw(`UPDATE attachments SET preview_claimed_at = ? WHERE id IN (
+			SELECT id FROM attachments
+			WHERE status = 'clean' AND preview_status = 'pending' AND (preview_claimed_at IS NULL OR preview_claimed_at < ?)
+			ORDER BY id LIMIT ? FOR UPDATE SKIP LOCKED)
+		RETURNING id`,
+		now, now.Add(-claimTimeout), limit).ValuesFlat(&ids)
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	var attachments []Attachment
+	_, err = o.QueryTable(new(Attachment)).Filter("Id__in", ids).OrderBy("id").Limit(-1).All(&attachments)
+	if err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// SetAttachmentPreview records the text, page count and thumbnail of a processed attachment
+func SetAttachmentPreview(attachmentID int, text string, pageCount int, thumbnailPath string) error {
+	o := orm.NewOrm()
+
+	num, err := o.QueryTable(new(Attachment)).Filter("Id", attachmentID).Update(orm.Params{
+		"PreviewStatus": "ready",
+		"Text":          text,
+		"PageCount":     pageCount,
+		"ThumbnailPath": thumbnailPath,
+	})
+	if err != nil {
+		return err
+	}
+	if num == 0 {
+		return errors.New("attachment not found")
+	}
+
+	return nil
+}
+
+// SetAttachmentPreviewFailed marks an attachment whose file cannot be processed, it is not tried again
+func SetAttachmentPreviewFailed(attachmentID int) error {
+	o := orm.NewOrm()
+
+	_, err := o.QueryTable(new(Attachment)).Filter("Id", attachmentID).Update(orm.Params{
+		"PreviewStatus": "failed",
+	})
+	return err
+}
@@ -0,0 +1,319 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+)
+
+type Ban struct {
+	Id        int        `orm:"pk;auto"`
+	User      *User      `orm:"rel(fk);on_delete(cascade)"`
+	Admin     *User      `orm:"rel(fk);on_delete(set_null);null"` // Admin who issued the ban
+	Reason    string     `orm:"type(text);null"`
+	StartsAt  time.Time  `orm:"type(timestamp)"`
+	EndsAt    *time.Time `orm:"type(timestamp);null"` // nil means the ban is permanent
+	LiftedAt  *time.Time `orm:"type(timestamp);null"` // set when the ban expires or is lifted by an admin
+	CreatedAt time.Time  `orm:"auto_now_add;type(timestamp)"`
+}
+
+type BanAppeal struct {
+	Id            int        `orm:"pk;auto"`
+	Ban           *Ban       `orm:"rel(fk);on_delete(cascade)"`
+	User          *User      `orm:"rel(fk);on_delete(cascade)"`
+	Message       string     `orm:"type(text)"`
+	Status        string     `orm:"size(30);default(pending)"` // pending, approved, rejected
+	AdminResponse string     `orm:"type(text);null"`
+	ReviewedBy    *User      `orm:"rel(fk);on_delete(set_null);null"`
+	ReviewedAt    *time.Time `orm:"type(timestamp);null"`
+	CreatedAt     time.Time  `orm:"auto_now_add;type(timestamp)"`
+}
+
+func init() {
+	orm.RegisterModel(new(Ban), new(BanAppeal))
+}
+
+func (b *Ban) TableName() string {
+	return "bans"
+}
+
+func (a *BanAppeal) TableName() string {
+	return "ban_appeals"
+}
+
+// IsActive reports whether the ban is still in force at the given time.
+func (b *Ban) IsActive(now time.Time) bool {
+	if b.LiftedAt != nil {
+		return false
+	}
+	return b.EndsAt == nil || b.EndsAt.After(now)
+}
+
+func CreateBan(userID, adminID int, reason string, endsAt *time.Time) (int, error) {
+	o := orm.NewOrm()
+	var banID int64
+
+	err := o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		user := User{Id: userID}
+		if err := txOrm.Read(&user); err != nil {
+			return err
+		}
+
+		ban := Ban{
+			User:      &user,
+			Admin:     &User{Id: adminID},
+			Reason:    reason,
+			StartsAt:  time.Now(),
+			EndsAt:    endsAt,
+			CreatedAt: time.Now(),
+		}
+
+		id, err := txOrm.Insert(&ban)
+		if err != nil {
+			return err
+		}
+		banID = id
+
+		user.Ban = true
+		_, err = txOrm.Update(&user, "Ban")
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return int(banID), nil
+}
+
+func GetBanByID(banID int) (*Ban, error) {
+	o := orm.NewOrm()
+	ban := Ban{Id: banID}
+
+	err := o.Read(&ban)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ban, nil
+}
+
+func GetBansByUserID(userID int) ([]Ban, error) {
+	o := orm.NewOrm()
+	var bans []Ban
+
+	_, err := o.QueryTable(new(Ban)).Filter("User__Id", userID).OrderBy("-id").All(&bans)
+	if err != nil {
+		return nil, err
+	}
+
+	return bans, nil
+}
+
+// GetActiveBanByUserID returns the ban currently in force for the user or nil if there is none.
+// Bans that have run out are lifted on the way, so an expired ban never blocks a request.
+func GetActiveBanByUserID(userID int) (*Ban, error) {
+	o := orm.NewOrm()
+	var bans []Ban
+
+	_, err := o.QueryTable(new(Ban)).
+		Filter("User__Id", userID).
+		Filter("LiftedAt__isnull", true).
+		OrderBy("-id").All(&bans)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var active *Ban
+	for i := range bans {
+		if bans[i].IsActive(now) {
+			if active == nil {
+				active = &bans[i]
+			}
+			continue
+		}
+		if err := LiftBan(&bans[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return active, nil
+}
+
+// LiftBan marks the ban as lifted and clears the user's ban flag when no other ban is still in force.
+func LiftBan(ban *Ban) error {
+	o := orm.NewOrm()
+
+	return o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		return liftBan(txOrm, ban)
+	})
+}
+
+func liftBan(o orm.QueryExecutor, ban *Ban) error {
+	now := time.Now()
+	ban.LiftedAt = &now
+	if _, err := o.Update(ban, "LiftedAt"); err != nil {
+		return err
+	}
+
+	var remaining []Ban
+	_, err := o.QueryTable(new(Ban)).
+		Filter("User__Id", ban.User.Id).
+		Filter("LiftedAt__isnull", true).
+		All(&remaining)
+	if err != nil {
+		return err
+	}
+	for _, other := range remaining {
+		if other.IsActive(now) {
+			return nil
+		}
+	}
+
+	user := User{Id: ban.User.Id, Ban: false}
+	_, err = o.Update(&user, "Ban")
+	return err
+}
+
+// LiftActiveBansByUserID lifts every ban that is still in force for the user.
+func LiftActiveBansByUserID(userID int) error {
+	for {
+		ban, err := GetActiveBanByUserID(userID)
+		if err != nil {
+			return err
+		}
+		if ban == nil {
+			break
+		}
+		if err := LiftBan(ban); err != nil {
+			return err
+		}
+	}
+
+	// Users banned before ban records existed only carry the flag
+	user := User{Id: userID, Ban: false}
+	_, err := orm.NewOrm().Update(&user, "Ban")
+	return err
+}
+
+// LiftExpiredBans lifts every ban whose end time has passed and returns how many were lifted.
+func LiftExpiredBans() (int, error) {
+	o := orm.NewOrm()
+	var bans []Ban
+
+	_, err := o.QueryTable(new(Ban)).
+		Filter("LiftedAt__isnull", true).
+		Filter("EndsAt__isnull", false).
+		Filter("EndsAt__lte", time.Now()).
+		All(&bans)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range bans {
+		if err := LiftBan(&bans[i]); err != nil {
+			return i, err
+		}
+	}
+
+	return len(bans), nil
+}
+
+func CreateBanAppeal(ban *Ban, message string) (int, error) {
+	o := orm.NewOrm()
+
+	exists := o.QueryTable(new(BanAppeal)).Filter("Ban__Id", ban.Id).Filter("Status", "pending").Exist()
+	if exists {
+		return 0, errors.New("appeal already pending")
+	}
+
+	appeal := BanAppeal{
+		Ban:       ban,
+		User:      ban.User,
+		Message:   message,
+		Status:    "pending",
+		CreatedAt: time.Now(),
+	}
+
+	id, err := o.Insert(&appeal)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+func GetBanAppealByID(appealID int) (*BanAppeal, error) {
+	o := orm.NewOrm()
+	appeal := BanAppeal{Id: appealID}
+
+	err := o.Read(&appeal)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = o.LoadRelated(&appeal, "Ban")
+	if err != nil {
+		return nil, err
+	}
+
+	return &appeal, nil
+}
+
+func GetBanAppealsByUserID(userID int) ([]BanAppeal, error) {
+	o := orm.NewOrm()
+	var appeals []BanAppeal
+
+	_, err := o.QueryTable(new(BanAppeal)).Filter("User__Id", userID).OrderBy("-id").RelatedSel("Ban").All(&appeals)
+	if err != nil {
+		return nil, err
+	}
+
+	return appeals, nil
+}
+
+// GetBanAppeals returns appeals for the admin review queue, oldest first. An empty status returns all appeals.
+func GetBanAppeals(status string) ([]BanAppeal, error) {
+	o := orm.NewOrm()
+	var appeals []BanAppeal
+
+	qs := o.QueryTable(new(BanAppeal))
+	if status != "" {
+		qs = qs.Filter("Status", status)
+	}
+
+	_, err := qs.OrderBy("id").RelatedSel("Ban").All(&appeals)
+	if err != nil {
+		return nil, err
+	}
+
+	return appeals, nil
+}
+
+// ReviewBanAppeal saves the admin's review of the pending appeal and, when lift is set, lifts its ban
+// in the same transaction. An appeal reviewed in the meantime fails with "appeal already reviewed".
+func ReviewBanAppeal(appeal *BanAppeal, lift bool) error {
+	o := orm.NewOrm()
+
+	return o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		num, err := txOrm.QueryTable(new(BanAppeal)).Filter("Id", appeal.Id).Filter("Status", "pending").
+			Update(orm.Params{"status": appeal.Status})
+		if err != nil {
+			return err
+		}
+		if num == 0 {
+			return errors.New("appeal already reviewed")
+		}
+
+		if _, err := txOrm.Update(appeal, "AdminResponse", "ReviewedBy", "ReviewedAt"); err != nil {
+			return err
+		}
+
+		if lift {
+			return liftBan(txOrm, appeal.Ban)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// JobBookmark is a job a freelancer saved for later
+type JobBookmark struct {
+	Id        int       `orm:"pk;auto"`
+	User      *User     `orm:"rel(fk);on_delete(cascade)"`
+	Job       *Job      `orm:"rel(fk);on_delete(cascade)"`
+	CreatedAt time.Time `orm:"auto_now_add;type(timestamp)"`
+}
+
+func (b *JobBookmark) TableUnique() [][]string {
+	return [][]string{
+		{"User", "Job"},
+	}
+}
+
+func init() {
+	orm.RegisterModel(new(JobBookmark))
+}
+
+func (b *JobBookmark) TableName() string {
+	return "job_bookmarks"
+}
+
+func CreateJobBookmark(userID, jobID int) error {
+	o := orm.NewOrm()
+
+	bookmark := JobBookmark{
+		User: &User{Id: userID},
+		Job:  &Job{Id: jobID},
+	}
+
+	_, err := o.Insert(&bookmark)
+	return err
+}
+
+func JobBookmarkExists(userID, jobID int) bool {
+	o := orm.NewOrm()
+	return o.QueryTable(new(JobBookmark)).Filter("User__Id", userID).Filter("Job__Id", jobID).Exist()
+}
+
+// GetJobBookmarksByUserID returns the user's bookmarks with their jobs, newest first.
+// Bookmarks of closed or deleted jobs are included so they can be shown as unavailable.
+func GetJobBookmarksByUserID(userID int) ([]JobBookmark, error) {
+	o := orm.NewOrm()
+	var bookmarks []JobBookmark
+
+	_, err := o.QueryTable(new(JobBookmark)).Filter("User__Id", userID).RelatedSel("Job").
+		OrderBy("-created_at", "-id").Limit(-1).All(&bookmarks)
+	if err != nil {
+		return nil, err
+	}
+
+	return bookmarks, nil
+}
+
+// GetBookmarkedJobIDs returns the set of job IDs the user bookmarked
+func GetBookmarkedJobIDs(userID int) (map[int]bool, error) {
+	o := orm.NewOrm()
+	var bookmarks []JobBookmark
+
+	_, err := o.QueryTable(new(JobBookmark)).Filter("User__Id", userID).Limit(-1).All(&bookmarks, "Job")
+	if err != nil {
+		return nil, err
+	}
+
+	bookmarked := make(map[int]bool, len(bookmarks))
+	for _, bookmark := range bookmarks {
+		bookmarked[bookmark.Job.Id] = true
+	}
+
+	return bookmarked, nil
+}
+
+func DeleteJobBookmark(userID, jobID int) (bool, error) {
+	o := orm.NewOrm()
+
+	num, err := o.QueryTable(new(JobBookmark)).Filter("User__Id", userID).Filter("Job__Id", jobID).Delete()
+	if err != nil {
+		return false, err
+	}
+
+	return num > 0, nil
+}
+
+// PruneJobBookmarks removes bookmarks of completed, cancelled and expired jobs. Bookmarks of jobs
+// that are only in progress or paused stay and are shown as unavailable, like those of deleted jobs,
+// which come back when an admin restores the job and are removed with it by the purge.
+func PruneJobBookmarks() (int, error) {
+	o := orm.NewOrm()
+
+	result, err := o.Raw(`DELETE FROM job_bookmarks WHERE job_id IN (
+		SELECT id FROM jobs WHERE status IN ('completed', 'cancelled', 'expired') AND deleted_at IS NULL
+	)`).Exec()
+	if err != nil {
+		return 0, err
+	}
+
+	pruned, err := result.RowsAffected()
+	return int(pruned), err
+}
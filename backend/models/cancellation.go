@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// CancellationRequest is a hired freelancer asking to leave a job. The freelancer stays on the job
+// until the client acknowledges the request, the accepted application is then withdrawn.
+type CancellationRequest struct {
+	Id             int          `orm:"pk;auto"`
+	Application    *Application `orm:"rel(fk);on_delete(cascade)"` // the accepted application
+	Reason         string       `orm:"type(text)"`
+	Status         string       `orm:"size(20);default(pending)"` // pending, acknowledged
+	CreatedAt      time.Time    `orm:"auto_now_add;type(timestamp)"`
+	AcknowledgedAt *time.Time   `orm:"type(timestamp);null"`
+}
+
+func init() {
+	orm.RegisterModel(new(CancellationRequest))
+}
+
+func (r *CancellationRequest) TableName() string {
+	return "cancellation_requests"
+}
+
+func CreateCancellationRequest(application *Application, reason string) (int, error) {
+	o := orm.NewOrm()
+
+	request := CancellationRequest{
+		Application: application,
+		Reason:      reason,
+		Status:      "pending",
+	}
+
+	id, err := o.Insert(&request)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+// GetPendingCancellationRequest returns the request of the application waiting for the client,
+// orm.ErrNoRows when there is none
+func GetPendingCancellationRequest(applicationID int) (*CancellationRequest, error) {
+	o := orm.NewOrm()
+	var request CancellationRequest
+
+	err := o.QueryTable(new(CancellationRequest)).Filter("Application__Id", applicationID).Filter("Status", "pending").One(&request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &request, nil
+}
+
+// GetCancellationRequestsByJobID returns the cancellation requests of the job's freelancers, newest first
+func GetCancellationRequestsByJobID(jobID int) ([]CancellationRequest, error) {
+	o := orm.NewOrm()
+	var requests []CancellationRequest
+
+	_, err := o.QueryTable(new(CancellationRequest)).Filter("Application__Job__Id", jobID).RelatedSel("Application").
+		OrderBy("-created_at").Limit(-1).All(&requests)
+	if err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
+// AcknowledgeCancellationRequest marks the request as acknowledged and withdraws the accepted application
+// with the freelancer's reason, removing the freelancer from the job is done by the caller
+func AcknowledgeCancellationRequest(o orm.QueryExecutor, request *CancellationRequest) error {
+	now := time.Now()
+	request.Status = "acknowledged"
+	request.AcknowledgedAt = &now
+
+	if _, err := o.Update(request, "Status", "AcknowledgedAt"); err != nil {
+		return err
+	}
+
+	return withdrawApplication(o, request.Application, request.Reason)
+}
@@ -0,0 +1,246 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// CreditTransaction is an entry of a freelancer's credit ledger. Freelancers spend credits to apply
+// to jobs, Balance is the balance after the transaction.
+type CreditTransaction struct {
+	Id          int          `orm:"pk;auto"`
+	User        *User        `orm:"rel(fk);on_delete(cascade)"`
+	Type        string       `orm:"size(20)"` // allowance, application, refund, grant
+	Amount      int          // credits added, negative for spent credits
+	Balance     int          // balance after the transaction
+	Job         *Job         `orm:"rel(fk);null;on_delete(set_null)"`
+	Application *Application `orm:"rel(fk);null;on_delete(set_null)"`
+	Admin       *User        `orm:"rel(fk);null;on_delete(set_null)"` // grants: the admin granting the credits
+	Note        string       `orm:"size(255);null"`
+	CreatedAt   time.Time    `orm:"auto_now_add;type(timestamp)"`
+}
+
+func init() {
+	orm.RegisterModel(new(CreditTransaction))
+}
+
+func (t *CreditTransaction) TableName() string {
+	return "credit_transactions"
+}
+
+// ApplicationCreditCost returns the credits an application to the job costs, bigger and longer jobs cost more
+func ApplicationCreditCost(job *Job) int {
+	cost := 2
+
+	switch job.Length {
+	case "1-3":
+		cost += 1
+	case "3-6":
+		cost += 2
+	case "6-12":
+		cost += 3
+	case "12+":
+		cost += 4
+	}
+
+	// Hourly amounts are per hour, fixed amounts are the whole budget
+	large, medium := 2000, 500
+	if job.Rate == "hourly" {
+		large, medium = 60, 30
+	}
+	if job.Amount >= large {
+		cost += 2
+	} else if job.Amount >= medium {
+		cost += 1
+	}
+
+	return cost
+}
+
+func GetCreditBalance(userID int) (int, error) {
+	return creditBalance(orm.NewOrm(), userID)
+}
+
+func creditBalance(o orm.QueryExecutor, userID int) (int, error) {
+	var transaction CreditTransaction
+
+	err := o.QueryTable(new(CreditTransaction)).Filter("User__Id", userID).OrderBy("-id").One(&transaction, "Balance")
+	if err == orm.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return transaction.Balance, nil
+}
+
+// GetCreditTransactions returns the ledger of the freelancer, newest first
+func GetCreditTransactions(userID, limit, offset int) ([]CreditTransaction, error) {
+	o := orm.NewOrm()
+	var transactions []CreditTransaction
+
+	_, err := o.QueryTable(new(CreditTransaction)).Filter("User__Id", userID).OrderBy("-id").
+		Limit(limit, offset).All(&transactions)
+	if err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// recordCreditTransaction adds the transaction to the user's ledger. The user row is locked so
+// concurrent transactions see each other's balance, spending more than the balance fails.
+func recordCreditTransaction(o orm.QueryExecutor, transaction *CreditTransaction) error {
+	if err := lockCredits(o, transaction.User.Id); err != nil {
+		return err
+	}
+
+	balance, err := creditBalance(o, transaction.User.Id)
+	if err != nil {
+		return err
+	}
+	if balance+transaction.Amount < 0 {
+		return errors.New("not enough credits")
+	}
+
+	transaction.Balance = balance + transaction.Amount
+	_, err = o.Insert(transaction)
+	return err
+}
+
+// lockCredits locks the user row until the transaction ends
+func lockCredits(o orm.QueryExecutor, userID int) error {
+	var user User
+	return o.QueryTable(new(User)).Filter("Id", userID).ForUpdate().One(&user, "Id")
+}
+
+func spendApplicationCredits(o orm.QueryExecutor, application *Application) error {
+	return recordCreditTransaction(o, &CreditTransaction{
+		User:        application.User,
+		Type:        "application",
+		Amount:      -ApplicationCreditCost(application.Job),
+		Job:         application.Job,
+		Application: application,
+	})
+}
+
+// refundJobCredits gives back the credits spent on the job's applications, except for the freelancers
+// who were hired. Every application is refunded once.
+func refundJobCredits(o orm.QueryExecutor, jobID int, note string) error {
+	var spent []CreditTransaction
+	_, err := o.QueryTable(new(CreditTransaction)).Filter("Job__Id", jobID).Filter("Type", "application").
+		Filter("Application__isnull", false).Exclude("Application__Status", "accepted").Limit(-1).All(&spent)
+	if err != nil {
+		return err
+	}
+
+	for _, transaction := range spent {
+		refunded := o.QueryTable(new(CreditTransaction)).Filter("Application__Id", transaction.Application.Id).
+			Filter("Type", "refund").Exist()
+		if refunded {
+			continue
+		}
+
+		err := recordCreditTransaction(o, &CreditTransaction{
+			User:        transaction.User,
+			Type:        "refund",
+			Amount:      -transaction.Amount,
+			Job:         &Job{Id: jobID},
+			Application: transaction.Application,
+			Note:        note,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RefundExpiredJobCredits refunds the applications of a job that expired without a hire
+func RefundExpiredJobCredits(o orm.QueryExecutor, jobID int) error {
+	hired, err := o.QueryTable(new(JobAssignment)).Filter("Job__Id", jobID).Count()
+	if err != nil || hired > 0 {
+		return err
+	}
+
+	return refundJobCredits(o, jobID, "The job expired without a hire")
+}
+
+// GrantCredits adds credits to the freelancer's balance on behalf of an admin
+func GrantCredits(userID, adminID, amount int, note string) (*CreditTransaction, error) {
+	o := orm.NewOrm()
+
+	transaction := CreditTransaction{
+		User:   &User{Id: userID},
+		Type:   "grant",
+		Amount: amount,
+		Admin:  &User{Id: adminID},
+		Note:   note,
+	}
+
+	err := o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		return recordCreditTransaction(txOrm, &transaction)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &transaction, nil
+}
+
+// GrantMonthlyCredits tops the balance of every freelancer up to the allowance once per calendar month,
+// credits above the allowance are kept. It returns how many freelancers received credits.
+func GrantMonthlyCredits(allowance int) (int, error) {
+	o := orm.NewOrm()
+	now := time.Now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	var freelancers []User
+	_, err := o.QueryTable(new(User)).Filter("Role", "freelancer").Filter("DeletedAt__isnull", true).
+		Limit(-1).All(&freelancers, "Id")
+	if err != nil {
+		return 0, err
+	}
+
+	granted := 0
+	for _, freelancer := range freelancers {
+		received := o.QueryTable(new(CreditTransaction)).Filter("User__Id", freelancer.Id).Filter("Type", "allowance").
+			Filter("CreatedAt__gte", monthStart).Exist()
+		if received {
+			continue
+		}
+
+		err := o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+			if err := lockCredits(txOrm, freelancer.Id); err != nil {
+				return err
+			}
+			balance, err := creditBalance(txOrm, freelancer.Id)
+			if err != nil {
+				return err
+			}
+
+			// Recorded even when nothing is added, so the freelancer is skipped for the rest of the month
+			amount := allowance - balance
+			if amount < 0 {
+				amount = 0
+			}
+			return recordCreditTransaction(txOrm, &CreditTransaction{
+				User:   &User{Id: freelancer.Id},
+				Type:   "allowance",
+				Amount: amount,
+				Note:   now.Format("January 2006") + " allowance",
+			})
+		})
+		if err != nil {
+			return granted, err
+		}
+		granted++
+	}
+
+	return granted, nil
+}
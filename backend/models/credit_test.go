@@ -0,0 +1,33 @@
+package models
+
+import "testing"
+
+func TestApplicationCreditCost(t *testing.T) {
+	tests := []struct {
+		name string
+		job  Job
+		want int
+	}{
+		{"short small fixed job", Job{Length: "<1", Rate: "fixed", Amount: 100}, 2},
+		{"unknown length", Job{Length: "", Rate: "fixed", Amount: 100}, 2},
+		{"1-3 months", Job{Length: "1-3", Rate: "fixed", Amount: 100}, 3},
+		{"3-6 months", Job{Length: "3-6", Rate: "fixed", Amount: 100}, 4},
+		{"6-12 months", Job{Length: "6-12", Rate: "fixed", Amount: 100}, 5},
+		{"over 12 months", Job{Length: "12+", Rate: "fixed", Amount: 100}, 6},
+		{"fixed just under medium", Job{Length: "<1", Rate: "fixed", Amount: 499}, 2},
+		{"fixed medium budget", Job{Length: "<1", Rate: "fixed", Amount: 500}, 3},
+		{"fixed just under large", Job{Length: "<1", Rate: "fixed", Amount: 1999}, 3},
+		{"fixed large budget", Job{Length: "<1", Rate: "fixed", Amount: 2000}, 4},
+		{"hourly low rate", Job{Length: "<1", Rate: "hourly", Amount: 29}, 2},
+		{"hourly medium rate", Job{Length: "<1", Rate: "hourly", Amount: 30}, 3},
+		{"hourly high rate", Job{Length: "<1", Rate: "hourly", Amount: 60}, 4},
+		{"hourly rate counts per hour", Job{Length: "<1", Rate: "hourly", Amount: 100}, 4},
+		{"longest and largest", Job{Length: "12+", Rate: "fixed", Amount: 10000}, 8},
+	}
+
+	for _, tt := range tests {
+		if got := ApplicationCreditCost(&tt.job); got != tt.want {
+			t.Errorf("%s: ApplicationCreditCost = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
@@ -14,7 +14,7 @@ type FreelancerData struct {
 	Description  string   `orm:"type(text);null"`
 	HourlyRate   float64  `orm:"null"`
 	HoursPerWeek string   `orm:"size(30);null"` // <20, 20-40, 40-60, 60-80, 80+ ( hours )
-	Skills       []*Skill `orm:"rel(m2m);rel_table(freelancer_skills);on_delete(cascade)"`
+	Skills       []*Skill `orm:"rel(m2m);rel_through(backend/models.FreelancerSkill)"`
 }
 
 func init() {
@@ -70,17 +70,26 @@ func UpdateFreelancerData(freelancerData *FreelancerData) error {
 		return err
 	}
 
+	QueueFreelancerMatchScoreRefresh(freelancerData.User.Id)
 	return nil
 }
 
-func AddSkillToFreelancerData(freelancerData *FreelancerData, skill *Skill) error {
+func AddSkillToFreelancerData(freelancerData *FreelancerData, skill *Skill, level string, yearsOfExperience int) error {
 	o := orm.NewOrm()
 
-	_, err := o.QueryM2M(freelancerData, "Skills").Add(skill)
+	freelancerSkill := FreelancerSkill{
+		FreelancerData:    freelancerData,
+		Skill:             skill,
+		Level:             level,
+		YearsOfExperience: yearsOfExperience,
+	}
+
+	_, err := o.Insert(&freelancerSkill)
 	if err != nil {
 		return err
 	}
 
+	QueueFreelancerMatchScoreRefresh(freelancerData.User.Id)
 	return nil
 }
 func DeleteSkillFromFreelancerData(freelancerData *FreelancerData, skill *Skill) error {
@@ -95,5 +104,6 @@ func DeleteSkillFromFreelancerData(freelancerData *FreelancerData, skill *Skill)
 		return fmt.Errorf("skill not found")
 	}
 
+	QueueFreelancerMatchScoreRefresh(freelancerData.User.Id)
 	return nil
 }
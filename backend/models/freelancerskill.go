@@ -0,0 +1,73 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// FreelancerSkill is a row of the freelancer_skills join table with the freelancer's proficiency
+type FreelancerSkill struct {
+	Id                int             `orm:"pk;auto"`
+	FreelancerData    *FreelancerData `orm:"rel(fk);on_delete(cascade)"`
+	Skill             *Skill          `orm:"rel(fk);on_delete(cascade);column(skills_id)"`
+	Level             string          `orm:"size(20);default(beginner)"` // beginner, intermediate, advanced, expert
+	YearsOfExperience int             `orm:"default(0)"`
+}
+
+func init() {
+	orm.RegisterModel(new(FreelancerSkill))
+}
+
+func (f *FreelancerSkill) TableName() string {
+	return "freelancer_skills"
+}
+
+// skillLevelRank turns a level column into a comparable number, unknown levels rank as 0
+const skillLevelRank = "COALESCE(array_position(ARRAY['beginner', 'intermediate', 'advanced', 'expert']::varchar[], %s), 0)"
+
+func GetFreelancerSkills(freelancerDataID int) ([]FreelancerSkill, error) {
+	o := orm.NewOrm()
+	var freelancerSkills []FreelancerSkill
+
+	_, err := o.QueryTable(new(FreelancerSkill)).Filter("FreelancerData__Id", freelancerDataID).
+		RelatedSel("Skill").OrderBy("id").All(&freelancerSkills)
+	if err != nil {
+		return nil, err
+	}
+
+	return freelancerSkills, nil
+}
+
+func UpdateFreelancerSkill(freelancerData *FreelancerData, skillID int, level string, yearsOfExperience int) error {
+	o := orm.NewOrm()
+
+	num, err := o.QueryTable(new(FreelancerSkill)).Filter("FreelancerData__Id", freelancerData.Id).Filter("Skill__Id", skillID).
+		Update(orm.Params{"level": level, "years_of_experience": yearsOfExperience})
+	if err != nil {
+		return err
+	}
+
+	if num == 0 {
+		return fmt.Errorf("skill not found")
+	}
+
+	QueueFreelancerMatchScoreRefresh(freelancerData.User.Id)
+	return nil
+}
+
+// GetFreelancerUserIDsBySkill returns the users having the skill at minLevel or above with at least minYears of experience
+func GetFreelancerUserIDsBySkill(skillID int, minLevel string, minYears int) ([]int, error) {
+	o := orm.NewOrm()
+	var userIDs []int
+
+	_, err := o.Raw(`SELECT fd.user_id FROM freelancer_skills fs
+		JOIN freelancer_data fd ON fd.id = fs.freelancer_data_id
+		WHERE fs.skills_id = ? AND `+fmt.Sprintf(skillLevelRank, "fs.level")+` >= `+fmt.Sprintf(skillLevelRank, "?")+`
+		AND fs.years_of_experience >= ?`, skillID, minLevel, minYears).QueryRows(&userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return userIDs, nil
+}
@@ -2,24 +2,36 @@ package models
 
 import (
 	"backend/types"
-	"fmt"
+	"context"
+	"errors"
+	"time"
 
 	"github.com/beego/beego/v2/client/orm"
 )
 
 type Job struct {
-	Id           int      `orm:"pk;auto"`
-	Client       *User    `orm:"rel(fk);on_delete(cascade)"`
-	Freelancer   *User    `orm:"rel(fk);on_delete(cascade);null"`
-	Title        string   `orm:"size(30)"`
-	Description  string   `orm:"type(text)"`
-	Type         string   `orm:"size(30)"` // ongoing, one-time
-	Rate         string   `orm:"size(30)"` // hourly, fixed
-	Amount       int      // if hourly, amount per hour, if fixed, total amount
-	Length       string   `orm:"size(30)"`               // <1, 1-3, 3-6, 6-12, 12+ ( months )
-	HoursPerWeek string   `orm:"size(30)"`               // <20, 20-40, 40-60, 60-80, 80+ ( hours )
-	Status       string   `orm:"size(30);default(open)"` // open, in-progress, completed
-	Skills       []*Skill `orm:"rel(m2m);rel_table(job_skills);on_delete(cascade)"`
+	Id                   int        `orm:"pk;auto"`
+	Client               *User      `orm:"rel(fk);on_delete(cascade)"`
+	Freelancer           *User      `orm:"rel(fk);on_delete(cascade);null"` // unused, hires are JobAssignments, the column stays for the early migrations
+	Title                string     `orm:"size(30)"`
+	Description          string     `orm:"type(text)"`
+	Type                 string     `orm:"size(30)"` // ongoing, one-time
+	Rate                 string     `orm:"size(30)"` // hourly, fixed
+	Amount               int        // if hourly, amount per hour, if fixed, total amount
+	Length               string     `orm:"size(30)"`                         // <1, 1-3, 3-6, 6-12, 12+ ( months )
+	HoursPerWeek         string     `orm:"size(30)"`                         // <20, 20-40, 40-60, 60-80, 80+ ( hours )
+	Openings             int        `orm:"default(1)"`                       // how many freelancers the job hires
+	Status               string     `orm:"size(30);default(open)"`           // draft, scheduled, open, paused, in-progress, completed, expired, cancelled
+	Visibility           string     `orm:"size(20);default(public)"`         // public, invite-only, restricted
+	RestrictedSkill      *Skill     `orm:"rel(fk);null;on_delete(set_null)"` // restricted jobs: freelancers need this skill
+	RestrictedSkillLevel string     `orm:"size(20);null"`                    // minimum level of the restricted skill, empty for any
+	MinCompletedJobs     int        `orm:"default(0)"`                       // restricted jobs: freelancers need this many completed jobs
+	ExposedStages        string     `orm:"size(255);null"`                   // application review stages shown to freelancers, comma separated
+	Skills               []*Skill   `orm:"rel(m2m);rel_through(backend/models.JobSkill)"`
+	PublishAt            *time.Time `orm:"type(timestamp);null"` // when a scheduled job opens, or when the job was opened
+	ExpiresAt            *time.Time `orm:"type(timestamp);null"` // when an open job expires, null for never
+	CreatedAt            time.Time  `orm:"auto_now_add;type(timestamp);null"`
+	DeletedAt            *time.Time `orm:"type(timestamp);null"` // soft deletion time, purged after the retention period
 }
 
 func init() {
@@ -30,7 +42,8 @@ func (s *Job) TableName() string {
 	return "jobs"
 }
 
-func CreateJob(client *User, title, description, projectType, rate, length, hoursPerWeek string, amount int, skills []*types.Skill) error {
+// CreateJob saves the job with its skills, screening questions and attachments, whose files are already stored
+func CreateJob(client *User, title, description, projectType, rate, length, hoursPerWeek string, amount, openings int, skills []*types.Skill, questions []types.ScreeningQuestion, exposedStages []string, visibility *types.JobVisibility, schedule *types.JobSchedule, attachments []Attachment) (int, error) {
 	o := orm.NewOrm()
 
 	job := Job{
@@ -42,60 +55,134 @@ func CreateJob(client *User, title, description, projectType, rate, length, hour
 		Amount:       amount,
 		Length:       length,
 		HoursPerWeek: hoursPerWeek,
-		Status:       "open", // Default status
+		Openings:     openings,
+		PublishAt:    schedule.PublishAt,
+		ExpiresAt:    schedule.ExpiresAt,
+		CreatedAt:    time.Now(),
 	}
-
-	_, err := o.Insert(&job)
-	if err != nil {
-		return err
+	job.SetVisibility(visibility)
+	job.SetExposedStages(exposedStages)
+
+	// Drafts wait for the client to publish them, other jobs open now or at their publish time
+	if schedule.Draft {
+		job.Status = "draft"
+	} else {
+		job.publishOrSchedule(time.Now())
 	}
 
-	// Associate skills if provided
-	if len(skills) > 0 {
-		m2m := o.QueryM2M(&job, "Skills")
-		for _, skillReq := range skills {
-			var skill Skill
-			err := o.QueryTable("skills").Filter("Id", skillReq.Id).One(&skill)
-			if err == nil { // Only add existing skills
-				m2m.Add(&skill)
+	// The job is published only once it is complete
+	err := o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		if _, err := txOrm.Insert(&job); err != nil {
+			return err
+		}
+
+		// Associate skills if provided
+		if len(skills) > 0 {
+			if err := setJobSkills(txOrm, job.Id, skills); err != nil {
+				return err
 			}
 		}
-	}
 
-	return nil
-}
+		if len(questions) > 0 {
+			if err := setScreeningQuestions(txOrm, job.Id, questions); err != nil {
+				return err
+			}
+		}
 
-func UpdateJobWithSkills(job *Job, skills []*types.Skill) error {
-	o := orm.NewOrm()
+		for i := range attachments {
+			attachments[i].Job = &job
+			if _, err := txOrm.Insert(&attachments[i]); err != nil {
+				return err
+			}
+		}
 
-	// Update the job basic information
-	_, err := o.Update(job)
+		return CreateJobStatusHistory(txOrm, job.Id, "", job.Status, "create", client.Id, "")
+	})
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	// Get M2M relationship handler
-	m2m := o.QueryM2M(job, "Skills")
-	if m2m == nil {
-		return fmt.Errorf("failed to get M2M relationship")
+	if job.Status == "open" {
+		jobPublished(job.Id)
 	}
+	return job.Id, nil
+}
 
-	_, err = m2m.Clear()
-	if err != nil {
-		return err
+// SetVisibility sets who can see the job, clearing the restrictions unless the job is restricted
+func (j *Job) SetVisibility(visibility *types.JobVisibility) {
+	j.Visibility = visibility.Visibility
+	j.RestrictedSkill = nil
+	j.RestrictedSkillLevel = ""
+	j.MinCompletedJobs = 0
+
+	if visibility.Visibility == "restricted" {
+		if visibility.RestrictedSkillID != 0 {
+			j.RestrictedSkill = &Skill{Id: visibility.RestrictedSkillID}
+			j.RestrictedSkillLevel = visibility.RestrictedSkillLevel
+		}
+		j.MinCompletedJobs = visibility.MinCompletedJobs
 	}
+}
 
-	// Add new skills if provided
-	if len(skills) > 0 {
-		for _, skillReq := range skills {
-			var skill Skill
-			err := o.QueryTable("skills").Filter("Id", skillReq.Id).One(&skill)
-			if err == nil { // Only add existing skills
-				m2m.Add(&skill)
+func (j *Job) VisibilityInfo() types.JobVisibility {
+	visibility := types.JobVisibility{
+		Visibility:           j.Visibility,
+		RestrictedSkillLevel: j.RestrictedSkillLevel,
+		MinCompletedJobs:     j.MinCompletedJobs,
+	}
+	if j.RestrictedSkill != nil {
+		visibility.RestrictedSkillID = j.RestrictedSkill.Id
+	}
+	return visibility
+}
+
+// UpdateJobWithSkills saves the client's edit of the job with its skills and, unless questions is nil, its
+// screening questions. The status only changes through jobstate, so it is not written here. The job is
+// locked to check, against concurrent status changes and hires, that it can still be edited and that
+// the openings stay above the hired freelancers.
+func UpdateJobWithSkills(job *Job, skills []*types.Skill, questions *[]types.ScreeningQuestion) error {
+	o := orm.NewOrm()
+
+	err := o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		current, err := LockJob(txOrm, job.Id)
+		if err != nil {
+			return err
+		}
+		if current.Status != "open" && current.Status != "draft" && current.Status != "scheduled" {
+			return errors.New("job cannot be edited")
+		}
+		job.Status = current.Status
+
+		if job.Openings != current.Openings {
+			hired, err := txOrm.QueryTable(new(JobAssignment)).Filter("Job__Id", job.Id).Count()
+			if err != nil {
+				return err
+			}
+			if int64(job.Openings) <= hired {
+				return errors.New("openings already filled")
 			}
 		}
+
+		_, err = txOrm.Update(job, "Title", "Description", "Type", "Rate", "Amount", "Length", "HoursPerWeek", "Openings",
+			"Visibility", "RestrictedSkill", "RestrictedSkillLevel", "MinCompletedJobs", "ExposedStages")
+		if err != nil {
+			return err
+		}
+
+		if err := setJobSkills(txOrm, job.Id, skills); err != nil {
+			return err
+		}
+
+		if questions != nil {
+			return setScreeningQuestions(txOrm, job.Id, *questions)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
+	QueueJobMatchScoreRefresh(job.Id)
 	return nil
 }
 
@@ -114,7 +201,7 @@ func GetOpenJobs() ([]Job, error) {
 	o := orm.NewOrm()
 	var jobs []Job
 
-	_, err := o.QueryTable(new(Job)).Filter("Status", "open").All(&jobs)
+	_, err := o.QueryTable(new(Job)).Filter("Status", "open").Filter("DeletedAt__isnull", true).All(&jobs)
 	if err != nil {
 		return nil, err
 	}
@@ -129,6 +216,18 @@ func GetOpenJobs() ([]Job, error) {
 	return jobs, nil
 }
 
+// LockJob locks the job row until the transaction ends and returns its current status and openings
+func LockJob(o orm.QueryExecutor, jobID int) (*Job, error) {
+	var job Job
+
+	err := o.QueryTable(new(Job)).Filter("Id", jobID).ForUpdate().One(&job, "Id", "Status", "Openings")
+	if err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
 func GetJobByID(jobID int) (*Job, error) {
 	o := orm.NewOrm()
 	job := Job{Id: jobID}
@@ -137,6 +236,9 @@ func GetJobByID(jobID int) (*Job, error) {
 	if err != nil {
 		return nil, err
 	}
+	if job.DeletedAt != nil {
+		return nil, orm.ErrNoRows
+	}
 
 	_, err = o.LoadRelated(&job, "Skills")
 	if err != nil {
@@ -146,24 +248,79 @@ func GetJobByID(jobID int) (*Job, error) {
 	return &job, nil
 }
 
+// DeleteJobByID soft-deletes the job and its applications with a shared timestamp and refunds
+// the credits spent on the applications.
 func DeleteJobByID(jobID int) error {
+	o := orm.NewOrm()
+
+	return o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		now := time.Now()
+
+		num, err := txOrm.QueryTable(new(Job)).Filter("Id", jobID).Filter("DeletedAt__isnull", true).
+			Update(orm.Params{"deleted_at": now})
+		if err != nil || num == 0 {
+			return err
+		}
+
+		_, err = txOrm.QueryTable(new(Application)).Filter("Job__Id", jobID).Filter("DeletedAt__isnull", true).
+			Update(orm.Params{"deleted_at": now})
+		if err != nil {
+			return err
+		}
+
+		return refundJobCredits(txOrm, jobID, "The job was deleted")
+	})
+}
 
+// RestoreJobByID restores a soft-deleted job and the applications deleted along with it.
+func RestoreJobByID(jobID int) error {
 	o := orm.NewOrm()
+
 	job := Job{Id: jobID}
+	if err := o.Read(&job); err != nil {
+		return err
+	}
+	if job.DeletedAt == nil {
+		return errors.New("job is not deleted")
+	}
 
-	_, err := o.Delete(&job)
-	if err != nil {
+	client := User{Id: job.Client.Id}
+	if err := o.Read(&client); err != nil {
 		return err
 	}
+	if client.DeletedAt != nil {
+		return errors.New("job client is deleted")
+	}
 
-	return nil
+	return o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		_, err := txOrm.QueryTable(new(Job)).Filter("Id", jobID).Update(orm.Params{"deleted_at": nil})
+		if err != nil {
+			return err
+		}
+
+		_, err = txOrm.QueryTable(new(Application)).Filter("Job__Id", jobID).Filter("DeletedAt", *job.DeletedAt).
+			Update(orm.Params{"deleted_at": nil})
+		return err
+	})
+}
+
+func GetDeletedJobs() ([]Job, error) {
+	o := orm.NewOrm()
+	var jobs []Job
+
+	_, err := o.QueryTable(new(Job)).Filter("DeletedAt__isnull", false).OrderBy("-deleted_at").All(&jobs)
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
 }
 
 func GetJobsByClientID(clientID int) ([]Job, error) {
 	o := orm.NewOrm()
 	var jobs []Job
 
-	_, err := o.QueryTable(new(Job)).Filter("Client__Id", clientID).OrderBy("id").All(&jobs)
+	_, err := o.QueryTable(new(Job)).Filter("Client__Id", clientID).Filter("DeletedAt__isnull", true).OrderBy("id").All(&jobs)
 	if err != nil {
 		return nil, err
 	}
@@ -182,7 +339,21 @@ func GetJobsByFreelancerID(freelancerID int) ([]Job, error) {
 	o := orm.NewOrm()
 	var jobs []Job
 
-	_, err := o.QueryTable(new(Job)).Filter("Freelancer__Id", freelancerID).OrderBy("id").All(&jobs)
+	var assignments []JobAssignment
+	_, err := o.QueryTable(new(JobAssignment)).Filter("Freelancer__Id", freelancerID).Limit(-1).All(&assignments, "Job")
+	if err != nil {
+		return nil, err
+	}
+	if len(assignments) == 0 {
+		return jobs, nil
+	}
+
+	jobIDs := make([]int, 0, len(assignments))
+	for _, assignment := range assignments {
+		jobIDs = append(jobIDs, assignment.Job.Id)
+	}
+
+	_, err = o.QueryTable(new(Job)).Filter("Id__in", jobIDs).Filter("DeletedAt__isnull", true).OrderBy("id").All(&jobs)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// JobAssignment is a freelancer hired for one of the openings of a job
+type JobAssignment struct {
+	Id          int          `orm:"pk;auto"`
+	Job         *Job         `orm:"rel(fk);on_delete(cascade)"`
+	Freelancer  *User        `orm:"rel(fk);on_delete(cascade)"`
+	Application *Application `orm:"rel(fk);null;on_delete(set_null)"` // the accepted application
+	Offer       *Offer       `orm:"rel(fk);null;on_delete(set_null)"` // the accepted offer with the agreed terms
+	CreatedAt   time.Time    `orm:"auto_now_add;type(timestamp)"`
+}
+
+func (a *JobAssignment) TableUnique() [][]string {
+	return [][]string{
+		{"Job", "Freelancer"},
+	}
+}
+
+func init() {
+	orm.RegisterModel(new(JobAssignment))
+}
+
+func (a *JobAssignment) TableName() string {
+	return "job_assignments"
+}
+
+func CreateJobAssignment(o orm.QueryExecutor, jobID, freelancerID, applicationID int, offer *Offer) error {
+	assignment := JobAssignment{
+		Job:         &Job{Id: jobID},
+		Freelancer:  &User{Id: freelancerID},
+		Application: &Application{Id: applicationID},
+		Offer:       offer,
+	}
+
+	_, err := o.Insert(&assignment)
+	return err
+}
+
+func CountJobAssignments(jobID int) (int, error) {
+	o := orm.NewOrm()
+
+	count, err := o.QueryTable(new(JobAssignment)).Filter("Job__Id", jobID).Count()
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+func JobAssignmentExists(jobID, freelancerID int) bool {
+	o := orm.NewOrm()
+	return o.QueryTable(new(JobAssignment)).Filter("Job__Id", jobID).Filter("Freelancer__Id", freelancerID).Exist()
+}
+
+// GetJobFreelancerIDs returns the freelancers hired for the job, in the order they were hired
+func GetJobFreelancerIDs(jobID int) ([]int, error) {
+	o := orm.NewOrm()
+	var assignments []JobAssignment
+
+	_, err := o.QueryTable(new(JobAssignment)).Filter("Job__Id", jobID).OrderBy("id").Limit(-1).All(&assignments, "Freelancer")
+	if err != nil {
+		return nil, err
+	}
+
+	freelancerIDs := make([]int, 0, len(assignments))
+	for _, assignment := range assignments {
+		freelancerIDs = append(freelancerIDs, assignment.Freelancer.Id)
+	}
+	return freelancerIDs, nil
+}
+
+// DeleteJobAssignments removes the freelancer from the job, or every hired freelancer when freelancerID is 0
+func DeleteJobAssignments(o orm.QueryExecutor, jobID, freelancerID int) error {
+	qs := o.QueryTable(new(JobAssignment)).Filter("Job__Id", jobID)
+	if freelancerID != 0 {
+		qs = qs.Filter("Freelancer__Id", freelancerID)
+	}
+
+	_, err := qs.Delete()
+	return err
+}
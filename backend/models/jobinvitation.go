@@ -0,0 +1,171 @@
+package models
+
+import (
+	"backend/types"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// JobInvitation is a client's invitation for a freelancer to apply to a job
+type JobInvitation struct {
+	Id          int          `orm:"pk;auto"`
+	Job         *Job         `orm:"rel(fk);on_delete(cascade)"`
+	Freelancer  *User        `orm:"rel(fk);on_delete(cascade)"`
+	Message     string       `orm:"type(text);null"`
+	Status      string       `orm:"size(20);default(pending)"`        // pending, accepted, declined
+	Application *Application `orm:"rel(fk);null;on_delete(set_null)"` // created when the invitation is accepted
+	CreatedAt   time.Time    `orm:"auto_now_add;type(timestamp)"`
+	RespondedAt *time.Time   `orm:"type(timestamp);null"`
+}
+
+func (i *JobInvitation) TableUnique() [][]string {
+	return [][]string{
+		{"Job", "Freelancer"},
+	}
+}
+
+func init() {
+	orm.RegisterModel(new(JobInvitation))
+}
+
+func (i *JobInvitation) TableName() string {
+	return "job_invitations"
+}
+
+func CreateJobInvitation(jobID, freelancerID int, message string) (int, error) {
+	o := orm.NewOrm()
+
+	invitation := JobInvitation{
+		Job:        &Job{Id: jobID},
+		Freelancer: &User{Id: freelancerID},
+		Message:    message,
+		Status:     "pending",
+	}
+
+	_, err := o.Insert(&invitation)
+	if err != nil {
+		return 0, err
+	}
+
+	return invitation.Id, nil
+}
+
+func JobInvitationExists(jobID, freelancerID int) bool {
+	o := orm.NewOrm()
+	return o.QueryTable(new(JobInvitation)).Filter("Job__Id", jobID).Filter("Freelancer__Id", freelancerID).Exist()
+}
+
+func GetJobInvitationByID(invitationID int) (*JobInvitation, error) {
+	o := orm.NewOrm()
+	var invitation JobInvitation
+
+	err := o.QueryTable(new(JobInvitation)).Filter("Id", invitationID).RelatedSel("Job", "Freelancer").One(&invitation)
+	if err != nil {
+		return nil, err
+	}
+
+	return &invitation, nil
+}
+
+func GetJobInvitationsByJobID(jobID int) ([]JobInvitation, error) {
+	o := orm.NewOrm()
+	var invitations []JobInvitation
+
+	_, err := o.QueryTable(new(JobInvitation)).Filter("Job__Id", jobID).RelatedSel("Freelancer").
+		OrderBy("id").Limit(-1).All(&invitations)
+	if err != nil {
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+// GetJobInvitationsByFreelancerID returns the freelancer's invitations to jobs that were not deleted, newest first
+func GetJobInvitationsByFreelancerID(freelancerID int) ([]JobInvitation, error) {
+	o := orm.NewOrm()
+	var invitations []JobInvitation
+
+	_, err := o.QueryTable(new(JobInvitation)).Filter("Freelancer__Id", freelancerID).
+		Filter("Job__DeletedAt__isnull", true).RelatedSel("Job").
+		OrderBy("-created_at", "-id").Limit(-1).All(&invitations)
+	if err != nil {
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+// GetInvitedJobIDs returns the set of job IDs the freelancer was invited to
+func GetInvitedJobIDs(freelancerID int) (map[int]bool, error) {
+	o := orm.NewOrm()
+	var invitations []JobInvitation
+
+	_, err := o.QueryTable(new(JobInvitation)).Filter("Freelancer__Id", freelancerID).Limit(-1).All(&invitations, "Job")
+	if err != nil {
+		return nil, err
+	}
+
+	invited := make(map[int]bool, len(invitations))
+	for _, invitation := range invitations {
+		invited[invitation.Job.Id] = true
+	}
+
+	return invited, nil
+}
+
+// AcceptJobInvitation creates the freelancer's application to the job and marks the invitation accepted
+// in one transaction, invited freelancers apply without spending credits
+func AcceptJobInvitation(invitation *JobInvitation, freelancer *User, description string, answers []types.ScreeningAnswer) (int, error) {
+	if invitation.Status != "pending" {
+		return 0, errors.New("invitation already answered")
+	}
+
+	o := orm.NewOrm()
+	var applicationID int
+	now := time.Now()
+
+	err := o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		var err error
+		applicationID, err = createApplication(txOrm, freelancer, invitation.Job, description, answers, nil, false)
+		if err != nil {
+			return err
+		}
+
+		// Only a pending invitation is accepted, a concurrent answer rolls the application back
+		num, err := txOrm.QueryTable(new(JobInvitation)).Filter("Id", invitation.Id).Filter("Status", "pending").
+			Update(orm.Params{"status": "accepted", "application_id": applicationID, "responded_at": now})
+		if err != nil {
+			return err
+		}
+		if num == 0 {
+			return errors.New("invitation already answered")
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	invitation.Status = "accepted"
+	invitation.Application = &Application{Id: applicationID}
+	invitation.RespondedAt = &now
+
+	return applicationID, nil
+}
+
+func DeclineJobInvitation(invitation *JobInvitation) error {
+	if invitation.Status != "pending" {
+		return errors.New("invitation already answered")
+	}
+
+	now := time.Now()
+	invitation.Status = "declined"
+	invitation.RespondedAt = &now
+
+	o := orm.NewOrm()
+	_, err := o.Update(invitation, "Status", "RespondedAt")
+	return err
+}
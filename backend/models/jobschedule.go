@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// publishOrSchedule opens the job now, or schedules it when its publish time is in the future
+func (j *Job) publishOrSchedule(now time.Time) {
+	if j.PublishAt != nil && j.PublishAt.After(now) {
+		j.Status = "scheduled"
+		return
+	}
+
+	j.Status = "open"
+	j.PublishAt = &now
+}
+
+// jobPublished queues the work that follows a job going live
+func jobPublished(jobID int) {
+	QueueJobMatchScoreRefresh(jobID)
+	QueueJobAlerts(jobID)
+}
+
+// ExtendJob moves the expiry of an open or scheduled job
+func ExtendJob(job *Job, expiresAt *time.Time) error {
+	o := orm.NewOrm()
+
+	job.ExpiresAt = expiresAt
+
+	_, err := o.Update(job, "ExpiresAt")
+	return err
+}
+
+// GetDueScheduledJobs returns the scheduled jobs whose publish time has come
+func GetDueScheduledJobs() ([]Job, error) {
+	o := orm.NewOrm()
+	var jobs []Job
+
+	_, err := o.QueryTable(new(Job)).Filter("Status", "scheduled").Filter("PublishAt__lte", time.Now()).
+		Filter("DeletedAt__isnull", true).Limit(-1).All(&jobs)
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// GetDueExpiredJobs returns the open jobs past their expiry
+func GetDueExpiredJobs() ([]Job, error) {
+	o := orm.NewOrm()
+	var jobs []Job
+
+	_, err := o.QueryTable(new(Job)).Filter("Status", "open").Filter("ExpiresAt__lte", time.Now()).
+		Filter("DeletedAt__isnull", true).Limit(-1).All(&jobs)
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
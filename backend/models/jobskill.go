@@ -0,0 +1,86 @@
+package models
+
+import (
+	"backend/types"
+	"fmt"
+
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// JobSkill is a row of the job_skills join table with how strongly the job needs the skill
+type JobSkill struct {
+	Id       int    `orm:"pk;auto"`
+	Job      *Job   `orm:"rel(fk);on_delete(cascade);column(jobs_id)"`
+	Skill    *Skill `orm:"rel(fk);on_delete(cascade);column(skills_id)"`
+	Required bool   `orm:"default(true)"` // false for nice-to-have skills
+	MinLevel string `orm:"size(20);null"` // beginner, intermediate, advanced, expert, empty for any level
+}
+
+func init() {
+	orm.RegisterModel(new(JobSkill))
+}
+
+func (j *JobSkill) TableName() string {
+	return "job_skills"
+}
+
+func GetJobSkills(jobID int) ([]JobSkill, error) {
+	o := orm.NewOrm()
+	var jobSkills []JobSkill
+
+	_, err := o.QueryTable(new(JobSkill)).Filter("Job__Id", jobID).RelatedSel("Skill").OrderBy("-required", "id").All(&jobSkills)
+	if err != nil {
+		return nil, err
+	}
+
+	return jobSkills, nil
+}
+
+// setJobSkills replaces the skills of the job, skipping skills that do not exist.
+// Skills default to required when the request does not say otherwise.
+func setJobSkills(o orm.QueryExecutor, jobID int, skills []*types.Skill) error {
+	_, err := o.QueryTable(new(JobSkill)).Filter("Job__Id", jobID).Delete()
+	if err != nil {
+		return err
+	}
+
+	added := make(map[int]bool)
+	for _, skillReq := range skills {
+		if added[skillReq.Id] || !o.QueryTable(new(Skill)).Filter("Id", skillReq.Id).Exist() { // Only add existing skills once
+			continue
+		}
+		added[skillReq.Id] = true
+
+		jobSkill := JobSkill{
+			Job:      &Job{Id: jobID},
+			Skill:    &Skill{Id: skillReq.Id},
+			Required: skillReq.Required == nil || *skillReq.Required,
+			MinLevel: skillReq.MinLevel,
+		}
+		if _, err := o.Insert(&jobSkill); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetMatchingOpenJobIDs returns the open jobs whose required skills the freelancer has at the minimum level
+func GetMatchingOpenJobIDs(freelancerDataID int) ([]int, error) {
+	o := orm.NewOrm()
+	var jobIDs []int
+
+	_, err := o.Raw(`SELECT j.id FROM jobs j
+		WHERE j.status = 'open' AND j.deleted_at IS NULL AND NOT EXISTS (
+			SELECT 1 FROM job_skills js WHERE js.jobs_id = j.id AND js.required AND NOT EXISTS (
+				SELECT 1 FROM freelancer_skills fs
+				WHERE fs.freelancer_data_id = ? AND fs.skills_id = js.skills_id
+				AND `+fmt.Sprintf(skillLevelRank, "fs.level")+` >= `+fmt.Sprintf(skillLevelRank, "js.min_level")+`
+			)
+		)`, freelancerDataID).QueryRows(&jobIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return jobIDs, nil
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// JobStatusHistory records a change of a job's status
+type JobStatusHistory struct {
+	Id         int       `orm:"pk;auto"`
+	Job        *Job      `orm:"rel(fk);on_delete(cascade)"`
+	FromStatus string    `orm:"size(30);null"` // empty when the job was created
+	ToStatus   string    `orm:"size(30)"`
+	Event      string    `orm:"size(30)"`                         // create, publish, schedule, hire, complete, expire, republish, cancel, pause, resume, reopen
+	Actor      *User     `orm:"rel(fk);null;on_delete(set_null)"` // null for changes made by the scheduler
+	Reason     string    `orm:"type(text);null"`
+	CreatedAt  time.Time `orm:"auto_now_add;type(timestamp)"`
+}
+
+func init() {
+	orm.RegisterModel(new(JobStatusHistory))
+}
+
+func (h *JobStatusHistory) TableName() string {
+	return "job_status_history"
+}
+
+// CreateJobStatusHistory records a status change, actorID is 0 for changes made by the scheduler
+func CreateJobStatusHistory(o orm.QueryExecutor, jobID int, fromStatus, toStatus, event string, actorID int, reason string) error {
+	history := JobStatusHistory{
+		Job:        &Job{Id: jobID},
+		FromStatus: fromStatus,
+		ToStatus:   toStatus,
+		Event:      event,
+		Reason:     reason,
+	}
+	if actorID != 0 {
+		history.Actor = &User{Id: actorID}
+	}
+
+	_, err := o.Insert(&history)
+	return err
+}
+
+// GetJobStatusHistory returns the status changes of the job, oldest first
+func GetJobStatusHistory(jobID int) ([]JobStatusHistory, error) {
+	o := orm.NewOrm()
+	var history []JobStatusHistory
+
+	_, err := o.QueryTable(new(JobStatusHistory)).Filter("Job__Id", jobID).OrderBy("created_at", "id").Limit(-1).All(&history)
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// GetStatusBeforePause returns the status the job had when it was last paused
+func GetStatusBeforePause(jobID int) (string, error) {
+	o := orm.NewOrm()
+	var history JobStatusHistory
+
+	err := o.QueryTable(new(JobStatusHistory)).Filter("Job__Id", jobID).Filter("ToStatus", "paused").
+		OrderBy("-created_at", "-id").Limit(1).One(&history)
+	if err != nil {
+		return "", err
+	}
+
+	return history.FromStatus, nil
+}
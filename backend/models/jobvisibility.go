@@ -0,0 +1,84 @@
+package models
+
+import (
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// JobViewer holds what decides which jobs a user can see, so that lists of jobs
+// are checked without a query per job
+type JobViewer struct {
+	User          *User
+	InvitedJobIDs map[int]bool
+	SkillLevels   map[int]string // skill ID to the freelancer's level
+	CompletedJobs int
+}
+
+func NewJobViewer(user *User) (*JobViewer, error) {
+	viewer := &JobViewer{User: user}
+	if user.Role != "freelancer" {
+		return viewer, nil
+	}
+
+	var err error
+	viewer.InvitedJobIDs, err = GetInvitedJobIDs(user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	o := orm.NewOrm()
+
+	var freelancerSkills []FreelancerSkill
+	_, err = o.QueryTable(new(FreelancerSkill)).Filter("FreelancerData__User__Id", user.Id).Limit(-1).All(&freelancerSkills)
+	if err != nil {
+		return nil, err
+	}
+	viewer.SkillLevels = make(map[int]string, len(freelancerSkills))
+	for _, freelancerSkill := range freelancerSkills {
+		viewer.SkillLevels[freelancerSkill.Skill.Id] = freelancerSkill.Level
+	}
+
+	completedJobs, err := o.QueryTable(new(JobAssignment)).Filter("Freelancer__Id", user.Id).Filter("Job__Status", "completed").
+		Filter("Job__DeletedAt__isnull", true).Count()
+	if err != nil {
+		return nil, err
+	}
+	viewer.CompletedJobs = int(completedJobs)
+
+	return viewer, nil
+}
+
+// CanView reports whether the job is visible to the viewer. Restricted and invite-only jobs are
+// always visible to their client, admins and the invited freelancers.
+func (v *JobViewer) CanView(job *Job) bool {
+	if job.Visibility == "public" || v.User.Role == "admin" || job.Client.Id == v.User.Id || v.InvitedJobIDs[job.Id] {
+		return true
+	}
+
+	if job.Visibility == "restricted" && v.User.Role == "freelancer" {
+		return v.isEligible(job)
+	}
+
+	return false
+}
+
+// isEligible checks the freelancer against the restrictions of the job
+func (v *JobViewer) isEligible(job *Job) bool {
+	if job.RestrictedSkill != nil {
+		level, ok := v.SkillLevels[job.RestrictedSkill.Id]
+		if !ok || skillLevelRanks[level] < skillLevelRanks[job.RestrictedSkillLevel] {
+			return false
+		}
+	}
+
+	return v.CompletedJobs >= job.MinCompletedJobs
+}
+
+// CanViewJob reports whether the user can see the job, see JobViewer.CanView
+func CanViewJob(job *Job, user *User) bool {
+	viewer, err := NewJobViewer(user)
+	if err != nil {
+		return false
+	}
+
+	return viewer.CanView(job)
+}
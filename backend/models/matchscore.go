@@ -0,0 +1,517 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// MatchScore is the precomputed fit between an open job and a freelancer, from 0 to 100.
+// Pairs without any skill overlap are not stored.
+type MatchScore struct {
+	Id           int   `orm:"pk;auto"`
+	Job          *Job  `orm:"rel(fk);on_delete(cascade)"`
+	Freelancer   *User `orm:"rel(fk);on_delete(cascade)"`
+	Score        float64
+	SkillScore   float64 // part scores are from 0 to 1
+	RateScore    float64
+	HoursScore   float64
+	HistoryScore float64
+	Reasons      string    `orm:"type(text)"` // JSON list of human readable reasons
+	UpdatedAt    time.Time `orm:"auto_now;type(timestamp)"`
+}
+
+// MatchScoreRefresh queues a job or freelancer whose scores must be recomputed.
+// A target is queued at most once until the refresh task picks it up.
+type MatchScoreRefresh struct {
+	Id         int       `orm:"pk;auto"`
+	TargetType string    `orm:"size(20)"` // job, freelancer
+	TargetId   int       // job ID or freelancer user ID
+	CreatedAt  time.Time `orm:"auto_now_add;type(timestamp)"`
+}
+
+func (m *MatchScore) TableUnique() [][]string {
+	return [][]string{
+		{"Job", "Freelancer"},
+	}
+}
+
+func (m *MatchScoreRefresh) TableUnique() [][]string {
+	return [][]string{
+		{"TargetType", "TargetId"},
+	}
+}
+
+func init() {
+	orm.RegisterModel(new(MatchScore), new(MatchScoreRefresh))
+}
+
+func (m *MatchScore) TableName() string {
+	return "match_scores"
+}
+
+func (m *MatchScoreRefresh) TableName() string {
+	return "match_score_refreshes"
+}
+
+// Weights of the part scores in the total score
+const (
+	skillWeight   = 0.5
+	rateWeight    = 0.2
+	hoursWeight   = 0.15
+	historyWeight = 0.15
+)
+
+// completedJobsForFullHistory is the number of completed jobs that gives the full history score
+const completedJobsForFullHistory = 5
+
+var skillLevelRanks = map[string]int{
+	"beginner":     1,
+	"intermediate": 2,
+	"advanced":     3,
+	"expert":       4,
+}
+
+var hoursPerWeekBuckets = []string{"<20", "20-40", "40-60", "60-80", "80+"}
+
+func (m *MatchScore) ReasonList() []string {
+	var reasons []string
+	if err := json.Unmarshal([]byte(m.Reasons), &reasons); err != nil {
+		return nil
+	}
+	return reasons
+}
+
+type matchJob struct {
+	Job    Job
+	Skills []JobSkill
+}
+
+type matchFreelancer struct {
+	Data          FreelancerData
+	Skills        []FreelancerSkill
+	CompletedJobs int
+}
+
+// loadMatchJobs returns the open job with jobID, or every open job when jobID is 0
+func loadMatchJobs(o orm.Ormer, jobID int) ([]matchJob, error) {
+	var jobs []Job
+	qs := o.QueryTable(new(Job)).Filter("Status", "open").Filter("DeletedAt__isnull", true)
+	if jobID != 0 {
+		qs = qs.Filter("Id", jobID)
+	}
+	if _, err := qs.Limit(-1).All(&jobs); err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+
+	jobIDs := make([]int, 0, len(jobs))
+	for _, job := range jobs {
+		jobIDs = append(jobIDs, job.Id)
+	}
+
+	var jobSkills []JobSkill
+	_, err := o.QueryTable(new(JobSkill)).Filter("Job__Id__in", jobIDs).RelatedSel("Skill").Limit(-1).All(&jobSkills)
+	if err != nil {
+		return nil, err
+	}
+
+	skillsByJob := make(map[int][]JobSkill)
+	for _, jobSkill := range jobSkills {
+		skillsByJob[jobSkill.Job.Id] = append(skillsByJob[jobSkill.Job.Id], jobSkill)
+	}
+
+	matchJobs := make([]matchJob, 0, len(jobs))
+	for _, job := range jobs {
+		matchJobs = append(matchJobs, matchJob{Job: job, Skills: skillsByJob[job.Id]})
+	}
+
+	return matchJobs, nil
+}
+
+// loadMatchFreelancers returns the active freelancer with userID, or every active freelancer when userID is 0
+func loadMatchFreelancers(o orm.Ormer, userID int) ([]matchFreelancer, error) {
+	var freelancerData []FreelancerData
+	qs := o.QueryTable(new(FreelancerData)).Filter("User__Role", "freelancer").
+		Filter("User__DeletedAt__isnull", true).Filter("User__Ban", false)
+	if userID != 0 {
+		qs = qs.Filter("User__Id", userID)
+	}
+	if _, err := qs.Limit(-1).All(&freelancerData); err != nil {
+		return nil, err
+	}
+	if len(freelancerData) == 0 {
+		return nil, nil
+	}
+
+	dataIDs := make([]int, 0, len(freelancerData))
+	for _, data := range freelancerData {
+		dataIDs = append(dataIDs, data.Id)
+	}
+
+	var freelancerSkills []FreelancerSkill
+	_, err := o.QueryTable(new(FreelancerSkill)).Filter("FreelancerData__Id__in", dataIDs).Limit(-1).All(&freelancerSkills)
+	if err != nil {
+		return nil, err
+	}
+
+	skillsByData := make(map[int][]FreelancerSkill)
+	for _, freelancerSkill := range freelancerSkills {
+		skillsByData[freelancerSkill.FreelancerData.Id] = append(skillsByData[freelancerSkill.FreelancerData.Id], freelancerSkill)
+	}
+
+	var freelancerIDs, completedCounts []int
+	_, err = o.Raw(`SELECT ja.freelancer_id, COUNT(*) FROM job_assignments ja
+		JOIN jobs j ON j.id = ja.job_id
+		WHERE j.status = 'completed' AND j.deleted_at IS NULL
+		GROUP BY ja.freelancer_id`).QueryRows(&freelancerIDs, &completedCounts)
+	if err != nil {
+		return nil, err
+	}
+
+	completedByUser := make(map[int]int, len(freelancerIDs))
+	for i, freelancerID := range freelancerIDs {
+		completedByUser[freelancerID] = completedCounts[i]
+	}
+
+	matchFreelancers := make([]matchFreelancer, 0, len(freelancerData))
+	for _, data := range freelancerData {
+		matchFreelancers = append(matchFreelancers, matchFreelancer{
+			Data:          data,
+			Skills:        skillsByData[data.Id],
+			CompletedJobs: completedByUser[data.User.Id],
+		})
+	}
+
+	return matchFreelancers, nil
+}
+
+// scoreMatch scores how well the freelancer fits the job and explains why.
+// It returns nil when the job lists skills and the freelancer has none of them.
+func scoreMatch(job *matchJob, freelancer *matchFreelancer) *MatchScore {
+	var reasons []string
+
+	levels := make(map[int]string, len(freelancer.Skills))
+	for _, freelancerSkill := range freelancer.Skills {
+		levels[freelancerSkill.Skill.Id] = freelancerSkill.Level
+	}
+
+	// Required skills count twice as much as nice-to-have skills, skills below the minimum level count half
+	skillScore := 0.5
+	if len(job.Skills) > 0 {
+		var total, credit float64
+		var requiredCount int
+		var matchedRequired, matchedOptional, belowLevel []string
+
+		for _, jobSkill := range job.Skills {
+			weight := 1.0
+			if jobSkill.Required {
+				weight = 2.0
+				requiredCount++
+			}
+			total += weight
+
+			level, ok := levels[jobSkill.Skill.Id]
+			if !ok {
+				continue
+			}
+
+			if skillLevelRanks[level] >= skillLevelRanks[jobSkill.MinLevel] {
+				credit += weight
+				if jobSkill.Required {
+					matchedRequired = append(matchedRequired, jobSkill.Skill.Name)
+				} else {
+					matchedOptional = append(matchedOptional, jobSkill.Skill.Name)
+				}
+			} else {
+				credit += weight / 2
+				belowLevel = append(belowLevel, jobSkill.Skill.Name)
+			}
+		}
+
+		if credit == 0 {
+			return nil
+		}
+		skillScore = credit / total
+
+		if len(matchedRequired) > 0 {
+			reasons = append(reasons, fmt.Sprintf("Has %d of %d required skills: %s", len(matchedRequired), requiredCount, strings.Join(matchedRequired, ", ")))
+		}
+		if len(matchedOptional) > 0 {
+			reasons = append(reasons, fmt.Sprintf("Has nice-to-have skills: %s", strings.Join(matchedOptional, ", ")))
+		}
+		if len(belowLevel) > 0 {
+			reasons = append(reasons, fmt.Sprintf("Below the minimum level in: %s", strings.Join(belowLevel, ", ")))
+		}
+	} else {
+		reasons = append(reasons, "Job does not require specific skills")
+	}
+
+	// Only hourly jobs can be compared with the freelancer's hourly rate
+	rateScore := 0.5
+	hourlyRate := freelancer.Data.HourlyRate
+	if job.Job.Rate == "hourly" && hourlyRate > 0 {
+		if hourlyRate <= float64(job.Job.Amount) {
+			rateScore = 1
+			reasons = append(reasons, fmt.Sprintf("Hourly rate %.0f fits the budget of %d", hourlyRate, job.Job.Amount))
+		} else {
+			rateScore = float64(job.Job.Amount) / hourlyRate
+			reasons = append(reasons, fmt.Sprintf("Hourly rate %.0f is above the budget of %d", hourlyRate, job.Job.Amount))
+		}
+	}
+
+	hoursScore := 0.5
+	jobHours, freelancerHours := bucketIndex(job.Job.HoursPerWeek), bucketIndex(freelancer.Data.HoursPerWeek)
+	if jobHours >= 0 && freelancerHours >= 0 {
+		switch distance := int(math.Abs(float64(jobHours - freelancerHours))); distance {
+		case 0:
+			hoursScore = 1
+			reasons = append(reasons, fmt.Sprintf("Available %s hours per week as requested", freelancer.Data.HoursPerWeek))
+		case 1:
+			hoursScore = 0.5
+			reasons = append(reasons, fmt.Sprintf("Available %s hours per week, close to the requested %s", freelancer.Data.HoursPerWeek, job.Job.HoursPerWeek))
+		default:
+			hoursScore = 0
+		}
+	}
+
+	historyScore := math.Min(float64(freelancer.CompletedJobs), completedJobsForFullHistory) / completedJobsForFullHistory
+	if freelancer.CompletedJobs > 0 {
+		reasons = append(reasons, fmt.Sprintf("Completed %d jobs on the platform", freelancer.CompletedJobs))
+	}
+
+	score := 100 * (skillWeight*skillScore + rateWeight*rateScore + hoursWeight*hoursScore + historyWeight*historyScore)
+
+	reasonsJSON, _ := json.Marshal(reasons)
+
+	return &MatchScore{
+		Job:          &Job{Id: job.Job.Id},
+		Freelancer:   &User{Id: freelancer.Data.User.Id},
+		Score:        math.Round(score*10) / 10,
+		SkillScore:   skillScore,
+		RateScore:    rateScore,
+		HoursScore:   hoursScore,
+		HistoryScore: historyScore,
+		Reasons:      string(reasonsJSON),
+		UpdatedAt:    time.Now(),
+	}
+}
+
+func bucketIndex(hoursPerWeek string) int {
+	for i, bucket := range hoursPerWeekBuckets {
+		if bucket == hoursPerWeek {
+			return i
+		}
+	}
+	return -1
+}
+
+// replaceMatchScores recomputes the scores of every job and freelancer pair and
+// replaces the stored scores matched by the scope filter in one transaction.
+func replaceMatchScores(jobs []matchJob, freelancers []matchFreelancer, scope func(qs orm.QuerySeter) orm.QuerySeter) error {
+	var scores []MatchScore
+	for i := range jobs {
+		for j := range freelancers {
+			if score := scoreMatch(&jobs[i], &freelancers[j]); score != nil {
+				scores = append(scores, *score)
+			}
+		}
+	}
+
+	o := orm.NewOrm()
+	return o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		if _, err := scope(txOrm.QueryTable(new(MatchScore))).Delete(); err != nil {
+			return err
+		}
+
+		if len(scores) == 0 {
+			return nil
+		}
+
+		_, err := txOrm.InsertMulti(100, scores)
+		return err
+	})
+}
+
+// RefreshJobMatchScores recomputes the scores of one job against every freelancer.
+// Scores of jobs that are no longer open are removed.
+func RefreshJobMatchScores(jobID int) error {
+	o := orm.NewOrm()
+
+	jobs, err := loadMatchJobs(o, jobID)
+	if err != nil {
+		return err
+	}
+
+	var freelancers []matchFreelancer
+	if len(jobs) > 0 {
+		if freelancers, err = loadMatchFreelancers(o, 0); err != nil {
+			return err
+		}
+	}
+
+	return replaceMatchScores(jobs, freelancers, func(qs orm.QuerySeter) orm.QuerySeter {
+		return qs.Filter("Job__Id", jobID)
+	})
+}
+
+// RefreshFreelancerMatchScores recomputes the scores of one freelancer against every open job
+func RefreshFreelancerMatchScores(userID int) error {
+	o := orm.NewOrm()
+
+	freelancers, err := loadMatchFreelancers(o, userID)
+	if err != nil {
+		return err
+	}
+
+	var jobs []matchJob
+	if len(freelancers) > 0 {
+		if jobs, err = loadMatchJobs(o, 0); err != nil {
+			return err
+		}
+	}
+
+	return replaceMatchScores(jobs, freelancers, func(qs orm.QuerySeter) orm.QuerySeter {
+		return qs.Filter("Freelancer__Id", userID)
+	})
+}
+
+// RebuildMatchScores recomputes the scores of every open job and freelancer
+func RebuildMatchScores() error {
+	o := orm.NewOrm()
+
+	jobs, err := loadMatchJobs(o, 0)
+	if err != nil {
+		return err
+	}
+
+	freelancers, err := loadMatchFreelancers(o, 0)
+	if err != nil {
+		return err
+	}
+
+	return replaceMatchScores(jobs, freelancers, func(qs orm.QuerySeter) orm.QuerySeter {
+		return qs.Filter("Id__gt", 0)
+	})
+}
+
+// queueMatchScoreRefresh marks a job or freelancer for the refresh task.
+// Failing to queue is not fatal as the nightly rebuild recomputes every score.
+func queueMatchScoreRefresh(targetType string, targetID int) {
+	o := orm.NewOrm()
+	o.Raw(`INSERT INTO match_score_refreshes (target_type, target_id, created_at) VALUES (?, ?, ?)
+		ON CONFLICT (target_type, target_id) DO NOTHING`, targetType, targetID, time.Now()).Exec()
+}
+
+func QueueJobMatchScoreRefresh(jobID int) {
+	queueMatchScoreRefresh("job", jobID)
+}
+
+func QueueFreelancerMatchScoreRefresh(userID int) {
+	queueMatchScoreRefresh("freelancer", userID)
+}
+
+// ProcessMatchScoreRefreshes recomputes the scores of up to limit queued targets, oldest first
+func ProcessMatchScoreRefreshes(limit int) (int, error) {
+	o := orm.NewOrm()
+	var refreshes []MatchScoreRefresh
+
+	_, err := o.QueryTable(new(MatchScoreRefresh)).OrderBy("id").Limit(limit).All(&refreshes)
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+	for _, refresh := range refreshes {
+		// Remove the entry first so changes made during the refresh queue it again
+		if _, err := o.Delete(&refresh); err != nil {
+			return processed, err
+		}
+
+		switch refresh.TargetType {
+		case "job":
+			err = RefreshJobMatchScores(refresh.TargetId)
+		case "freelancer":
+			err = RefreshFreelancerMatchScores(refresh.TargetId)
+		}
+		if err != nil {
+			return processed, err
+		}
+		processed++
+	}
+
+	return processed, nil
+}
+
+// GetRecommendedJobScores returns the best scored open jobs the freelancer has not applied to
+func GetRecommendedJobScores(userID, limit int) ([]MatchScore, error) {
+	o := orm.NewOrm()
+	var scores []MatchScore
+
+	var appliedJobIDs orm.ParamsList
+	_, err := o.QueryTable(new(Application)).Filter("User__Id", userID).ValuesFlat(&appliedJobIDs, "Job")
+	if err != nil {
+		return nil, err
+	}
+
+	invitedJobIDs, err := GetInvitedJobIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invite-only jobs are only recommended to the freelancers invited to them, the eligibility
+	// for restricted jobs is left to the caller
+	visible := orm.NewCondition().Or("Job__Visibility__in", "public", "restricted")
+	if len(invitedJobIDs) > 0 {
+		jobIDs := make([]int, 0, len(invitedJobIDs))
+		for jobID := range invitedJobIDs {
+			jobIDs = append(jobIDs, jobID)
+		}
+		visible = visible.Or("Job__Id__in", jobIDs)
+	}
+
+	qs := o.QueryTable(new(MatchScore)).SetCond(orm.NewCondition().AndCond(visible)).Filter("Freelancer__Id", userID).
+		Filter("Job__Status", "open").Filter("Job__DeletedAt__isnull", true)
+	if len(appliedJobIDs) > 0 {
+		qs = qs.Exclude("Job__Id__in", appliedJobIDs)
+	}
+
+	_, err = qs.RelatedSel("Job").OrderBy("-score", "id").Limit(limit).All(&scores)
+	if err != nil {
+		return nil, err
+	}
+
+	return scores, nil
+}
+
+// GetRecommendedFreelancerScores returns the best scored active freelancers for the job who have not applied to it
+func GetRecommendedFreelancerScores(jobID, limit int) ([]MatchScore, error) {
+	o := orm.NewOrm()
+	var scores []MatchScore
+
+	var applicantIDs orm.ParamsList
+	_, err := o.QueryTable(new(Application)).Filter("Job__Id", jobID).ValuesFlat(&applicantIDs, "User")
+	if err != nil {
+		return nil, err
+	}
+
+	qs := o.QueryTable(new(MatchScore)).Filter("Job__Id", jobID).
+		Filter("Freelancer__DeletedAt__isnull", true).Filter("Freelancer__Ban", false)
+	if len(applicantIDs) > 0 {
+		qs = qs.Exclude("Freelancer__Id__in", applicantIDs)
+	}
+
+	_, err = qs.RelatedSel("Freelancer").OrderBy("-score", "id").Limit(limit).All(&scores)
+	if err != nil {
+		return nil, err
+	}
+
+	return scores, nil
+}
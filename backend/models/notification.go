@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// Notification is an in-app message shown to a user
+type Notification struct {
+	Id        int        `orm:"pk;auto"`
+	User      *User      `orm:"rel(fk);on_delete(cascade)"`
+	Type      string     `orm:"size(30)"` // job-alert, job-invitation, job-expired, job-status, application-stage, application-withdrawn, offer, credits
+	Title     string     `orm:"size(255)"`
+	Message   string     `orm:"type(text)"`
+	Link      string     `orm:"size(255);null"` // frontend path the notification points to
+	ReadAt    *time.Time `orm:"type(timestamp);null"`
+	CreatedAt time.Time  `orm:"auto_now_add;type(timestamp)"`
+}
+
+func init() {
+	orm.RegisterModel(new(Notification))
+}
+
+func (n *Notification) TableName() string {
+	return "notifications"
+}
+
+func CreateNotification(userID int, notificationType, title, message, link string) error {
+	o := orm.NewOrm()
+
+	notification := Notification{
+		User:    &User{Id: userID},
+		Type:    notificationType,
+		Title:   title,
+		Message: message,
+		Link:    link,
+	}
+
+	_, err := o.Insert(&notification)
+	return err
+}
+
+// GetNotificationsByUserID returns the user's notifications, newest first
+func GetNotificationsByUserID(userID int, unreadOnly bool) ([]Notification, error) {
+	o := orm.NewOrm()
+	var notifications []Notification
+
+	qs := o.QueryTable(new(Notification)).Filter("User__Id", userID)
+	if unreadOnly {
+		qs = qs.Filter("ReadAt__isnull", true)
+	}
+
+	_, err := qs.OrderBy("-created_at", "-id").Limit(100).All(&notifications)
+	if err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+func GetUnreadNotificationCount(userID int) (int, error) {
+	o := orm.NewOrm()
+	count, err := o.QueryTable(new(Notification)).Filter("User__Id", userID).Filter("ReadAt__isnull", true).Count()
+	return int(count), err
+}
+
+// MarkNotificationsRead marks the given notifications of the user as read, or all of them when no IDs are given
+func MarkNotificationsRead(userID int, notificationIDs ...int) error {
+	o := orm.NewOrm()
+
+	qs := o.QueryTable(new(Notification)).Filter("User__Id", userID).Filter("ReadAt__isnull", true)
+	if len(notificationIDs) > 0 {
+		qs = qs.Filter("Id__in", notificationIDs)
+	}
+
+	_, err := qs.Update(orm.Params{"read_at": time.Now()})
+	return err
+}
@@ -0,0 +1,181 @@
+package models
+
+import (
+	"backend/types"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// Offer is a round of the negotiation of an application's terms. The client sends the first offer and
+// every counter-offer is a new round proposed by the other party. The freelancer is hired when the
+// party the pending offer waits on accepts it.
+type Offer struct {
+	Id          int          `orm:"pk;auto"`
+	Application *Application `orm:"rel(fk);on_delete(cascade)"`
+	Round       int          // 1 for the first offer, each counter-offer adds one
+	ProposedBy  string       `orm:"size(20)"` // client, freelancer
+	Rate        string       `orm:"size(30)"` // hourly, fixed
+	Amount      int          // if hourly, amount per hour, if fixed, total amount
+	StartDate   *time.Time   `orm:"type(date);null"`
+	Milestones  string       `orm:"type(text);null"` // JSON list of types.OfferMilestone
+	Message     string       `orm:"type(text);null"`
+	Status      string       `orm:"size(20);default(pending)"` // pending, countered, accepted, declined, withdrawn
+	CreatedAt   time.Time    `orm:"auto_now_add;type(timestamp)"`
+	RespondedAt *time.Time   `orm:"type(timestamp);null"`
+}
+
+func init() {
+	orm.RegisterModel(new(Offer))
+}
+
+func (o *Offer) TableName() string {
+	return "offers"
+}
+
+// MilestoneList returns the milestones of the offer
+func (o *Offer) MilestoneList() []types.OfferMilestone {
+	milestones := []types.OfferMilestone{}
+	if o.Milestones != "" {
+		json.Unmarshal([]byte(o.Milestones), &milestones)
+	}
+	return milestones
+}
+
+// GetOffers returns the negotiation history of the application, oldest round first
+func GetOffers(applicationID int) ([]Offer, error) {
+	o := orm.NewOrm()
+	var offers []Offer
+
+	_, err := o.QueryTable(new(Offer)).Filter("Application__Id", applicationID).OrderBy("round").Limit(-1).All(&offers)
+	if err != nil {
+		return nil, err
+	}
+
+	return offers, nil
+}
+
+// GetPendingOffer returns the offer of the application waiting for an answer, orm.ErrNoRows when there is none
+func GetPendingOffer(applicationID int) (*Offer, error) {
+	o := orm.NewOrm()
+	var offer Offer
+
+	err := o.QueryTable(new(Offer)).Filter("Application__Id", applicationID).Filter("Status", "pending").One(&offer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &offer, nil
+}
+
+// ProposeOffer adds a negotiation round with the terms, the pending offer it answers is marked as countered.
+// The application moves to the offer-sent stage.
+func ProposeOffer(application *Application, proposedBy string, terms *types.OfferTerms) (*Offer, error) {
+	o := orm.NewOrm()
+	now := time.Now()
+
+	offer := Offer{
+		Application: application,
+		ProposedBy:  proposedBy,
+		Rate:        terms.Rate,
+		Amount:      terms.Amount,
+		StartDate:   terms.StartDate,
+		Message:     terms.Message,
+		Status:      "pending",
+	}
+	if len(terms.Milestones) > 0 {
+		milestones, err := json.Marshal(terms.Milestones)
+		if err != nil {
+			return nil, err
+		}
+		offer.Milestones = string(milestones)
+	}
+
+	err := o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		_, err := txOrm.QueryTable(new(Offer)).Filter("Application__Id", application.Id).Filter("Status", "pending").
+			Update(orm.Params{"status": "countered", "responded_at": now})
+		if err != nil {
+			return err
+		}
+
+		rounds, err := txOrm.QueryTable(new(Offer)).Filter("Application__Id", application.Id).Count()
+		if err != nil {
+			return err
+		}
+		offer.Round = int(rounds) + 1
+
+		if _, err := txOrm.Insert(&offer); err != nil {
+			return err
+		}
+
+		application.Status = "offer-sent"
+		_, err = txOrm.Update(application, "Status")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &offer, nil
+}
+
+// DeclineOffer ends the negotiation, the application moves to the offer-declined stage
+// where the client can send a new offer or reject it
+func DeclineOffer(offer *Offer, application *Application) error {
+	o := orm.NewOrm()
+	now := time.Now()
+
+	return o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		offer.Status = "declined"
+		offer.RespondedAt = &now
+		if _, err := txOrm.Update(offer, "Status", "RespondedAt"); err != nil {
+			return err
+		}
+
+		application.Status = "offer-declined"
+		_, err := txOrm.Update(application, "Status")
+		return err
+	})
+}
+
+// AcceptOffer marks the offer as mutually accepted, the hire that follows is done by the caller.
+// Only a pending offer is accepted, so an offer answered or withdrawn in the meantime fails
+func AcceptOffer(o orm.QueryExecutor, offer *Offer) error {
+	now := time.Now()
+
+	num, err := o.QueryTable(new(Offer)).Filter("Id", offer.Id).Filter("Status", "pending").
+		Update(orm.Params{"status": "accepted", "responded_at": now})
+	if err != nil {
+		return err
+	}
+	if num == 0 {
+		return errors.New("offer already answered")
+	}
+
+	offer.Status = "accepted"
+	offer.RespondedAt = &now
+	return nil
+}
+
+// WithdrawPendingOffer withdraws the pending offer of the application, if any
+func WithdrawPendingOffer(o orm.QueryExecutor, applicationID int) error {
+	_, err := o.QueryTable(new(Offer)).Filter("Application__Id", applicationID).Filter("Status", "pending").
+		Update(orm.Params{"status": "withdrawn", "responded_at": time.Now()})
+	return err
+}
+
+// withdrawJobOffers withdraws the pending offers of the job's applications under review,
+// except for the application with exceptApplicationID
+func withdrawJobOffers(o orm.QueryExecutor, jobID, exceptApplicationID int) error {
+	qs := o.QueryTable(new(Offer)).Filter("Status", "pending").Filter("Application__Job__Id", jobID).
+		Filter("Application__Status__in", reviewStages)
+	if exceptApplicationID != 0 {
+		qs = qs.Exclude("Application__Id", exceptApplicationID)
+	}
+
+	_, err := qs.Update(orm.Params{"status": "withdrawn", "responded_at": time.Now()})
+	return err
+}
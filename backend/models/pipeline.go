@@ -0,0 +1,94 @@
+package models
+
+import (
+	"context"
+	"strings"
+
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// ApplicationStages are the statuses of an application in the order of the hiring pipeline
+var ApplicationStages = []string{"pending", "shortlisted", "interviewing", "offer-sent", "offer-declined", "accepted", "rejected", "withdrawn"}
+
+// reviewStages are the statuses of applications the client has not decided on yet
+var reviewStages = []string{"pending", "shortlisted", "interviewing", "offer-sent", "offer-declined"}
+
+// InReview reports whether the client has not accepted or rejected the application yet
+func (a *Application) InReview() bool {
+	for _, stage := range reviewStages {
+		if a.Status == stage {
+			return true
+		}
+	}
+	return false
+}
+
+func (j *Job) ExposedStageList() []string {
+	stages := []string{}
+	if j.ExposedStages != "" {
+		stages = strings.Split(j.ExposedStages, ",")
+	}
+	return stages
+}
+
+func (j *Job) SetExposedStages(stages []string) {
+	j.ExposedStages = strings.Join(stages, ",")
+}
+
+// FreelancerStatus returns the status of an application to the job as the freelancer sees it,
+// review stages the client does not expose show as pending. The offer stages always show,
+// the freelancer takes part in the negotiation.
+func (j *Job) FreelancerStatus(status string) string {
+	switch status {
+	case "pending", "offer-sent", "offer-declined", "accepted", "rejected", "withdrawn":
+		return status
+	}
+
+	for _, stage := range j.ExposedStageList() {
+		if stage == status {
+			return status
+		}
+	}
+	return "pending"
+}
+
+// MoveApplicationToStage moves an application under review to another review stage,
+// a pending offer on it is withdrawn
+func MoveApplicationToStage(application *Application, stage string) error {
+	o := orm.NewOrm()
+
+	return o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		if err := WithdrawPendingOffer(txOrm, application.Id); err != nil {
+			return err
+		}
+
+		application.Status = stage
+		_, err := txOrm.Update(application, "Status")
+		return err
+	})
+}
+
+// RejectApplication rejects an application under review with the reason, a pending offer on it is withdrawn
+func RejectApplication(application *Application, reason string) error {
+	o := orm.NewOrm()
+
+	return o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		if err := WithdrawPendingOffer(txOrm, application.Id); err != nil {
+			return err
+		}
+
+		application.Status = "rejected"
+		application.RejectionReason = reason
+		_, err := txOrm.Update(application, "Status", "RejectionReason")
+		return err
+	})
+}
+
+func UpdateApplicationNotes(application *Application, notes string) error {
+	o := orm.NewOrm()
+
+	application.Notes = notes
+
+	_, err := o.Update(application, "Notes")
+	return err
+}
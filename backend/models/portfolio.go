@@ -0,0 +1,203 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// PortfolioItem is a piece of past work on a freelancer's profile, with its files stored as attachments.
+// Featured items are shown first, then the items in the order of Position.
+type PortfolioItem struct {
+	Id          int        `orm:"pk;auto"`
+	Freelancer  *User      `orm:"rel(fk);on_delete(cascade)"`
+	Title       string     `orm:"size(100)"`
+	Description string     `orm:"type(text);null"`
+	Role        string     `orm:"size(100);null"` // the freelancer's role in the project
+	ProjectURL  string     `orm:"size(255);null;column(project_url)"`
+	StartDate   *time.Time `orm:"null;type(date)"`
+	EndDate     *time.Time `orm:"null;type(date)"` // empty for ongoing work
+	Featured    bool       `orm:"default(false)"`
+	Position    int        `orm:"default(0)"`
+	CreatedAt   time.Time  `orm:"auto_now_add;type(timestamp)"`
+	UpdatedAt   time.Time  `orm:"auto_now;type(timestamp)"`
+}
+
+// PortfolioItemSkill is a skill used in a portfolio item
+type PortfolioItemSkill struct {
+	Id            int            `orm:"pk;auto"`
+	PortfolioItem *PortfolioItem `orm:"rel(fk);on_delete(cascade)"`
+	Skill         *Skill         `orm:"rel(fk);on_delete(cascade)"`
+}
+
+func (s *PortfolioItemSkill) TableUnique() [][]string {
+	return [][]string{
+		{"PortfolioItem", "Skill"},
+	}
+}
+
+func init() {
+	orm.RegisterModel(new(PortfolioItem), new(PortfolioItemSkill))
+}
+
+func (p *PortfolioItem) TableName() string {
+	return "portfolio_items"
+}
+
+func (s *PortfolioItemSkill) TableName() string {
+	return "portfolio_item_skills"
+}
+
+// CreatePortfolioItem adds the item after the freelancer's other items
+func CreatePortfolioItem(item *PortfolioItem, skillIDs []int) error {
+	o := orm.NewOrm()
+
+	return o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		var last PortfolioItem
+		err := txOrm.QueryTable(new(PortfolioItem)).Filter("Freelancer__Id", item.Freelancer.Id).
+			OrderBy("-position").Limit(1).One(&last, "Position")
+		if err != nil && err != orm.ErrNoRows {
+			return err
+		}
+		item.Position = last.Position + 1
+
+		if _, err := txOrm.Insert(item); err != nil {
+			return err
+		}
+
+		return setPortfolioItemSkills(txOrm, item.Id, skillIDs)
+	})
+}
+
+func GetPortfolioItemByID(itemID int) (*PortfolioItem, error) {
+	o := orm.NewOrm()
+	item := PortfolioItem{Id: itemID}
+
+	err := o.Read(&item)
+	if err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// GetPortfolioItemsByFreelancerID returns the freelancer's items, featured first and then in their order
+func GetPortfolioItemsByFreelancerID(freelancerID int) ([]PortfolioItem, error) {
+	o := orm.NewOrm()
+	var items []PortfolioItem
+
+	_, err := o.QueryTable(new(PortfolioItem)).Filter("Freelancer__Id", freelancerID).
+		OrderBy("-featured", "position", "id").Limit(-1).All(&items)
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// CountFeaturedPortfolioItems returns how many of the freelancer's items are featured, leaving out excludeItemID
+func CountFeaturedPortfolioItems(freelancerID, excludeItemID int) (int64, error) {
+	o := orm.NewOrm()
+
+	return o.QueryTable(new(PortfolioItem)).Filter("Freelancer__Id", freelancerID).Filter("Featured", true).
+		Exclude("Id", excludeItemID).Count()
+}
+
+func UpdatePortfolioItem(item *PortfolioItem, skillIDs []int) error {
+	o := orm.NewOrm()
+
+	return o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		_, err := txOrm.Update(item, "Title", "Description", "Role", "ProjectURL", "StartDate", "EndDate", "Featured", "UpdatedAt")
+		if err != nil {
+			return err
+		}
+
+		return setPortfolioItemSkills(txOrm, item.Id, skillIDs)
+	})
+}
+
+// ReorderPortfolioItems sets the order of the freelancer's items to the order of itemIDs,
+// which must list every item of the freelancer once
+func ReorderPortfolioItems(freelancerID int, itemIDs []int) error {
+	o := orm.NewOrm()
+
+	return o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		var items []PortfolioItem
+		_, err := txOrm.QueryTable(new(PortfolioItem)).Filter("Freelancer__Id", freelancerID).ForUpdate().Limit(-1).All(&items, "Id")
+		if err != nil {
+			return err
+		}
+
+		owned := make(map[int]bool, len(items))
+		for _, item := range items {
+			owned[item.Id] = true
+		}
+		if len(itemIDs) != len(items) {
+			return errors.New("portfolio order mismatch")
+		}
+		for _, itemID := range itemIDs {
+			if !owned[itemID] {
+				return errors.New("portfolio order mismatch")
+			}
+			delete(owned, itemID)
+		}
+
+		for i, itemID := range itemIDs {
+			_, err := txOrm.QueryTable(new(PortfolioItem)).Filter("Id", itemID).Update(orm.Params{"Position": i + 1})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func DeletePortfolioItemByID(itemID int) error {
+	o := orm.NewOrm()
+
+	_, err := o.Delete(&PortfolioItem{Id: itemID})
+	return err
+}
+
+// GetPortfolioItemSkills returns the skills of the item by name
+func GetPortfolioItemSkills(itemID int) ([]PortfolioItemSkill, error) {
+	o := orm.NewOrm()
+	var itemSkills []PortfolioItemSkill
+
+	_, err := o.QueryTable(new(PortfolioItemSkill)).Filter("PortfolioItem__Id", itemID).RelatedSel("Skill").
+		OrderBy("Skill__Name").Limit(-1).All(&itemSkills)
+	if err != nil {
+		return nil, err
+	}
+
+	return itemSkills, nil
+}
+
+// setPortfolioItemSkills replaces the skills of the item, skipping skills that do not exist
+func setPortfolioItemSkills(o orm.QueryExecutor, itemID int, skillIDs []int) error {
+	_, err := o.QueryTable(new(PortfolioItemSkill)).Filter("PortfolioItem__Id", itemID).Delete()
+	if err != nil {
+		return err
+	}
+
+	added := make(map[int]bool)
+	for _, skillID := range skillIDs {
+		if added[skillID] || !o.QueryTable(new(Skill)).Filter("Id", skillID).Exist() {
+			continue
+		}
+		added[skillID] = true
+
+		itemSkill := PortfolioItemSkill{
+			PortfolioItem: &PortfolioItem{Id: itemID},
+			Skill:         &Skill{Id: skillID},
+		}
+		if _, err := o.Insert(&itemSkill); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
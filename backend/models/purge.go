@@ -0,0 +1,64 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// PurgeDeleted permanently deletes users, jobs and applications soft-deleted before the cutoff, with the
+// portfolios of the purged freelancers.
+// It returns the storage keys of the attachment, thumbnail and upload files that belonged to the purged rows, the FK
+// cascades only remove the rows so the caller has to remove the files.
+func PurgeDeleted(cutoff time.Time) ([]string, error) {
+	o := orm.NewOrm()
+	var filePaths []string
+
+	err := o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		var paths orm.ParamsList
+		_, err := txOrm.Raw(`SELECT unnest(ARRAY[a.file_path, NULLIF(a.thumbnail_path, '')]) FROM attachments a
+			LEFT JOIN applications ap ON ap.id = a.application_id
+			JOIN jobs j ON j.id = COALESCE(ap.job_id, a.job_id)
+			LEFT JOIN users applicant ON applicant.id = ap.user_id
+			JOIN users client ON client.id = j.client_id
+			WHERE ap.deleted_at < ? OR j.deleted_at < ? OR applicant.deleted_at < ?
+			OR client.deleted_at < ?
+			UNION ALL
+			SELECT unnest(ARRAY[a.file_path, NULLIF(a.thumbnail_path, '')]) FROM attachments a
+			JOIN portfolio_items p ON p.id = a.portfolio_item_id
+			JOIN users freelancer ON freelancer.id = p.freelancer_id
+			WHERE freelancer.deleted_at < ?
+			UNION ALL
+			SELECT u.file_path FROM uploads u
+			JOIN users owner ON owner.id = u.user_id
+			WHERE owner.deleted_at < ?`,
+			cutoff, cutoff, cutoff, cutoff, cutoff, cutoff).ValuesFlat(&paths)
+		if err != nil {
+			return err
+		}
+
+		for _, path := range paths {
+			if filePath, ok := path.(string); ok {
+				filePaths = append(filePaths, filePath)
+			}
+		}
+
+		if _, err := txOrm.QueryTable(new(Application)).Filter("DeletedAt__lt", cutoff).Delete(); err != nil {
+			return err
+		}
+		if _, err := txOrm.QueryTable(new(Job)).Filter("DeletedAt__lt", cutoff).Delete(); err != nil {
+			return err
+		}
+		if _, err := txOrm.QueryTable(new(User)).Filter("DeletedAt__lt", cutoff).Delete(); err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return filePaths, nil
+}
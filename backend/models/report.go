@@ -0,0 +1,159 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+)
+
+type Report struct {
+	Id          int        `orm:"pk;auto"`
+	Reporter    *User      `orm:"rel(fk);on_delete(cascade)"`
+	TargetType  string     `orm:"size(30)"` // job, application, user
+	TargetId    int        // id of the reported job, application or user
+	Category    string     `orm:"size(30)"` // scam, spam, abuse, fake-profile, inappropriate, other
+	Description string     `orm:"type(text);null"`
+	Status      string     `orm:"size(30);default(open)"` // open, resolved, dismissed
+	Action      string     `orm:"size(30);null"`          // action taken on resolve: none, remove-job, remove-application, ban-user
+	Resolution  string     `orm:"type(text);null"`
+	ResolvedBy  *User      `orm:"rel(fk);on_delete(set_null);null"`
+	ResolvedAt  *time.Time `orm:"type(timestamp);null"`
+	CreatedAt   time.Time  `orm:"auto_now_add;type(timestamp)"`
+}
+
+func init() {
+	orm.RegisterModel(new(Report))
+}
+
+func (r *Report) TableName() string {
+	return "reports"
+}
+
+func CreateReport(reporterID int, targetType string, targetID int, category, description string) (int, error) {
+	o := orm.NewOrm()
+
+	exists := o.QueryTable(new(Report)).
+		Filter("Reporter__Id", reporterID).
+		Filter("TargetType", targetType).
+		Filter("TargetId", targetID).
+		Filter("Status", "open").Exist()
+	if exists {
+		return 0, errors.New("report already open")
+	}
+
+	report := Report{
+		Reporter:    &User{Id: reporterID},
+		TargetType:  targetType,
+		TargetId:    targetID,
+		Category:    category,
+		Description: description,
+		Status:      "open",
+		CreatedAt:   time.Now(),
+	}
+
+	id, err := o.Insert(&report)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+func GetReportByID(reportID int) (*Report, error) {
+	o := orm.NewOrm()
+	report := Report{Id: reportID}
+
+	err := o.Read(&report)
+	if err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+func GetReportsByReporterID(reporterID int) ([]Report, error) {
+	o := orm.NewOrm()
+	var reports []Report
+
+	_, err := o.QueryTable(new(Report)).Filter("Reporter__Id", reporterID).OrderBy("-id").All(&reports)
+	if err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+// GetReports returns reports for the moderation queue, oldest first. Empty filters are ignored.
+func GetReports(status, targetType, category string) ([]Report, error) {
+	o := orm.NewOrm()
+	var reports []Report
+
+	qs := o.QueryTable(new(Report))
+	if status != "" {
+		qs = qs.Filter("Status", status)
+	}
+	if targetType != "" {
+		qs = qs.Filter("TargetType", targetType)
+	}
+	if category != "" {
+		qs = qs.Filter("Category", category)
+	}
+
+	_, err := qs.OrderBy("id").All(&reports)
+	if err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+func GetOpenReportCountForTarget(targetType string, targetID int) (int, error) {
+	o := orm.NewOrm()
+
+	count, err := o.QueryTable(new(Report)).
+		Filter("TargetType", targetType).
+		Filter("TargetId", targetID).
+		Filter("Status", "open").Count()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// CloseReport sets the final status of the report. Resolving a report also resolves
+// the other open reports on the same target, since the action taken covers them too.
+func CloseReport(report *Report, adminID int, status, action, resolution string) error {
+	o := orm.NewOrm()
+	now := time.Now()
+
+	report.Status = status
+	report.Action = action
+	report.Resolution = resolution
+	report.ResolvedBy = &User{Id: adminID}
+	report.ResolvedAt = &now
+
+	_, err := o.Update(report, "Status", "Action", "Resolution", "ResolvedBy", "ResolvedAt")
+	if err != nil {
+		return err
+	}
+
+	if status != "resolved" {
+		return nil
+	}
+
+	_, err = o.QueryTable(new(Report)).
+		Filter("TargetType", report.TargetType).
+		Filter("TargetId", report.TargetId).
+		Filter("Status", "open").
+		Update(orm.Params{
+			"status":         status,
+			"action":         action,
+			"resolution":     resolution,
+			"resolved_by_id": adminID,
+			"resolved_at":    now,
+		})
+
+	return err
+}
@@ -0,0 +1,332 @@
+package models
+
+import (
+	"backend/types"
+	"context"
+	"strings"
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// SavedSearch is a freelancer's job search that sends alerts when new jobs match it
+type SavedSearch struct {
+	Id         int        `orm:"pk;auto"`
+	User       *User      `orm:"rel(fk);on_delete(cascade)"`
+	Name       string     `orm:"size(50)"`
+	Skills     []*Skill   `orm:"rel(m2m);rel_table(saved_search_skills);on_delete(cascade)"` // any of the skills, none for all jobs
+	Rate       string     `orm:"size(30);null"`                                              // hourly, fixed, empty for both
+	MinAmount  int        `orm:"default(0)"`                                                 // 0 for no lower bound
+	MaxAmount  int        `orm:"default(0)"`                                                 // 0 for no upper bound
+	Keywords   string     `orm:"size(255);null"`                                             // every word must appear in the title or description
+	Frequency  string     `orm:"size(20);default(daily)"`                                    // instant, daily, weekly
+	Channel    string     `orm:"size(20);default(in-app)"`                                   // in-app, email
+	LastSentAt *time.Time `orm:"type(timestamp);null"`
+	CreatedAt  time.Time  `orm:"auto_now_add;type(timestamp)"`
+}
+
+// SavedSearchMatch is a job found for a saved search, kept until it is delivered
+type SavedSearchMatch struct {
+	Id          int          `orm:"pk;auto"`
+	SavedSearch *SavedSearch `orm:"rel(fk);on_delete(cascade)"`
+	Job         *Job         `orm:"rel(fk);on_delete(cascade)"`
+	SentAt      *time.Time   `orm:"type(timestamp);null"`
+	CreatedAt   time.Time    `orm:"auto_now_add;type(timestamp)"`
+}
+
+// PendingJobAlert queues a newly created job to be matched against the saved searches
+type PendingJobAlert struct {
+	Id        int       `orm:"pk;auto"`
+	Job       *Job      `orm:"rel(fk);on_delete(cascade);unique"`
+	CreatedAt time.Time `orm:"auto_now_add;type(timestamp)"`
+}
+
+func (s *SavedSearchMatch) TableUnique() [][]string {
+	return [][]string{
+		{"SavedSearch", "Job"},
+	}
+}
+
+func init() {
+	orm.RegisterModel(new(SavedSearch), new(SavedSearchMatch), new(PendingJobAlert))
+}
+
+func (s *SavedSearch) TableName() string {
+	return "saved_searches"
+}
+
+func (s *SavedSearchMatch) TableName() string {
+	return "saved_search_matches"
+}
+
+func (p *PendingJobAlert) TableName() string {
+	return "pending_job_alerts"
+}
+
+func (s *SavedSearch) SearchFilter() *types.JobSearchFilter {
+	filter := &types.JobSearchFilter{
+		Rate:      s.Rate,
+		MinAmount: s.MinAmount,
+		MaxAmount: s.MaxAmount,
+		Keywords:  s.Keywords,
+	}
+	for _, skill := range s.Skills {
+		filter.SkillIDs = append(filter.SkillIDs, skill.Id)
+	}
+	return filter
+}
+
+// JobMatchesSearch checks a job and the IDs of its skills against a job search
+func JobMatchesSearch(job *Job, jobSkillIDs []int, filter *types.JobSearchFilter) bool {
+	if filter.Rate != "" && job.Rate != filter.Rate {
+		return false
+	}
+	if filter.MinAmount > 0 && job.Amount < filter.MinAmount {
+		return false
+	}
+	if filter.MaxAmount > 0 && job.Amount > filter.MaxAmount {
+		return false
+	}
+
+	if len(filter.SkillIDs) > 0 {
+		hasSkill := false
+		for _, skillID := range filter.SkillIDs {
+			for _, jobSkillID := range jobSkillIDs {
+				if skillID == jobSkillID {
+					hasSkill = true
+				}
+			}
+		}
+		if !hasSkill {
+			return false
+		}
+	}
+
+	text := strings.ToLower(job.Title + " " + job.Description)
+	for _, keyword := range strings.Fields(strings.ToLower(filter.Keywords)) {
+		if !strings.Contains(text, keyword) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func CreateSavedSearch(savedSearch *SavedSearch, skillIDs []int) (int, error) {
+	o := orm.NewOrm()
+
+	err := o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		if _, err := txOrm.Insert(savedSearch); err != nil {
+			return err
+		}
+		return setSavedSearchSkills(txOrm, savedSearch, skillIDs)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return savedSearch.Id, nil
+}
+
+func UpdateSavedSearch(savedSearch *SavedSearch, skillIDs []int) error {
+	o := orm.NewOrm()
+
+	return o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		if _, err := txOrm.Update(savedSearch, "Name", "Rate", "MinAmount", "MaxAmount", "Keywords", "Frequency", "Channel"); err != nil {
+			return err
+		}
+		return setSavedSearchSkills(txOrm, savedSearch, skillIDs)
+	})
+}
+
+// setSavedSearchSkills replaces the skills of the saved search, skipping skills that do not exist
+func setSavedSearchSkills(txOrm orm.TxOrmer, savedSearch *SavedSearch, skillIDs []int) error {
+	m2m := txOrm.QueryM2M(savedSearch, "Skills")
+	if _, err := m2m.Clear(); err != nil {
+		return err
+	}
+
+	if len(skillIDs) == 0 {
+		return nil
+	}
+
+	var skills []*Skill
+	if _, err := txOrm.QueryTable(new(Skill)).Filter("Id__in", skillIDs).All(&skills); err != nil {
+		return err
+	}
+	if len(skills) == 0 {
+		return nil
+	}
+
+	_, err := m2m.Add(skills)
+	return err
+}
+
+func GetSavedSearchesByUserID(userID int) ([]SavedSearch, error) {
+	o := orm.NewOrm()
+	var savedSearches []SavedSearch
+
+	_, err := o.QueryTable(new(SavedSearch)).Filter("User__Id", userID).OrderBy("id").All(&savedSearches)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range savedSearches {
+		if _, err := o.LoadRelated(&savedSearches[i], "Skills"); err != nil {
+			return nil, err
+		}
+	}
+
+	return savedSearches, nil
+}
+
+func GetSavedSearchByID(savedSearchID int) (*SavedSearch, error) {
+	o := orm.NewOrm()
+	savedSearch := SavedSearch{Id: savedSearchID}
+
+	err := o.Read(&savedSearch)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = o.LoadRelated(&savedSearch, "Skills")
+	if err != nil {
+		return nil, err
+	}
+
+	return &savedSearch, nil
+}
+
+func DeleteSavedSearchByID(savedSearchID int) error {
+	o := orm.NewOrm()
+
+	_, err := o.Delete(&SavedSearch{Id: savedSearchID})
+	return err
+}
+
+// QueueJobAlerts queues a new job for the alert task.
+// Failing to queue only means no alerts are sent for the job.
+func QueueJobAlerts(jobID int) {
+	o := orm.NewOrm()
+	o.Raw(`INSERT INTO pending_job_alerts (job_id, created_at) VALUES (?, ?) ON CONFLICT (job_id) DO NOTHING`, jobID, time.Now()).Exec()
+}
+
+// ProcessPendingJobAlerts matches up to limit queued jobs against the saved searches
+// of active freelancers and records the matches for delivery. It returns the number of matches.
+func ProcessPendingJobAlerts(limit int) (int, error) {
+	o := orm.NewOrm()
+	var pendingAlerts []PendingJobAlert
+
+	_, err := o.QueryTable(new(PendingJobAlert)).OrderBy("id").Limit(limit).All(&pendingAlerts)
+	if err != nil || len(pendingAlerts) == 0 {
+		return 0, err
+	}
+
+	var savedSearches []SavedSearch
+	_, err = o.QueryTable(new(SavedSearch)).Filter("User__DeletedAt__isnull", true).Filter("User__Ban", false).
+		RelatedSel("User").Limit(-1).All(&savedSearches)
+	if err != nil {
+		return 0, err
+	}
+	for i := range savedSearches {
+		if _, err := o.LoadRelated(&savedSearches[i], "Skills"); err != nil {
+			return 0, err
+		}
+	}
+
+	// Viewers of the saved search owners, built when a restricted job needs them
+	viewers := make(map[int]*JobViewer)
+
+	matches := 0
+	for _, pendingAlert := range pendingAlerts {
+		if _, err := o.Delete(&pendingAlert); err != nil {
+			return matches, err
+		}
+
+		job, err := GetJobByID(pendingAlert.Job.Id)
+		if err == orm.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return matches, err
+		}
+		// Invite-only jobs are not announced, the invited freelancers already know about them
+		if job.Status != "open" || job.Visibility == "invite-only" {
+			continue
+		}
+
+		jobSkillIDs := make([]int, 0, len(job.Skills))
+		for _, skill := range job.Skills {
+			jobSkillIDs = append(jobSkillIDs, skill.Id)
+		}
+
+		for i := range savedSearches {
+			if !JobMatchesSearch(job, jobSkillIDs, savedSearches[i].SearchFilter()) {
+				continue
+			}
+
+			if job.Visibility == "restricted" {
+				user := savedSearches[i].User
+				if viewers[user.Id] == nil {
+					viewer, err := NewJobViewer(user)
+					if err != nil {
+						return matches, err
+					}
+					viewers[user.Id] = viewer
+				}
+				if !viewers[user.Id].CanView(job) {
+					continue
+				}
+			}
+
+			_, err := o.Raw(`INSERT INTO saved_search_matches (saved_search_id, job_id, created_at) VALUES (?, ?, ?)
+				ON CONFLICT (saved_search_id, job_id) DO NOTHING`, savedSearches[i].Id, job.Id, time.Now()).Exec()
+			if err != nil {
+				return matches, err
+			}
+			matches++
+		}
+	}
+
+	return matches, nil
+}
+
+// GetUndeliveredSavedSearchMatches returns the undelivered matches of open jobs that are not invite-only for saved searches
+// with the frequency that are due, meaning their last delivery was before the cutoff.
+func GetUndeliveredSavedSearchMatches(frequency string, cutoff time.Time) ([]SavedSearchMatch, error) {
+	o := orm.NewOrm()
+	var matches []SavedSearchMatch
+
+	due := orm.NewCondition().Or("SavedSearch__LastSentAt__isnull", true).Or("SavedSearch__LastSentAt__lt", cutoff)
+	cond := orm.NewCondition().AndCond(due).
+		And("SentAt__isnull", true).
+		And("SavedSearch__Frequency", frequency).
+		And("Job__Status", "open").
+		And("Job__Visibility__in", "public", "restricted").
+		And("Job__DeletedAt__isnull", true)
+
+	_, err := o.QueryTable(new(SavedSearchMatch)).SetCond(cond).RelatedSel("SavedSearch__User", "Job").
+		OrderBy("saved_search_id", "id").Limit(-1).All(&matches)
+	if err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+// MarkSavedSearchMatchesDelivered marks the matches as sent and records the delivery on the saved search
+func MarkSavedSearchMatchesDelivered(savedSearchID int, matchIDs []int) error {
+	o := orm.NewOrm()
+
+	return o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		now := time.Now()
+
+		_, err := txOrm.QueryTable(new(SavedSearchMatch)).Filter("Id__in", matchIDs).Update(orm.Params{"sent_at": now})
+		if err != nil {
+			return err
+		}
+
+		_, err = txOrm.QueryTable(new(SavedSearch)).Filter("Id", savedSearchID).Update(orm.Params{"last_sent_at": now})
+		return err
+	})
+}
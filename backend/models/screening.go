@@ -0,0 +1,146 @@
+package models
+
+import (
+	"backend/types"
+	"context"
+	"encoding/json"
+
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// ScreeningQuestion is a question freelancers answer when they apply to a job
+type ScreeningQuestion struct {
+	Id       int    `orm:"pk;auto"`
+	Job      *Job   `orm:"rel(fk);on_delete(cascade)"`
+	Position int    // order of the question on the job
+	Type     string `orm:"size(20)"` // text, yes-no, multiple-choice, number
+	Question string `orm:"size(255)"`
+	Options  string `orm:"type(text);null"` // JSON list of the choices of multiple-choice questions
+	Required bool   `orm:"default(false)"`
+}
+
+// ScreeningAnswer is a freelancer's answer to a screening question, yes-no answers are "yes" or "no"
+// and number answers are stored as they were given
+type ScreeningAnswer struct {
+	Id          int                `orm:"pk;auto"`
+	Application *Application       `orm:"rel(fk);on_delete(cascade)"`
+	Question    *ScreeningQuestion `orm:"rel(fk);on_delete(cascade)"`
+	Answer      string             `orm:"type(text)"`
+}
+
+func (a *ScreeningAnswer) TableUnique() [][]string {
+	return [][]string{
+		{"Application", "Question"},
+	}
+}
+
+func init() {
+	orm.RegisterModel(new(ScreeningQuestion), new(ScreeningAnswer))
+}
+
+func (q *ScreeningQuestion) TableName() string {
+	return "screening_questions"
+}
+
+func (a *ScreeningAnswer) TableName() string {
+	return "screening_answers"
+}
+
+// OptionList returns the choices of a multiple-choice question
+func (q *ScreeningQuestion) OptionList() []string {
+	var options []string
+	if q.Options != "" {
+		json.Unmarshal([]byte(q.Options), &options)
+	}
+	return options
+}
+
+// GetScreeningQuestions returns the questions of the job in their order
+func GetScreeningQuestions(jobID int) ([]ScreeningQuestion, error) {
+	o := orm.NewOrm()
+	var questions []ScreeningQuestion
+
+	_, err := o.QueryTable(new(ScreeningQuestion)).Filter("Job__Id", jobID).OrderBy("position", "id").Limit(-1).All(&questions)
+	if err != nil {
+		return nil, err
+	}
+
+	return questions, nil
+}
+
+// SetScreeningQuestions replaces the questions of the job
+func SetScreeningQuestions(jobID int, questions []types.ScreeningQuestion) error {
+	o := orm.NewOrm()
+
+	return o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		return setScreeningQuestions(txOrm, jobID, questions)
+	})
+}
+
+func setScreeningQuestions(o orm.QueryExecutor, jobID int, questions []types.ScreeningQuestion) error {
+	if _, err := o.QueryTable(new(ScreeningQuestion)).Filter("Job__Id", jobID).Delete(); err != nil {
+		return err
+	}
+
+	for i, question := range questions {
+		screeningQuestion := ScreeningQuestion{
+			Job:      &Job{Id: jobID},
+			Position: i + 1,
+			Type:     question.Type,
+			Question: question.Question,
+			Required: question.Required,
+		}
+		if len(question.Options) > 0 {
+			options, err := json.Marshal(question.Options)
+			if err != nil {
+				return err
+			}
+			screeningQuestion.Options = string(options)
+		}
+
+		if _, err := o.Insert(&screeningQuestion); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func createScreeningAnswers(o orm.QueryExecutor, applicationID int, answers []types.ScreeningAnswer) error {
+	for _, answer := range answers {
+		if answer.Answer == "" {
+			continue
+		}
+
+		screeningAnswer := ScreeningAnswer{
+			Application: &Application{Id: applicationID},
+			Question:    &ScreeningQuestion{Id: answer.QuestionID},
+			Answer:      answer.Answer,
+		}
+		if _, err := o.Insert(&screeningAnswer); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetScreeningAnswersByJobID returns the answers to the job's questions by application ID,
+// in the order of the questions
+func GetScreeningAnswersByJobID(jobID int) (map[int][]ScreeningAnswer, error) {
+	o := orm.NewOrm()
+	var answers []ScreeningAnswer
+
+	_, err := o.QueryTable(new(ScreeningAnswer)).Filter("Question__Job__Id", jobID).RelatedSel("Question").
+		OrderBy("Question__Position", "Question__Id").Limit(-1).All(&answers)
+	if err != nil {
+		return nil, err
+	}
+
+	answersByApplication := make(map[int][]ScreeningAnswer)
+	for _, answer := range answers {
+		answersByApplication[answer.Application.Id] = append(answersByApplication[answer.Application.Id], answer)
+	}
+
+	return answersByApplication, nil
+}
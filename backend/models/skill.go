@@ -1,10 +1,17 @@
 package models
 
-import "github.com/beego/beego/v2/client/orm"
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/beego/beego/v2/client/orm"
+)
 
 type Skill struct {
-	Id   int    `orm:"pk;auto"`
-	Name string `orm:"size(50);unique"`
+	Id       int            `orm:"pk;auto"`
+	Name     string         `orm:"size(50);unique"`
+	Category *SkillCategory `orm:"rel(fk);on_delete(set_null);null"`
 }
 
 func init() {
@@ -56,11 +63,14 @@ func UpdateSkill(skill *Skill) error {
 	return nil
 }
 
-func CreateSkill(name string) error {
+func CreateSkill(name string, categoryID int) error {
 	o := orm.NewOrm()
 	skill := Skill{
 		Name: name,
 	}
+	if categoryID != 0 {
+		skill.Category = &SkillCategory{Id: categoryID}
+	}
 
 	_, err := o.Insert(&skill)
 	if err != nil {
@@ -69,3 +79,145 @@ func CreateSkill(name string) error {
 
 	return nil
 }
+
+// IsSkillNameTaken checks skill names and aliases case-insensitively, so "ReactJS" cannot
+// become a skill while it is an alias of "React". The skill with excludeSkillID is ignored.
+func IsSkillNameTaken(name string, excludeSkillID int) bool {
+	o := orm.NewOrm()
+	name = strings.TrimSpace(name)
+
+	if o.QueryTable(new(Skill)).Filter("Name__iexact", name).Exclude("Id", excludeSkillID).Exist() {
+		return true
+	}
+
+	return o.QueryTable(new(SkillAlias)).Filter("Name__iexact", name).Exist()
+}
+
+// ResolveSkillByName returns the canonical skill for a skill name or any of its aliases
+func ResolveSkillByName(name string) (*Skill, error) {
+	o := orm.NewOrm()
+	name = strings.TrimSpace(name)
+
+	var skill Skill
+	err := o.QueryTable(new(Skill)).Filter("Name__iexact", name).One(&skill)
+	if err == nil {
+		return &skill, nil
+	}
+	if err != orm.ErrNoRows {
+		return nil, err
+	}
+
+	var alias SkillAlias
+	err = o.QueryTable(new(SkillAlias)).Filter("Name__iexact", name).One(&alias)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetSkillById(alias.Skill.Id)
+}
+
+// SearchSkills returns skills whose name or alias contains the query, prefix matches first
+func SearchSkills(query string, limit int) ([]Skill, error) {
+	o := orm.NewOrm()
+	var skills []Skill
+
+	query = strings.TrimSpace(query)
+	_, err := o.Raw(`SELECT s.id, s.name, s.category_id FROM skills s
+		LEFT JOIN skill_aliases sa ON sa.skill_id = s.id
+		WHERE s.name ILIKE '%' || ? || '%' OR sa.name ILIKE '%' || ? || '%'
+		GROUP BY s.id, s.name, s.category_id
+		ORDER BY MAX(CASE WHEN s.name ILIKE ? || '%' OR sa.name ILIKE ? || '%' THEN 0 ELSE 1 END), s.name
+		LIMIT ?`, query, query, query, query, limit).QueryRows(&skills)
+	if err != nil {
+		return nil, err
+	}
+
+	return skills, nil
+}
+
+// MergeSkills moves every freelancer, job, portfolio item and saved search from the source skill to the target skill,
+// keeps the source name as an alias of the target and deletes the source skill.
+func MergeSkills(sourceID, targetID int) error {
+	if sourceID == targetID {
+		return errors.New("cannot merge a skill into itself")
+	}
+
+	o := orm.NewOrm()
+
+	source, err := GetSkillById(sourceID)
+	if err != nil {
+		return err
+	}
+	if _, err := GetSkillById(targetID); err != nil {
+		return err
+	}
+
+	return o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		// Link the target skill where the source was used, unless it is already linked
+		_, err := txOrm.Raw(`INSERT INTO freelancer_skills (freelancer_data_id, skills_id, level, years_of_experience)
+			SELECT fs.freelancer_data_id, ?, fs.level, fs.years_of_experience FROM freelancer_skills fs
+			WHERE fs.skills_id = ? AND NOT EXISTS (
+				SELECT 1 FROM freelancer_skills t WHERE t.freelancer_data_id = fs.freelancer_data_id AND t.skills_id = ?
+			)`, targetID, sourceID, targetID).Exec()
+		if err != nil {
+			return err
+		}
+
+		_, err = txOrm.Raw(`INSERT INTO job_skills (jobs_id, skills_id, required, min_level)
+			SELECT js.jobs_id, ?, js.required, js.min_level FROM job_skills js
+			WHERE js.skills_id = ? AND NOT EXISTS (
+				SELECT 1 FROM job_skills t WHERE t.jobs_id = js.jobs_id AND t.skills_id = ?
+			)`, targetID, sourceID, targetID).Exec()
+		if err != nil {
+			return err
+		}
+
+		_, err = txOrm.Raw(`INSERT INTO portfolio_item_skills (portfolio_item_id, skill_id)
+			SELECT ps.portfolio_item_id, ? FROM portfolio_item_skills ps
+			WHERE ps.skill_id = ? AND NOT EXISTS (
+				SELECT 1 FROM portfolio_item_skills t WHERE t.portfolio_item_id = ps.portfolio_item_id AND t.skill_id = ?
+			)`, targetID, sourceID, targetID).Exec()
+		if err != nil {
+			return err
+		}
+
+		_, err = txOrm.Raw(`INSERT INTO saved_search_skills (saved_searches_id, skills_id)
+			SELECT ss.saved_searches_id, ? FROM saved_search_skills ss
+			WHERE ss.skills_id = ? AND NOT EXISTS (
+				SELECT 1 FROM saved_search_skills t WHERE t.saved_searches_id = ss.saved_searches_id AND t.skills_id = ?
+			)`, targetID, sourceID, targetID).Exec()
+		if err != nil {
+			return err
+		}
+
+		if _, err := txOrm.Raw(`DELETE FROM freelancer_skills WHERE skills_id = ?`, sourceID).Exec(); err != nil {
+			return err
+		}
+		if _, err := txOrm.Raw(`DELETE FROM job_skills WHERE skills_id = ?`, sourceID).Exec(); err != nil {
+			return err
+		}
+		if _, err := txOrm.Raw(`DELETE FROM portfolio_item_skills WHERE skill_id = ?`, sourceID).Exec(); err != nil {
+			return err
+		}
+		if _, err := txOrm.Raw(`DELETE FROM saved_search_skills WHERE skills_id = ?`, sourceID).Exec(); err != nil {
+			return err
+		}
+
+		_, err = txOrm.QueryTable(new(Job)).Filter("RestrictedSkill__Id", sourceID).Update(orm.Params{"restricted_skill_id": targetID})
+		if err != nil {
+			return err
+		}
+
+		_, err = txOrm.QueryTable(new(SkillAlias)).Filter("Skill__Id", sourceID).Update(orm.Params{"skill_id": targetID})
+		if err != nil {
+			return err
+		}
+
+		if _, err := txOrm.Delete(&Skill{Id: sourceID}); err != nil {
+			return err
+		}
+
+		_, err = txOrm.Insert(&SkillAlias{Skill: &Skill{Id: targetID}, Name: source.Name})
+		return err
+	})
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// SkillAlias is an alternative spelling of a skill, such as "ReactJS" for "React"
+type SkillAlias struct {
+	Id    int    `orm:"pk;auto"`
+	Skill *Skill `orm:"rel(fk);on_delete(cascade)"`
+	Name  string `orm:"size(50);unique"`
+}
+
+func init() {
+	orm.RegisterModel(new(SkillAlias))
+}
+
+func (s *SkillAlias) TableName() string {
+	return "skill_aliases"
+}
+
+func GetAliasesBySkillID(skillID int) ([]SkillAlias, error) {
+	o := orm.NewOrm()
+	var aliases []SkillAlias
+	_, err := o.QueryTable(new(SkillAlias)).Filter("Skill__Id", skillID).OrderBy("name").All(&aliases)
+	return aliases, err
+}
+
+func GetAllSkillAliases() ([]SkillAlias, error) {
+	o := orm.NewOrm()
+	var aliases []SkillAlias
+	_, err := o.QueryTable(new(SkillAlias)).OrderBy("name").All(&aliases)
+	return aliases, err
+}
+
+func GetSkillAliasByID(aliasID int) (*SkillAlias, error) {
+	o := orm.NewOrm()
+	alias := SkillAlias{Id: aliasID}
+	err := o.Read(&alias)
+	if err != nil {
+		return nil, err
+	}
+	return &alias, nil
+}
+
+func CreateSkillAlias(skillID int, name string) error {
+	o := orm.NewOrm()
+	alias := SkillAlias{
+		Skill: &Skill{Id: skillID},
+		Name:  strings.TrimSpace(name),
+	}
+
+	_, err := o.Insert(&alias)
+	return err
+}
+
+func DeleteSkillAliasByID(aliasID int) error {
+	o := orm.NewOrm()
+	alias := SkillAlias{Id: aliasID}
+
+	_, err := o.Delete(&alias)
+	return err
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"errors"
+
+	"github.com/beego/beego/v2/client/orm"
+)
+
+type SkillCategory struct {
+	Id     int            `orm:"pk;auto"`
+	Name   string         `orm:"size(50);unique"`
+	Parent *SkillCategory `orm:"rel(fk);on_delete(set_null);null"` // nil for top level categories
+}
+
+func init() {
+	orm.RegisterModel(new(SkillCategory))
+}
+
+func (s *SkillCategory) TableName() string {
+	return "skill_categories"
+}
+
+func GetAllSkillCategories() ([]SkillCategory, error) {
+	o := orm.NewOrm()
+	var categories []SkillCategory
+	_, err := o.QueryTable(new(SkillCategory)).OrderBy("name").All(&categories)
+	return categories, err
+}
+
+func GetSkillCategoryByID(categoryID int) (*SkillCategory, error) {
+	o := orm.NewOrm()
+	category := SkillCategory{Id: categoryID}
+	err := o.Read(&category)
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func CreateSkillCategory(name string, parentID int) (int, error) {
+	o := orm.NewOrm()
+	category := SkillCategory{Name: name}
+	if parentID != 0 {
+		category.Parent = &SkillCategory{Id: parentID}
+	}
+
+	id, err := o.Insert(&category)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+// UpdateSkillCategory rejects parents that would make the category its own ancestor
+func UpdateSkillCategory(category *SkillCategory) error {
+	o := orm.NewOrm()
+
+	if category.Parent != nil {
+		parentID := category.Parent.Id
+		for parentID != 0 {
+			if parentID == category.Id {
+				return errors.New("category cycle")
+			}
+			parent := SkillCategory{Id: parentID}
+			if err := o.Read(&parent); err != nil {
+				return err
+			}
+			parentID = 0
+			if parent.Parent != nil {
+				parentID = parent.Parent.Id
+			}
+		}
+	}
+
+	_, err := o.Update(category)
+	return err
+}
+
+// DeleteSkillCategoryByID leaves the skills and subcategories of the category uncategorised
+func DeleteSkillCategoryByID(categoryID int) error {
+	o := orm.NewOrm()
+	category := SkillCategory{Id: categoryID}
+
+	_, err := o.Delete(&category)
+	return err
+}
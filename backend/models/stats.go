@@ -0,0 +1,195 @@
+package models
+
+import (
+	"backend/types"
+	"fmt"
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// Every series is built on generate_series so buckets without any rows are still returned with zero counts.
+// Deleted rows are left out, like in the snapshot counts.
+// The interval is interpolated into the SQL, callers must only pass values from types.ValidStatsIntervals.
+const bucketSeries = `generate_series(date_trunc('%[1]s', ?::timestamptz), ?::timestamptz, '1 %[1]s'::interval) AS b(bucket)`
+
+func GetRegistrationSeries(interval string, from, to time.Time) ([]types.RegistrationsBucket, error) {
+	o := orm.NewOrm()
+	var buckets []types.RegistrationsBucket
+
+	query := fmt.Sprintf(`SELECT b.bucket,
+			COUNT(u.id) FILTER (WHERE u.role = 'client') AS client,
+			COUNT(u.id) FILTER (WHERE u.role = 'freelancer') AS freelancer,
+			COUNT(u.id) FILTER (WHERE u.role = 'admin') AS admin
+		FROM `+bucketSeries+`
+		LEFT JOIN users u ON date_trunc('%[1]s', u.created_at) = b.bucket AND u.created_at BETWEEN ? AND ? AND u.deleted_at IS NULL
+		GROUP BY b.bucket ORDER BY b.bucket`, interval)
+
+	_, err := o.Raw(query, from, to, from, to).QueryRows(&buckets)
+	if err != nil {
+		return nil, err
+	}
+
+	return buckets, nil
+}
+
+// GetJobSeries counts posted jobs per bucket, split by the current status of those jobs
+func GetJobSeries(interval string, from, to time.Time) ([]types.JobsBucket, error) {
+	o := orm.NewOrm()
+	var buckets []types.JobsBucket
+
+	query := fmt.Sprintf(`SELECT b.bucket,
+			COUNT(j.id) AS total,
+			COUNT(j.id) FILTER (WHERE j.status = 'open') AS open,
+			COUNT(j.id) FILTER (WHERE j.status = 'in-progress') AS in_progress,
+			COUNT(j.id) FILTER (WHERE j.status = 'completed') AS completed
+		FROM `+bucketSeries+`
+		LEFT JOIN jobs j ON date_trunc('%[1]s', j.created_at) = b.bucket AND j.created_at BETWEEN ? AND ? AND j.deleted_at IS NULL
+		GROUP BY b.bucket ORDER BY b.bucket`, interval)
+
+	_, err := o.Raw(query, from, to, from, to).QueryRows(&buckets)
+	if err != nil {
+		return nil, err
+	}
+
+	return buckets, nil
+}
+
+// GetCountSeries counts rows of the table per bucket of the given timestamp column, softDeleted leaves out
+// the deleted rows of tables with a deleted_at column.
+// Table and column are interpolated into the SQL and must not come from user input.
+func GetCountSeries(table, column string, softDeleted bool, interval string, from, to time.Time) ([]types.CountBucket, error) {
+	o := orm.NewOrm()
+	var buckets []types.CountBucket
+
+	notDeleted := ""
+	if softDeleted {
+		notDeleted = " AND t.deleted_at IS NULL"
+	}
+
+	query := fmt.Sprintf(`SELECT b.bucket, COUNT(t.id) AS count
+		FROM `+bucketSeries+`
+		LEFT JOIN %[2]s t ON date_trunc('%[1]s', t.%[3]s) = b.bucket AND t.%[3]s BETWEEN ? AND ?%[4]s
+		GROUP BY b.bucket ORDER BY b.bucket`, interval, table, column, notDeleted)
+
+	_, err := o.Raw(query, from, to, from, to).QueryRows(&buckets)
+	if err != nil {
+		return nil, err
+	}
+
+	return buckets, nil
+}
+
+func GetUserCountsByRole() (map[string]int, error) {
+	o := orm.NewOrm()
+	var rows []struct {
+		Role  string
+		Count int
+	}
+
+	_, err := o.Raw(`SELECT role, COUNT(*) AS count FROM users WHERE deleted_at IS NULL GROUP BY role`).QueryRows(&rows)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{"client": 0, "freelancer": 0, "admin": 0}
+	for _, row := range rows {
+		counts[row.Role] = row.Count
+	}
+
+	return counts, nil
+}
+
+func GetJobCountsByStatus() (map[string]int, error) {
+	o := orm.NewOrm()
+	var rows []struct {
+		Status string
+		Count  int
+	}
+
+	_, err := o.Raw(`SELECT status, COUNT(*) AS count FROM jobs WHERE deleted_at IS NULL GROUP BY status`).QueryRows(&rows)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{"open": 0, "in-progress": 0, "completed": 0}
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+
+	return counts, nil
+}
+
+func GetBannedUserCount() (int, error) {
+	o := orm.NewOrm()
+
+	count, err := o.QueryTable(new(User)).Filter("Ban", true).Filter("DeletedAt__isnull", true).Count()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// GetApplicationOutcomes returns how many applications submitted in the range were accepted and how many were decided
+func GetApplicationOutcomes(from, to time.Time) (accepted int, decided int, err error) {
+	o := orm.NewOrm()
+
+	err = o.Raw(`SELECT
+			COUNT(*) FILTER (WHERE status = 'accepted'),
+			COUNT(*) FILTER (WHERE status IN ('accepted', 'rejected'))
+		FROM applications WHERE created_at BETWEEN ? AND ? AND deleted_at IS NULL`, from, to).QueryRow(&accepted, &decided)
+
+	return accepted, decided, err
+}
+
+// GetMedianTimeToHire returns the median hours between a job being posted and an application being accepted,
+// for hires made in the range. The hire count is returned so callers can tell an empty range from a zero median.
+func GetMedianTimeToHire(from, to time.Time) (hours float64, hires int, err error) {
+	o := orm.NewOrm()
+
+	err = o.Raw(`SELECT
+			COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM (a.accepted_at - j.created_at)) / 3600), 0),
+			COUNT(*)
+		FROM applications a JOIN jobs j ON j.id = a.job_id
+		WHERE a.accepted_at BETWEEN ? AND ? AND a.deleted_at IS NULL AND j.deleted_at IS NULL`, from, to).QueryRow(&hours, &hires)
+
+	return hours, hires, err
+}
+
+// GetTopDemandedSkills ranks skills by how many jobs posted in the range require them
+func GetTopDemandedSkills(from, to time.Time, limit int) ([]types.SkillCount, error) {
+	o := orm.NewOrm()
+	var skills []types.SkillCount
+
+	_, err := o.Raw(`SELECT s.id, s.name, COUNT(*) AS count
+		FROM job_skills js
+		JOIN skills s ON s.id = js.skills_id
+		JOIN jobs j ON j.id = js.jobs_id
+		WHERE j.deleted_at IS NULL AND j.created_at BETWEEN ? AND ?
+		GROUP BY s.id, s.name ORDER BY count DESC, s.name LIMIT ?`, from, to, limit).QueryRows(&skills)
+	if err != nil {
+		return nil, err
+	}
+
+	return skills, nil
+}
+
+// GetTopOfferedSkills ranks skills by how many current freelancers list them
+func GetTopOfferedSkills(limit int) ([]types.SkillCount, error) {
+	o := orm.NewOrm()
+	var skills []types.SkillCount
+
+	_, err := o.Raw(`SELECT s.id, s.name, COUNT(*) AS count
+		FROM freelancer_skills fs
+		JOIN skills s ON s.id = fs.skills_id
+		JOIN freelancer_data fd ON fd.id = fs.freelancer_data_id
+		JOIN users u ON u.id = fd.user_id
+		WHERE u.deleted_at IS NULL AND u.role = 'freelancer'
+		GROUP BY s.id, s.name ORDER BY count DESC, s.name LIMIT ?`, limit).QueryRows(&skills)
+	if err != nil {
+		return nil, err
+	}
+
+	return skills, nil
+}
@@ -0,0 +1,181 @@
+package models
+
+import (
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// TalentList is a client's named list of freelancers
+type TalentList struct {
+	Id        int       `orm:"pk;auto"`
+	Client    *User     `orm:"rel(fk);on_delete(cascade)"`
+	Name      string    `orm:"size(50)"`
+	CreatedAt time.Time `orm:"auto_now_add;type(timestamp)"`
+}
+
+// TalentListEntry is a freelancer saved to a talent list
+type TalentListEntry struct {
+	Id         int         `orm:"pk;auto"`
+	TalentList *TalentList `orm:"rel(fk);on_delete(cascade)"`
+	Freelancer *User       `orm:"rel(fk);on_delete(cascade)"`
+	Note       string      `orm:"size(255);null"`
+	CreatedAt  time.Time   `orm:"auto_now_add;type(timestamp)"`
+}
+
+func (e *TalentListEntry) TableUnique() [][]string {
+	return [][]string{
+		{"TalentList", "Freelancer"},
+	}
+}
+
+func init() {
+	orm.RegisterModel(new(TalentList), new(TalentListEntry))
+}
+
+func (t *TalentList) TableName() string {
+	return "talent_lists"
+}
+
+func (e *TalentListEntry) TableName() string {
+	return "talent_list_entries"
+}
+
+func CreateTalentList(clientID int, name string) (int, error) {
+	o := orm.NewOrm()
+
+	talentList := TalentList{
+		Client: &User{Id: clientID},
+		Name:   name,
+	}
+
+	_, err := o.Insert(&talentList)
+	if err != nil {
+		return 0, err
+	}
+
+	return talentList.Id, nil
+}
+
+func GetTalentListByID(talentListID int) (*TalentList, error) {
+	o := orm.NewOrm()
+	talentList := TalentList{Id: talentListID}
+
+	err := o.Read(&talentList)
+	if err != nil {
+		return nil, err
+	}
+
+	return &talentList, nil
+}
+
+func GetTalentListsByClientID(clientID int) ([]TalentList, error) {
+	o := orm.NewOrm()
+	var talentLists []TalentList
+
+	_, err := o.QueryTable(new(TalentList)).Filter("Client__Id", clientID).OrderBy("id").Limit(-1).All(&talentLists)
+	if err != nil {
+		return nil, err
+	}
+
+	return talentLists, nil
+}
+
+func UpdateTalentList(talentList *TalentList) error {
+	o := orm.NewOrm()
+
+	_, err := o.Update(talentList, "Name")
+	return err
+}
+
+func DeleteTalentListByID(talentListID int) error {
+	o := orm.NewOrm()
+
+	_, err := o.Delete(&TalentList{Id: talentListID})
+	return err
+}
+
+// GetTalentListEntries returns the active freelancers saved to the list, in the order they were added
+func GetTalentListEntries(talentListID int) ([]TalentListEntry, error) {
+	o := orm.NewOrm()
+	var entries []TalentListEntry
+
+	_, err := o.QueryTable(new(TalentListEntry)).Filter("TalentList__Id", talentListID).
+		Filter("Freelancer__DeletedAt__isnull", true).RelatedSel("Freelancer").
+		OrderBy("id").Limit(-1).All(&entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// CountTalentListEntries returns the number of active freelancers in each of the client's lists
+func CountTalentListEntries(clientID int) (map[int]int, error) {
+	o := orm.NewOrm()
+	var talentListIDs, counts []int
+
+	_, err := o.Raw(`SELECT e.talent_list_id, COUNT(*) FROM talent_list_entries e
+		JOIN talent_lists l ON l.id = e.talent_list_id
+		JOIN users u ON u.id = e.freelancer_id
+		WHERE l.client_id = ? AND u.deleted_at IS NULL
+		GROUP BY e.talent_list_id`, clientID).QueryRows(&talentListIDs, &counts)
+	if err != nil {
+		return nil, err
+	}
+
+	entryCounts := make(map[int]int, len(talentListIDs))
+	for i, talentListID := range talentListIDs {
+		entryCounts[talentListID] = counts[i]
+	}
+
+	return entryCounts, nil
+}
+
+// GetTalentListIDsByFreelancer returns, for each freelancer in the client's lists, the IDs of the lists they are in
+func GetTalentListIDsByFreelancer(clientID int) (map[int][]int, error) {
+	o := orm.NewOrm()
+	var entries []TalentListEntry
+
+	_, err := o.QueryTable(new(TalentListEntry)).Filter("TalentList__Client__Id", clientID).
+		OrderBy("talent_list_id").Limit(-1).All(&entries, "TalentList", "Freelancer")
+	if err != nil {
+		return nil, err
+	}
+
+	talentListIDs := make(map[int][]int)
+	for _, entry := range entries {
+		talentListIDs[entry.Freelancer.Id] = append(talentListIDs[entry.Freelancer.Id], entry.TalentList.Id)
+	}
+
+	return talentListIDs, nil
+}
+
+func TalentListEntryExists(talentListID, freelancerID int) bool {
+	o := orm.NewOrm()
+	return o.QueryTable(new(TalentListEntry)).Filter("TalentList__Id", talentListID).Filter("Freelancer__Id", freelancerID).Exist()
+}
+
+func AddFreelancerToTalentList(talentListID, freelancerID int, note string) error {
+	o := orm.NewOrm()
+
+	entry := TalentListEntry{
+		TalentList: &TalentList{Id: talentListID},
+		Freelancer: &User{Id: freelancerID},
+		Note:       note,
+	}
+
+	_, err := o.Insert(&entry)
+	return err
+}
+
+func RemoveFreelancerFromTalentList(talentListID, freelancerID int) (bool, error) {
+	o := orm.NewOrm()
+
+	num, err := o.QueryTable(new(TalentListEntry)).Filter("TalentList__Id", talentListID).Filter("Freelancer__Id", freelancerID).Delete()
+	if err != nil {
+		return false, err
+	}
+
+	return num > 0, nil
+}
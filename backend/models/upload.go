@@ -0,0 +1,167 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/beego/beego/v2/client/orm"
+)
+
+// Upload is a resumable upload of a large file. The client sends the file in chunks and continues
+// from the received offset after a broken connection. Each chunk is stored as a part under its own
+// key until the last one arrives and the parts are joined under FilePath. A completed upload is used
+// once, by the attachment that takes over its file.
+type Upload struct {
+	Id        int       `orm:"pk;auto"`
+	User      *User     `orm:"rel(fk);on_delete(cascade)"`
+	FileName  string    `orm:"size(255)"` // Original filename
+	FilePath  string    `orm:"size(255)"` // Storage key with unique name
+	Length    int64     // total size announced by the client
+	Received  int64     `orm:"default(0)"`                  // bytes received so far
+	Chunks    int       `orm:"default(0)"`                  // parts received so far
+	Status    string    `orm:"size(20);default(uploading)"` // uploading, completed
+	CreatedAt time.Time `orm:"auto_now_add;type(timestamp)"`
+	ExpiresAt time.Time `orm:"type(timestamp)"` // unused uploads are removed after this time
+}
+
+func init() {
+	orm.RegisterModel(new(Upload))
+}
+
+func (u *Upload) TableName() string {
+	return "uploads"
+}
+
+// PartKey returns the storage key of the upload's part with the index
+func (u *Upload) PartKey(index int) string {
+	return fmt.Sprintf("%s.part%d", u.FilePath, index)
+}
+
+// PartKeys returns the storage keys of the parts received so far
+func (u *Upload) PartKeys() []string {
+	keys := make([]string, 0, u.Chunks)
+	for i := 0; i < u.Chunks; i++ {
+		keys = append(keys, u.PartKey(i))
+	}
+	return keys
+}
+
+func CreateUpload(userID int, fileName, filePath string, length int64, expiresAt time.Time) (*Upload, error) {
+	o := orm.NewOrm()
+
+	upload := Upload{
+		User:      &User{Id: userID},
+		FileName:  fileName,
+		FilePath:  filePath,
+		Length:    length,
+		Status:    "uploading",
+		ExpiresAt: expiresAt,
+	}
+	if _, err := o.Insert(&upload); err != nil {
+		return nil, err
+	}
+
+	return &upload, nil
+}
+
+// GetUpload returns the user's upload, orm.ErrNoRows when it belongs to someone else
+func GetUpload(uploadID, userID int) (*Upload, error) {
+	o := orm.NewOrm()
+	var upload Upload
+
+	err := o.QueryTable(new(Upload)).Filter("Id", uploadID).Filter("User__Id", userID).One(&upload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &upload, nil
+}
+
+// ReceiveUploadChunk locks the upload, checks that the chunk starts at the received offset and records
+// the part that write stores. Chunks sent at the same time wait for each other.
+func ReceiveUploadChunk(uploadID int, offset int64, write func(upload *Upload) (int64, error)) (*Upload, error) {
+	o := orm.NewOrm()
+	var upload Upload
+
+	err := o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		err := txOrm.QueryTable(new(Upload)).Filter("Id", uploadID).ForUpdate().One(&upload)
+		if err != nil {
+			return err
+		}
+		if upload.Status != "uploading" {
+			return errors.New("upload is completed")
+		}
+		if upload.Received != offset {
+			return errors.New("upload offset mismatch")
+		}
+
+		written, err := write(&upload)
+		if err != nil {
+			return err
+		}
+
+		upload.Received += written
+		upload.Chunks++
+		if upload.Received == upload.Length {
+			upload.Status = "completed"
+		}
+		_, err = txOrm.Update(&upload, "Received", "Chunks", "Status")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &upload, nil
+}
+
+// ClaimUpload removes the completed upload so that its file is used only once, the caller takes over the file
+func ClaimUpload(upload *Upload) error {
+	o := orm.NewOrm()
+
+	num, err := o.QueryTable(new(Upload)).Filter("Id", upload.Id).Filter("Status", "completed").Delete()
+	if err != nil {
+		return err
+	}
+	if num == 0 {
+		return errors.New("upload is already used")
+	}
+
+	return nil
+}
+
+func DeleteUpload(uploadID int) error {
+	o := orm.NewOrm()
+
+	_, err := o.Delete(&Upload{Id: uploadID})
+	return err
+}
+
+// DeleteExpiredUploads removes the uploads that expired before being used and returns them,
+// the caller removes their files and parts
+func DeleteExpiredUploads(now time.Time) ([]Upload, error) {
+	o := orm.NewOrm()
+	var uploads []Upload
+
+	err := o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		_, err := txOrm.QueryTable(new(Upload)).Filter("ExpiresAt__lt", now).Limit(-1).All(&uploads, "Id", "FilePath", "Chunks")
+		if err != nil || len(uploads) == 0 {
+			return err
+		}
+
+		uploadIDs := make([]int, 0, len(uploads))
+		for _, upload := range uploads {
+			uploadIDs = append(uploadIDs, upload.Id)
+		}
+
+		_, err = txOrm.QueryTable(new(Upload)).Filter("Id__in", uploadIDs).Delete()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uploads, nil
+}
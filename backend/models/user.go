@@ -1,20 +1,23 @@
 package models
 
 import (
+	"context"
+	"errors"
 	"time"
 
 	"github.com/beego/beego/v2/client/orm"
 )
 
 type User struct {
-	Id        int       `orm:"pk;auto"`
-	Email     string    `orm:"unique;size(255)"`
-	Password  string    `orm:"size(255)"`
-	Name      string    `orm:"size(30)"`
-	Surname   string    `orm:"size(30)"`
-	CreatedAt time.Time `orm:"auto_now_add;type(timestamp)"`
-	Role      string    `orm:"size(20)"`
-	Ban       bool      `orm:"default(false)"`
+	Id        int        `orm:"pk;auto"`
+	Email     string     `orm:"unique;size(255)"`
+	Password  string     `orm:"size(255)"`
+	Name      string     `orm:"size(30)"`
+	Surname   string     `orm:"size(30)"`
+	CreatedAt time.Time  `orm:"auto_now_add;type(timestamp)"`
+	Role      string     `orm:"size(20)"`
+	Ban       bool       `orm:"default(false)"`
+	DeletedAt *time.Time `orm:"type(timestamp);null"` // soft deletion time, purged after the retention period
 }
 
 func init() {
@@ -66,9 +69,18 @@ func GetUserByEmail(email string) (*User, error) {
 	if err != nil {
 		return nil, err
 	}
+	if user.DeletedAt != nil {
+		return nil, orm.ErrNoRows
+	}
 	return &user, nil
 }
 
+// IsEmailTaken also checks deleted users, their email stays reserved until they are purged
+func IsEmailTaken(email string) bool {
+	o := orm.NewOrm()
+	return o.QueryTable(new(User)).Filter("Email", email).Exist()
+}
+
 func GetUserById(userID int) (*User, error) {
 	o := orm.NewOrm()
 	user := User{Id: userID}
@@ -76,6 +88,9 @@ func GetUserById(userID int) (*User, error) {
 	if err != nil {
 		return nil, err
 	}
+	if user.DeletedAt != nil {
+		return nil, orm.ErrNoRows
+	}
 	return &user, nil
 }
 
@@ -93,49 +108,99 @@ func IsUserBanned(userID int) (bool, error) {
 	return user.Ban, nil // Return the ban status
 }
 
-func BanUserByID(userID int) error {
+func UpdateUser(user *User, cols ...string) error {
 	o := orm.NewOrm()
-	user := User{Id: userID}
 
-	if err := o.Read(&user); err != nil {
+	_, err := o.Update(user, cols...)
+	if err != nil {
 		return err
 	}
 
-	user.Ban = true
-	_, err := o.Update(&user, "Ban")
-	return err
+	return nil
 }
 
-func UpdateUser(user *User) error {
+// DeleteUserByID soft-deletes the user together with their jobs and applications.
+// Everything deleted here shares the same timestamp so RestoreUserByID can bring it back.
+func DeleteUserByID(userID int) error {
 	o := orm.NewOrm()
 
-	_, err := o.Update(user)
-	if err != nil {
+	return o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		now := time.Now()
+
+		num, err := txOrm.QueryTable(new(User)).Filter("Id", userID).Filter("DeletedAt__isnull", true).
+			Update(orm.Params{"deleted_at": now})
+		if err != nil {
+			return err
+		}
+		if num == 0 {
+			return orm.ErrNoRows
+		}
+
+		_, err = txOrm.QueryTable(new(Job)).Filter("Client__Id", userID).Filter("DeletedAt__isnull", true).
+			Update(orm.Params{"deleted_at": now})
+		if err != nil {
+			return err
+		}
+
+		_, err = txOrm.Raw(`UPDATE applications SET deleted_at = ?
+			WHERE deleted_at IS NULL AND (user_id = ? OR job_id IN (SELECT id FROM jobs WHERE client_id = ?))`,
+			now, userID, userID).Exec()
+		return err
+	})
+}
+
+// RestoreUserByID restores a soft-deleted user and the jobs and applications deleted along with them.
+func RestoreUserByID(userID int) error {
+	o := orm.NewOrm()
+
+	user := User{Id: userID}
+	if err := o.Read(&user); err != nil {
 		return err
 	}
+	if user.DeletedAt == nil {
+		return errors.New("user is not deleted")
+	}
 
-	return nil
+	return o.DoTx(func(ctx context.Context, txOrm orm.TxOrmer) error {
+		deletedAt := *user.DeletedAt
+
+		_, err := txOrm.QueryTable(new(User)).Filter("Id", userID).Update(orm.Params{"deleted_at": nil})
+		if err != nil {
+			return err
+		}
+
+		_, err = txOrm.QueryTable(new(Job)).Filter("Client__Id", userID).Filter("DeletedAt", deletedAt).
+			Update(orm.Params{"deleted_at": nil})
+		if err != nil {
+			return err
+		}
+
+		_, err = txOrm.Raw(`UPDATE applications SET deleted_at = NULL
+			WHERE deleted_at = ? AND (user_id = ? OR job_id IN (SELECT id FROM jobs WHERE client_id = ?))`,
+			deletedAt, userID, userID).Exec()
+		return err
+	})
 }
 
-func DeleteUserByID(userID int) error {
+func GetUsers() ([]User, error) {
 	o := orm.NewOrm()
 
-	user := User{Id: userID}
+	var users []User
 
-	_, err := o.Delete(&user)
+	_, err := o.QueryTable(new(User)).Filter("DeletedAt__isnull", true).OrderBy("id").All(&users)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return users, nil
 }
 
-func GetUsers() ([]User, error) {
+func GetDeletedUsers() ([]User, error) {
 	o := orm.NewOrm()
 
 	var users []User
 
-	_, err := o.QueryTable(new(User)).OrderBy("id").All(&users)
+	_, err := o.QueryTable(new(User)).Filter("DeletedAt__isnull", false).OrderBy("-deleted_at").All(&users)
 	if err != nil {
 		return nil, err
 	}
@@ -146,7 +211,7 @@ func GetUsers() ([]User, error) {
 func GetUsersByRole(role string) ([]User, error) {
 	o := orm.NewOrm()
 	var users []User
-	_, err := o.QueryTable(new(User)).Filter("Role", role).All(&users)
+	_, err := o.QueryTable(new(User)).Filter("Role", role).Filter("DeletedAt__isnull", true).All(&users)
 	if err != nil {
 		return nil, err
 	}
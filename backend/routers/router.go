@@ -16,18 +16,60 @@ func init() {
 
 	// user logic
 	web.InsertFilter("/user/*", web.BeforeRouter, middleware.UserAuthMiddleware)
+	web.InsertFilterChain("/user/*", middleware.MultipartUploadChain)
+	web.InsertFilterChain("/user/uploads/*", middleware.UploadChunkChain)
 	web.Router("/user", &controllers.UserController{}, "get:GetUserHandler")
 	web.Router("/user", &controllers.UserController{}, "put:UpdateUserHandler")
 	web.Router("/user", &controllers.UserController{}, "delete:DeleteUserHandler")
 	web.Router("/user/auth", &controllers.AuthController{}, "get:AuthHandler")
 
+	web.Router("/user/reports", &controllers.ReportController{}, "post:CreateReportHandler")
+	web.Router("/user/reports", &controllers.ReportController{}, "get:GetUserReportsHandler")
+
 	web.Router("/user/attachments/:id", &controllers.AttachmentController{}, "get:DownloadAttachment")
+	web.Router("/user/attachments/:id/thumbnail", &controllers.AttachmentController{}, "get:GetAttachmentThumbnailHandler")
+
+	web.Router("/user/uploads", &controllers.UploadController{}, "post:CreateUploadHandler")
+	web.Router("/user/uploads/:id", &controllers.UploadController{}, "head:GetUploadHandler")
+	web.Router("/user/uploads/:id", &controllers.UploadController{}, "patch:PatchUploadHandler")
+	web.Router("/user/uploads/:id", &controllers.UploadController{}, "delete:DeleteUploadHandler")
+
+	web.Router("/user/notifications", &controllers.NotificationController{}, "get:GetNotificationsHandler")
+	web.Router("/user/notifications/read", &controllers.NotificationController{}, "put:MarkNotificationsReadHandler")
 
 	// freelancer role-specific logic
 	web.Router("/user/freelancer", &controllers.FreelancerController{}, "put:UpdateFreelancerDataHandler")
 
 	web.Router("/user/freelancer/skills", &controllers.SkillController{}, "post:AddFreelancerSkillHandler")
 	web.Router("/user/freelancer/skills", &controllers.SkillController{}, "delete:DeleteFreelancerSkillHandler")
+	web.Router("/user/freelancer/skills", &controllers.SkillController{}, "put:UpdateFreelancerSkillHandler")
+
+	web.Router("/user/freelancer/portfolio", &controllers.PortfolioController{}, "post:CreatePortfolioItemHandler")
+	web.Router("/user/freelancer/portfolio", &controllers.PortfolioController{}, "get:GetPortfolioItemsHandler")
+	web.Router("/user/freelancer/portfolio/order", &controllers.PortfolioController{}, "put:ReorderPortfolioHandler")
+	web.Router("/user/freelancer/portfolio/:id", &controllers.PortfolioController{}, "get:GetPortfolioItemHandler")
+	web.Router("/user/freelancer/portfolio/:id", &controllers.PortfolioController{}, "put:UpdatePortfolioItemHandler")
+	web.Router("/user/freelancer/portfolio/:id", &controllers.PortfolioController{}, "delete:DeletePortfolioItemHandler")
+	web.Router("/user/freelancer/portfolio/:id/files", &controllers.PortfolioController{}, "post:AddPortfolioFileHandler")
+	web.Router("/user/freelancer/portfolio/:id/files/:attachmentId", &controllers.PortfolioController{}, "delete:DeletePortfolioFileHandler")
+
+	web.Router("/user/freelancer/credits", &controllers.CreditController{}, "get:GetCreditsHandler")
+
+	web.Router("/user/freelancer/recommended-jobs", &controllers.RecommendationController{}, "get:GetRecommendedJobsHandler")
+
+	web.Router("/user/freelancer/saved-searches", &controllers.SavedSearchController{}, "post:CreateSavedSearchHandler")
+	web.Router("/user/freelancer/saved-searches", &controllers.SavedSearchController{}, "get:GetSavedSearchesHandler")
+	web.Router("/user/freelancer/saved-searches/:id", &controllers.SavedSearchController{}, "put:UpdateSavedSearchHandler")
+	web.Router("/user/freelancer/saved-searches/:id", &controllers.SavedSearchController{}, "delete:DeleteSavedSearchHandler")
+	web.Router("/user/freelancer/saved-searches/:id/jobs", &controllers.SavedSearchController{}, "get:GetSavedSearchJobsHandler")
+
+	web.Router("/user/freelancer/bookmarks", &controllers.BookmarkController{}, "post:CreateJobBookmarkHandler")
+	web.Router("/user/freelancer/bookmarks", &controllers.BookmarkController{}, "get:GetJobBookmarksHandler")
+	web.Router("/user/freelancer/bookmarks/:id", &controllers.BookmarkController{}, "delete:DeleteJobBookmarkHandler")
+
+	web.Router("/user/freelancer/invitations", &controllers.InvitationController{}, "get:GetFreelancerInvitationsHandler")
+	web.Router("/user/freelancer/invitations/:id/accept", &controllers.InvitationController{}, "post:AcceptInvitationHandler")
+	web.Router("/user/freelancer/invitations/:id/decline", &controllers.InvitationController{}, "post:DeclineInvitationHandler")
 
 	web.Router("/user/freelancer/jobs", &controllers.JobController{}, "get:GetFreelancerJobsHandler")
 	web.Router("/user/freelancer/jobs/:id", &controllers.JobController{}, "get:GetFreelancerJobHandler")
@@ -35,12 +77,21 @@ func init() {
 	web.Router("/user/freelancer/applications", &controllers.ApplicationController{}, "post:SubmitApplication")
 	web.Router("/user/freelancer/applications", &controllers.ApplicationController{}, "get:GetFreelancerApplications")
 	web.Router("/user/freelancer/applications/:id", &controllers.ApplicationController{}, "get:GetFreelancerApplication")
-	web.Router("/user/freelancer/applications/:id", &controllers.ApplicationController{}, "delete:DeleteApplication")
+	web.Router("/user/freelancer/applications/:id", &controllers.ApplicationController{}, "delete:WithdrawApplication")
+	web.Router("/user/freelancer/applications/:id/withdraw", &controllers.ApplicationController{}, "post:WithdrawApplication")
 	web.Router("/user/freelancer/applications/:id", &controllers.ApplicationController{}, "put:UpdateApplication")
+	web.Router("/user/freelancer/applications/:id/attachments", &controllers.AttachmentController{}, "post:AddApplicationAttachmentHandler")
+	web.Router("/user/freelancer/applications/:id/attachments/:attachmentId", &controllers.AttachmentController{}, "delete:DeleteApplicationAttachmentHandler")
+	web.Router("/user/freelancer/applications/:id/offers", &controllers.OfferController{}, "get:GetOffersHandler")
+	web.Router("/user/freelancer/applications/:id/offers", &controllers.OfferController{}, "post:ProposeOfferHandler")
+	web.Router("/user/freelancer/applications/:id/offers/accept", &controllers.OfferController{}, "post:AcceptOfferHandler")
+	web.Router("/user/freelancer/applications/:id/offers/decline", &controllers.OfferController{}, "post:DeclineOfferHandler")
 
 	// skill logic
 	web.InsertFilter("/skills/*", web.BeforeRouter, middleware.UserAuthMiddleware)
 	web.Router("/skills", &controllers.SkillController{}, "get:GetSkillsHandler")
+	web.Router("/skills/search", &controllers.SkillController{}, "get:SearchSkillsHandler")
+	web.Router("/skills/categories", &controllers.SkillController{}, "get:GetSkillCategoriesHandler")
 
 	// client role-specific logic
 	web.Router("/user/client", &controllers.ClientController{}, "put:UpdateClientDataHandler")
@@ -51,20 +102,76 @@ func init() {
 	web.Router("/user/client/jobs/:id", &controllers.JobController{}, "delete:DeleteClientJobHandler")
 	web.Router("/user/client/jobs/:id", &controllers.JobController{}, "put:UpdateClientJobHandler")
 	web.Router("/user/client/jobs/:id/complete", &controllers.JobController{}, "post:CompleteJobHandler")
+	web.Router("/user/client/jobs/:id/publish", &controllers.JobController{}, "post:PublishJobHandler")
+	web.Router("/user/client/jobs/:id/extend", &controllers.JobController{}, "post:ExtendJobHandler")
+	web.Router("/user/client/jobs/:id/republish", &controllers.JobController{}, "post:RepublishJobHandler")
+	web.Router("/user/client/jobs/:id/cancel", &controllers.JobController{}, "post:CancelJobHandler")
+	web.Router("/user/client/jobs/:id/pause", &controllers.JobController{}, "post:PauseJobHandler")
+	web.Router("/user/client/jobs/:id/resume", &controllers.JobController{}, "post:ResumeJobHandler")
+	web.Router("/user/client/jobs/:id/reopen", &controllers.JobController{}, "post:ReopenJobHandler")
+	web.Router("/user/client/jobs/:id/pipeline", &controllers.JobController{}, "get:GetJobPipelineHandler")
+	web.Router("/user/client/jobs/:id/attachments", &controllers.AttachmentController{}, "post:AddJobAttachmentHandler")
+	web.Router("/user/client/jobs/:id/attachments/:attachmentId", &controllers.AttachmentController{}, "delete:DeleteJobAttachmentHandler")
+	web.Router("/user/client/jobs/:id/invitations", &controllers.InvitationController{}, "post:InviteFreelancerHandler")
+	web.Router("/user/client/jobs/:id/recommended-freelancers", &controllers.RecommendationController{}, "get:GetRecommendedFreelancersHandler")
+
+	web.Router("/user/client/talent-lists", &controllers.TalentListController{}, "post:CreateTalentListHandler")
+	web.Router("/user/client/talent-lists", &controllers.TalentListController{}, "get:GetTalentListsHandler")
+	web.Router("/user/client/talent-lists/:id", &controllers.TalentListController{}, "get:GetTalentListHandler")
+	web.Router("/user/client/talent-lists/:id", &controllers.TalentListController{}, "put:UpdateTalentListHandler")
+	web.Router("/user/client/talent-lists/:id", &controllers.TalentListController{}, "delete:DeleteTalentListHandler")
+	web.Router("/user/client/talent-lists/:id/freelancers", &controllers.TalentListController{}, "post:AddTalentListFreelancerHandler")
+	web.Router("/user/client/talent-lists/:id/freelancers/:freelancerId", &controllers.TalentListController{}, "delete:RemoveTalentListFreelancerHandler")
 
 	web.Router("/user/client/jobs/applications/:id", &controllers.ApplicationController{}, "post:ChangeApplicationStatus")
+	web.Router("/user/client/jobs/applications/:id/notes", &controllers.ApplicationController{}, "put:ApplicationNotesHandler")
+	web.Router("/user/client/jobs/applications/:id/cancellation/acknowledge", &controllers.ApplicationController{}, "post:AcknowledgeCancellationHandler")
+	web.Router("/user/client/jobs/applications/:id/offers", &controllers.OfferController{}, "get:GetOffersHandler")
+	web.Router("/user/client/jobs/applications/:id/offers", &controllers.OfferController{}, "post:ProposeOfferHandler")
+	web.Router("/user/client/jobs/applications/:id/offers/accept", &controllers.OfferController{}, "post:AcceptOfferHandler")
+	web.Router("/user/client/jobs/applications/:id/offers/decline", &controllers.OfferController{}, "post:DeclineOfferHandler")
 
 	// admin role-specific logic
 	web.InsertFilter("/admin/*", web.BeforeRouter, middleware.AdminAuthMiddleware)
+	web.Router("/admin/stats", &controllers.AdminController{}, "get:GetStatsHandler")
 	web.Router("/admin/users", &controllers.AdminController{}, "get:GetUsersHandler")
 	web.Router("/admin/users/:id", &controllers.AdminController{}, "delete:DeleteUserHandler")
 	web.Router("/admin/users/:id", &controllers.AdminController{}, "put:UpdateUserHandler")
+	web.Router("/admin/users/:id/restore", &controllers.AdminController{}, "post:RestoreUserHandler")
+	web.Router("/admin/deleted/users", &controllers.AdminController{}, "get:GetDeletedUsersHandler")
+	web.Router("/admin/users/:id/credits", &controllers.CreditController{}, "post:GrantCreditsHandler")
 
 	web.Router("/admin/skills", &controllers.SkillController{}, "post:AddSkillHandler")
 	web.Router("/admin/skills/:id", &controllers.SkillController{}, "delete:DeleteSkillHandler")
 	web.Router("/admin/skills/:id", &controllers.SkillController{}, "put:UpdateSkillHandler")
+	web.Router("/admin/skills/:id/aliases", &controllers.SkillController{}, "post:AddSkillAliasHandler")
+	web.Router("/admin/skills/:id/merge", &controllers.SkillController{}, "post:MergeSkillHandler")
+	web.Router("/admin/skills/aliases/:id", &controllers.SkillController{}, "delete:DeleteSkillAliasHandler")
+	web.Router("/admin/skills/categories", &controllers.SkillController{}, "post:AddSkillCategoryHandler")
+	web.Router("/admin/skills/categories/:id", &controllers.SkillController{}, "put:UpdateSkillCategoryHandler")
+	web.Router("/admin/skills/categories/:id", &controllers.SkillController{}, "delete:DeleteSkillCategoryHandler")
 
 	web.Router("/admin/jobs/:id", &controllers.JobController{}, "delete:DeleteJobHandler")
+	web.Router("/admin/jobs/:id/restore", &controllers.JobController{}, "post:RestoreJobHandler")
+	web.Router("/admin/deleted/jobs", &controllers.JobController{}, "get:GetDeletedJobsHandler")
+
+	web.Router("/admin/applications/:id/restore", &controllers.ApplicationController{}, "post:RestoreApplication")
+	web.Router("/admin/deleted/applications", &controllers.ApplicationController{}, "get:GetDeletedApplications")
+
+	web.Router("/admin/users/:id/bans", &controllers.BanController{}, "get:GetUserBansHandler")
+	web.Router("/admin/users/:id/bans", &controllers.BanController{}, "post:BanUserHandler")
+	web.Router("/admin/bans/:id", &controllers.BanController{}, "delete:LiftBanHandler")
+	web.Router("/admin/appeals", &controllers.BanController{}, "get:GetBanAppealsHandler")
+	web.Router("/admin/appeals/:id", &controllers.BanController{}, "put:ReviewBanAppealHandler")
+
+	web.Router("/admin/reports", &controllers.ReportController{}, "get:GetReportsHandler")
+	web.Router("/admin/reports/:id", &controllers.ReportController{}, "get:GetReportHandler")
+	web.Router("/admin/reports/:id", &controllers.ReportController{}, "put:ResolveReportHandler")
+
+	// ban appeal logic, reachable by banned users
+	web.InsertFilter("/appeals/*", web.BeforeRouter, middleware.AppealAuthMiddleware)
+	web.Router("/appeals", &controllers.BanController{}, "get:GetAppealsHandler")
+	web.Router("/appeals", &controllers.BanController{}, "post:SubmitAppealHandler")
 
 	// public freelancer logic
 	web.InsertFilter("/freelancers/*", web.BeforeRouter, middleware.UserAuthMiddleware)
@@ -0,0 +1,87 @@
+package scanner
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// clamavChunkSize is how many bytes are sent to clamd at once
+const clamavChunkSize = 32 << 10
+
+// ClamAVScanner streams files to a clamd daemon with the INSTREAM command
+type ClamAVScanner struct {
+	Address string // host:port of clamd
+	Timeout time.Duration
+}
+
+func NewClamAVScanner(address string, timeout time.Duration) *ClamAVScanner {
+	return &ClamAVScanner{Address: address, Timeout: timeout}
+}
+
+// Scan sends the file in chunks, each prefixed with its length, and reads the verdict clamd
+// answers after the zero-length chunk that ends the stream
+func (s *ClamAVScanner) Scan(r io.Reader) (*Result, error) {
+	conn, err := net.DialTimeout("tcp", s.Address, s.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(s.Timeout))
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return nil, err
+	}
+
+	chunk := make([]byte, 4+clamavChunkSize)
+	for {
+		n, readErr := r.Read(chunk[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(chunk[:4], uint32(n))
+			if _, err := conn.Write(chunk[:4+n]); err != nil {
+				// clamd closes the connection when the stream goes past its StreamMaxLength
+				return s.reply(conn, err)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, readErr
+		}
+	}
+
+	if _, err := conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return nil, err
+	}
+	return s.reply(conn, nil)
+}
+
+// reply reads the verdict: "stream: OK", "stream: <signature> FOUND" or "<message> ERROR".
+// writeErr is returned when there is no reply after a failed write.
+func (s *ClamAVScanner) reply(conn net.Conn, writeErr error) (*Result, error) {
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && reply == "" {
+		if writeErr != nil {
+			return nil, writeErr
+		}
+		return nil, err
+	}
+	reply = strings.TrimRight(reply, "\x00\n")
+
+	switch {
+	case strings.HasSuffix(reply, " OK"):
+		return &Result{Clean: true}, nil
+	case strings.HasSuffix(reply, " FOUND"):
+		signature := strings.TrimSuffix(strings.TrimPrefix(reply, "stream: "), " FOUND")
+		return &Result{Signature: signature}, nil
+	case strings.Contains(reply, "size limit exceeded"):
+		return nil, ErrTooLarge
+	default:
+		return nil, fmt.Errorf("clamd: %s", reply)
+	}
+}
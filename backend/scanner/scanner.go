@@ -0,0 +1,52 @@
+package scanner
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/beego/beego/v2/server/web"
+)
+
+// ErrTooLarge is returned when the file is larger than the scanner accepts
+var ErrTooLarge = errors.New("file is too large to scan")
+
+// Result of scanning a file, Signature names the malware when the file is not clean
+type Result struct {
+	Clean     bool
+	Signature string
+}
+
+// Scanner checks files for malware
+type Scanner interface {
+	Scan(r io.Reader) (*Result, error)
+}
+
+var (
+	defaultScanner Scanner
+	once           sync.Once
+)
+
+// Default returns the scanner from the configuration: scanner_driver clamav uses a clamd daemon,
+// none reports every file clean and is meant for development only
+func Default() Scanner {
+	once.Do(func() {
+		if web.AppConfig.DefaultString("scanner_driver", "clamav") == "none" {
+			defaultScanner = NoopScanner{}
+			return
+		}
+		defaultScanner = NewClamAVScanner(
+			web.AppConfig.DefaultString("clamav_address", "clamav:3310"),
+			time.Duration(web.AppConfig.DefaultInt("clamav_timeout", 120))*time.Second,
+		)
+	})
+	return defaultScanner
+}
+
+// NoopScanner reports every file clean without reading it
+type NoopScanner struct{}
+
+func (NoopScanner) Scan(r io.Reader) (*Result, error) {
+	return &Result{Clean: true}, nil
+}
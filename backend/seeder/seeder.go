@@ -12,6 +12,7 @@ import (
 
 func SeedDatabase() {
 	SeedSkills()
+	SeedSkillTaxonomy()
 	SeedUsers()
 	SeedJobs()
 	SeedApplications()
@@ -21,6 +22,7 @@ var projectTypes = []string{"ongoing", "one-time"}
 var rateTypes = []string{"hourly", "fixed"}
 var lengthOptions = []string{"<1", "1-3", "3-6", "6-12", "12+"}
 var hoursPerWeekOptions = []string{"<20", "20-40", "40-60", "60-80", "80+"}
+var skillLevels = []string{"beginner", "intermediate", "advanced", "expert"}
 
 func getSkills() []string {
 	return []string{
@@ -37,6 +39,62 @@ func getSkills() []string {
 	}
 }
 
+// getSkillCategories maps each top level category to its subcategories and their skills
+func getSkillCategories() map[string]map[string][]string {
+	return map[string]map[string][]string{
+		"Software Development": {
+			"Programming Languages": {"JavaScript", "Python", "Java", "C++", "C#", "Go", "Ruby", "Swift", "Kotlin", "PHP", "TypeScript", "Rust", "Haskell", "Scala", "Lisp", "Erlang", "Dart", "Perl", "R", "MATLAB", "COBOL", "Solidity", "Bash"},
+			"Web Development":       {"HTML", "CSS", "Django", "Flask", "Spring", "React", "Angular", "Vue.js", "Node.js", "Express.js", "GraphQL"},
+			"Mobile Development":    {"Mobile Development", "iOS", "Android", "React Native", "Flutter", "Xamarin"},
+			"Game Development":      {"Game Development", "Unity", "Unreal Engine", "Cocos2d", "AR/VR"},
+			"Practices":             {"Functional Programming", "Git", "Agile", "Scrum"},
+		},
+		"Data": {
+			"Databases":        {"SQL", "NoSQL", "MongoDB", "PostgreSQL", "MySQL", "Elasticsearch", "Firebase"},
+			"Data Engineering": {"Big Data", "Hadoop", "Spark", "Kafka", "Data Analysis", "Pandas", "NumPy"},
+			"AI & ML":          {"Machine Learning", "Deep Learning", "NLP", "Computer Vision", "TensorFlow", "PyTorch", "Scikit-Learn"},
+		},
+		"Infrastructure": {
+			"Cloud":    {"AWS", "Azure", "GCP", "Cloud Computing"},
+			"DevOps":   {"Docker", "Kubernetes", "Terraform", "Jenkins", "CI/CD", "DevOps", "Site Reliability Engineering", "Linux", "Networking"},
+			"Security": {"Cybersecurity", "Penetration Testing", "Cryptography", "Blockchain", "Smart Contracts"},
+			"Hardware": {"IoT", "Embedded Systems", "Robotics", "Automation"},
+		},
+		"Design": {
+			"UI/UX":    {"UI/UX Design", "Figma", "Adobe XD"},
+			"Graphics": {"Photoshop", "Illustrator", "3D Modeling", "Animation"},
+		},
+		"Management": {
+			"Project Management": {"Project Management"},
+		},
+	}
+}
+
+func getSkillAliases() map[string][]string {
+	return map[string][]string{
+		"JavaScript":       {"JS", "ECMAScript"},
+		"TypeScript":       {"TS"},
+		"Go":               {"Golang"},
+		"C#":               {"CSharp", "C Sharp"},
+		"C++":              {"CPP"},
+		"React":            {"React.js", "ReactJS"},
+		"Vue.js":           {"Vue", "VueJS"},
+		"Angular":          {"AngularJS", "Angular.js"},
+		"Node.js":          {"Node", "NodeJS"},
+		"Express.js":       {"Express", "ExpressJS"},
+		"PostgreSQL":       {"Postgres"},
+		"Kubernetes":       {"K8s"},
+		"GCP":              {"Google Cloud", "Google Cloud Platform"},
+		"AWS":              {"Amazon Web Services"},
+		"Azure":            {"Microsoft Azure"},
+		"Machine Learning": {"ML"},
+		"NLP":              {"Natural Language Processing"},
+		"Scikit-Learn":     {"sklearn"},
+		"React Native":     {"ReactNative"},
+		"UI/UX Design":     {"UI Design", "UX Design"},
+	}
+}
+
 func SeedUsers() {
 	o := orm.NewOrm()
 
@@ -108,9 +166,14 @@ func SeedUsers() {
 						continue
 					}
 
-					m2m := o.QueryM2M(&freelancerData, "Skills")
 					for _, skill := range skills {
-						if _, err := m2m.Add(&skill); err != nil {
+						freelancerSkill := models.FreelancerSkill{
+							FreelancerData:    &freelancerData,
+							Skill:             &skill,
+							Level:             skillLevels[rand.IntN(len(skillLevels))],
+							YearsOfExperience: rand.IntN(10),
+						}
+						if _, err := o.Insert(&freelancerSkill); err != nil {
 							log.Printf("Error adding skill %s to freelancer %d: %v", skill.Name, userID, err)
 						}
 					}
@@ -177,6 +240,55 @@ func SeedSkills() {
 
 }
 
+// Add skill categories and aliases, only once
+func SeedSkillTaxonomy() {
+	o := orm.NewOrm()
+
+	count, err := o.QueryTable(new(models.SkillCategory)).Count()
+	if err != nil {
+		log.Fatalf("Error checking skill categories table: %v", err)
+		return
+	}
+
+	if count > 0 {
+		log.Println("Skill categories table already contains data.")
+		return
+	}
+
+	for categoryName, subcategories := range getSkillCategories() {
+		categoryID, err := models.CreateSkillCategory(categoryName, 0)
+		if err != nil {
+			log.Printf("Error inserting skill category %s: %v", categoryName, err)
+			continue
+		}
+
+		for subcategoryName, skillNames := range subcategories {
+			subcategoryID, err := models.CreateSkillCategory(subcategoryName, categoryID)
+			if err != nil {
+				log.Printf("Error inserting skill category %s: %v", subcategoryName, err)
+				continue
+			}
+
+			_, err = o.QueryTable(new(models.Skill)).Filter("Name__in", skillNames).Update(orm.Params{"category_id": subcategoryID})
+			if err != nil {
+				log.Printf("Error categorising skills of %s: %v", subcategoryName, err)
+			}
+		}
+	}
+
+	for skillName, aliases := range getSkillAliases() {
+		skill, err := models.ResolveSkillByName(skillName)
+		if err != nil {
+			continue
+		}
+		for _, alias := range aliases {
+			if err := models.CreateSkillAlias(skill.Id, alias); err != nil {
+				log.Printf("Error inserting skill alias %s: %v", alias, err)
+			}
+		}
+	}
+}
+
 func SeedJobs() {
 	o := orm.NewOrm()
 
@@ -230,7 +342,9 @@ func SeedJobs() {
 				Amount:       amount,
 				Length:       lengthOptions[rand.IntN(len(lengthOptions))],
 				HoursPerWeek: hoursPerWeekOptions[rand.IntN(len(hoursPerWeekOptions))],
+				Openings:     1,
 				Status:       "open",
+				Visibility:   "public",
 			}
 
 			jobID, err := o.Insert(&job)
@@ -247,9 +361,14 @@ func SeedJobs() {
 				continue
 			}
 
-			m2m := o.QueryM2M(&job, "Skills")
-			for _, skill := range skills {
-				if _, err := m2m.Add(&skill); err != nil {
+			for i, skill := range skills {
+				jobSkill := models.JobSkill{
+					Job:      &job,
+					Skill:    &skill,
+					Required: i == 0 || rand.IntN(2) == 0, // the first skill is always required
+					MinLevel: skillLevels[rand.IntN(len(skillLevels))],
+				}
+				if _, err := o.Insert(&jobSkill); err != nil {
 					log.Printf("Error adding skill %s to job %d: %v", skill.Name, jobID, err)
 				}
 			}
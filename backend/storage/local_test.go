@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStoragePath(t *testing.T) {
+	dir := t.TempDir()
+	s := NewLocalStorage(dir)
+
+	tests := []struct {
+		key  string
+		want string // empty when the key is rejected
+	}{
+		{"a.pdf", filepath.Join(dir, "a.pdf")},
+		{"2024/05/a.pdf", filepath.Join(dir, "2024", "05", "a.pdf")},
+		{"x/../a.pdf", filepath.Join(dir, "a.pdf")},
+		{"x_../../a.pdf", filepath.Join(dir, "a.pdf")},
+		{"", ""},
+		{".", ""},
+		{"..", ""},
+		{"../a.pdf", ""},
+		{"../../etc/passwd", ""},
+		{"x/../../a.pdf", ""},
+		{"/../a.pdf", ""},
+		{"../" + filepath.Base(dir) + "/a.pdf", filepath.Join(dir, "a.pdf")},
+		{"../" + filepath.Base(dir) + "-other/a.pdf", ""},
+	}
+
+	for _, tt := range tests {
+		got, err := s.path(tt.key)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("path(%q) = %s, want an error", tt.key, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("path(%q) = %s, %v, want %s", tt.key, got, err, tt.want)
+		}
+	}
+}
+
+func TestLocalStorage(t *testing.T) {
+	s := NewLocalStorage(t.TempDir())
+	content := []byte("%PDF-1.4 local storage test")
+
+	if err := s.Put("2024/a.pdf", bytes.NewReader(content), int64(len(content))); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	file, err := s.Get("2024/a.pdf")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	got, err := io.ReadAll(file)
+	file.Close()
+	if err != nil || !bytes.Equal(got, content) {
+		t.Fatalf("Get = %q, %v, want %q", got, err, content)
+	}
+
+	// A reader shorter than the announced size leaves no partial file behind
+	if err := s.Put("short.pdf", strings.NewReader("abc"), 10); err == nil {
+		t.Error("Put of a short reader succeeded")
+	}
+	if _, err := s.Get("short.pdf"); err != ErrNotFound {
+		t.Errorf("Get of a failed Put = %v, want ErrNotFound", err)
+	}
+
+	if err := s.Delete("2024/a.pdf"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Get("2024/a.pdf"); err != ErrNotFound {
+		t.Errorf("Get after Delete = %v, want ErrNotFound", err)
+	}
+	if err := s.Delete("2024/a.pdf"); err != nil {
+		t.Errorf("Delete of a missing key = %v, want nil", err)
+	}
+
+	if err := s.Put("../a.pdf", bytes.NewReader(content), int64(len(content))); err == nil {
+		t.Error("Put outside the directory succeeded")
+	}
+	if _, err := s.SignedURL("a.pdf", "a.pdf", 0); err != ErrSignedURLUnsupported {
+		t.Errorf("SignedURL = %v, want ErrSignedURLUnsupported", err)
+	}
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/beego/beego/v2/server/web"
+)
+
+var (
+	// ErrNotFound is returned by Get when there is no file under the key
+	ErrNotFound = errors.New("file not found")
+	// ErrSignedURLUnsupported is returned by SignedURL when the files can only be read through Get
+	ErrSignedURLUnsupported = errors.New("signed URLs are not supported")
+)
+
+// Storage keeps the uploaded files under keys, like "20060102_150405.000000_cv.pdf"
+type Storage interface {
+	// Put stores size bytes of the reader under the key, replacing what was there
+	Put(key string, r io.Reader, size int64) error
+	// Get opens the file under the key, the caller closes it
+	Get(key string) (io.ReadCloser, error)
+	// Delete removes the file under the key, a missing file is not an error
+	Delete(key string) error
+	// SignedURL returns a URL anyone can download the file from until it expires, the file is
+	// downloaded with fileName when it is not empty
+	SignedURL(key, fileName string, expires time.Duration) (string, error)
+}
+
+var (
+	defaultStorage Storage
+	once           sync.Once
+)
+
+// Default returns the storage from the configuration: storage_driver s3 uses an S3-compatible service,
+// anything else the local filesystem
+func Default() Storage {
+	once.Do(func() {
+		if web.AppConfig.DefaultString("storage_driver", "local") == "s3" {
+			defaultStorage = NewS3Storage(
+				web.AppConfig.DefaultString("s3_endpoint", ""),
+				web.AppConfig.DefaultString("s3_public_endpoint", ""),
+				web.AppConfig.DefaultString("s3_region", "us-east-1"),
+				web.AppConfig.DefaultString("s3_bucket", ""),
+				web.AppConfig.DefaultString("s3_access_key", ""),
+				web.AppConfig.DefaultString("s3_secret_key", ""),
+				web.AppConfig.DefaultBool("s3_path_style", true),
+			)
+			return
+		}
+		defaultStorage = NewLocalStorage(web.AppConfig.DefaultString("storage_local_dir", "uploads"))
+	})
+	return defaultStorage
+}
+
+// ConcatReader reads the files under the keys one after the other, each file is opened when it is reached
+func ConcatReader(storage Storage, keys []string) io.ReadCloser {
+	return &concatReader{storage: storage, keys: keys}
+}
+
+type concatReader struct {
+	storage Storage
+	keys    []string
+	current io.ReadCloser
+}
+
+func (r *concatReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if len(r.keys) == 0 {
+				return 0, io.EOF
+			}
+			file, err := r.storage.Get(r.keys[0])
+			if err != nil {
+				return 0, err
+			}
+			r.current = file
+			r.keys = r.keys[1:]
+		}
+
+		n, err := r.current.Read(p)
+		if err == io.EOF {
+			r.current.Close()
+			r.current = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (r *concatReader) Close() error {
+	if r.current != nil {
+		return r.current.Close()
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestConcatReader(t *testing.T) {
+	s := NewLocalStorage(t.TempDir())
+	parts := map[string]string{
+		"u.part0": "first ",
+		"u.part1": "",
+		"u.part2": "second ",
+		"u.part3": strings.Repeat("x", 64<<10),
+	}
+	for key, content := range parts {
+		if err := s.Put(key, strings.NewReader(content), int64(len(content))); err != nil {
+			t.Fatalf("Put(%s): %v", key, err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		keys    []string
+		want    string
+		wantErr error
+	}{
+		{"no parts", nil, "", nil},
+		{"single part", []string{"u.part0"}, "first ", nil},
+		{"parts in order", []string{"u.part0", "u.part1", "u.part2", "u.part3"}, "first second " + parts["u.part3"], nil},
+		{"missing part", []string{"u.part0", "u.part9"}, "first ", ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := ConcatReader(s, tt.keys)
+			defer r.Close()
+
+			got, err := io.ReadAll(r)
+			if err != tt.wantErr {
+				t.Fatalf("ReadAll error = %v, want %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("ReadAll = %d bytes, want %d", len(got), len(tt.want))
+			}
+		})
+	}
+}
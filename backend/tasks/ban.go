@@ -0,0 +1,22 @@
+package tasks
+
+import (
+	"backend/models"
+	"context"
+	"log"
+)
+
+// Lifts bans whose end time has passed so users do not stay flagged as banned
+func LiftExpiredBans(ctx context.Context) error {
+	lifted, err := models.LiftExpiredBans()
+	if err != nil {
+		log.Printf("Error lifting expired bans: %v", err)
+		return err
+	}
+
+	if lifted > 0 {
+		log.Printf("Lifted %d expired bans", lifted)
+	}
+
+	return nil
+}
@@ -0,0 +1,22 @@
+package tasks
+
+import (
+	"backend/models"
+	"context"
+	"log"
+)
+
+// Removes bookmarks of jobs that were completed, cancelled or expired, which can no longer be applied to
+func PruneJobBookmarks(ctx context.Context) error {
+	pruned, err := models.PruneJobBookmarks()
+	if err != nil {
+		log.Printf("Error pruning job bookmarks: %v", err)
+		return err
+	}
+
+	if pruned > 0 {
+		log.Printf("Pruned %d job bookmarks", pruned)
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package tasks
+
+import (
+	"backend/models"
+	"context"
+	"log"
+
+	"github.com/beego/beego/v2/server/web"
+)
+
+// Tops the freelancers' credits up to the monthly allowance, each freelancer once per month
+func GrantMonthlyCredits(ctx context.Context) error {
+	allowance := web.AppConfig.DefaultInt("credits_monthly_allowance", 60)
+
+	granted, err := models.GrantMonthlyCredits(allowance)
+	if err != nil {
+		log.Printf("Error granting monthly credits: %v", err)
+		return err
+	}
+
+	if granted > 0 {
+		log.Printf("Granted the monthly credit allowance to %d freelancers", granted)
+	}
+
+	return nil
+}
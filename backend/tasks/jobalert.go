@@ -0,0 +1,94 @@
+package tasks
+
+import (
+	"backend/models"
+	"backend/utils"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// Matches newly created jobs against the saved searches and delivers instant alerts
+func MatchJobAlerts(ctx context.Context) error {
+	matches, err := models.ProcessPendingJobAlerts(100)
+	if err != nil {
+		log.Printf("Error matching job alerts: %v", err)
+		return err
+	}
+
+	if matches > 0 {
+		log.Printf("Matched %d jobs to saved searches", matches)
+	}
+
+	return deliverJobAlerts("instant", time.Now())
+}
+
+// Delivers daily and weekly job alert digests to saved searches that are due
+func SendJobAlertDigests(ctx context.Context) error {
+	now := time.Now()
+
+	if err := deliverJobAlerts("daily", now.Add(-24*time.Hour)); err != nil {
+		return err
+	}
+
+	return deliverJobAlerts("weekly", now.AddDate(0, 0, -7))
+}
+
+// deliverJobAlerts sends one notification or email per saved search with its undelivered matches
+func deliverJobAlerts(frequency string, cutoff time.Time) error {
+	matches, err := models.GetUndeliveredSavedSearchMatches(frequency, cutoff)
+	if err != nil {
+		log.Printf("Error fetching %s job alerts: %v", frequency, err)
+		return err
+	}
+
+	// Matches are ordered by saved search
+	for start := 0; start < len(matches); {
+		end := start
+		for end < len(matches) && matches[end].SavedSearch.Id == matches[start].SavedSearch.Id {
+			end++
+		}
+
+		if err := deliverSavedSearchMatches(matches[start:end]); err != nil {
+			log.Printf("Error delivering job alerts of saved search %d: %v", matches[start].SavedSearch.Id, err)
+		}
+		start = end
+	}
+
+	return nil
+}
+
+func deliverSavedSearchMatches(matches []models.SavedSearchMatch) error {
+	savedSearch := matches[0].SavedSearch
+	user := savedSearch.User
+
+	title := fmt.Sprintf("%d new jobs match \"%s\"", len(matches), savedSearch.Name)
+	link := "/jobs"
+	if len(matches) == 1 {
+		title = fmt.Sprintf("New job matches \"%s\"", savedSearch.Name)
+		link = fmt.Sprintf("/jobs/%d", matches[0].Job.Id)
+	}
+
+	var lines []string
+	matchIDs := make([]int, 0, len(matches))
+	for _, match := range matches {
+		lines = append(lines, fmt.Sprintf("- %s (%s, %d)", match.Job.Title, match.Job.Rate, match.Job.Amount))
+		matchIDs = append(matchIDs, match.Id)
+	}
+	message := strings.Join(lines, "\n")
+
+	var err error
+	switch savedSearch.Channel {
+	case "email":
+		err = utils.SendEmail(user.Email, title, message)
+	default:
+		err = models.CreateNotification(user.Id, "job-alert", title, message, link)
+	}
+	if err != nil {
+		return err
+	}
+
+	return models.MarkSavedSearchMatchesDelivered(savedSearch.Id, matchIDs)
+}
@@ -0,0 +1,37 @@
+package tasks
+
+import (
+	"backend/jobstate"
+	"context"
+	"log"
+)
+
+// Opens the scheduled jobs whose publish time has come
+func PublishScheduledJobs(ctx context.Context) error {
+	published, err := jobstate.PublishDueJobs()
+	if err != nil {
+		log.Printf("Error publishing scheduled jobs: %v", err)
+		return err
+	}
+
+	if published > 0 {
+		log.Printf("Published %d scheduled jobs", published)
+	}
+
+	return nil
+}
+
+// Closes the open jobs past their expiry and rejects their pending applications
+func ExpireJobs(ctx context.Context) error {
+	expired, err := jobstate.ExpireDueJobs()
+	if err != nil {
+		log.Printf("Error expiring jobs: %v", err)
+		return err
+	}
+
+	if expired > 0 {
+		log.Printf("Expired %d jobs", expired)
+	}
+
+	return nil
+}
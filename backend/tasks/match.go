@@ -0,0 +1,33 @@
+package tasks
+
+import (
+	"backend/models"
+	"context"
+	"log"
+)
+
+// Recomputes the match scores of jobs and freelancers changed since the last run
+func RefreshMatchScores(ctx context.Context) error {
+	refreshed, err := models.ProcessMatchScoreRefreshes(100)
+	if err != nil {
+		log.Printf("Error refreshing match scores: %v", err)
+		return err
+	}
+
+	if refreshed > 0 {
+		log.Printf("Refreshed match scores of %d jobs and freelancers", refreshed)
+	}
+
+	return nil
+}
+
+// Recomputes every match score, catching changes the incremental refresh missed
+func RebuildMatchScores(ctx context.Context) error {
+	if err := models.RebuildMatchScores(); err != nil {
+		log.Printf("Error rebuilding match scores: %v", err)
+		return err
+	}
+
+	log.Println("Rebuilt match scores")
+	return nil
+}
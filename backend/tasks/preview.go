@@ -0,0 +1,82 @@
+package tasks
+
+import (
+	"backend/documents"
+	"backend/models"
+	"backend/storage"
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/beego/beego/v2/server/web"
+)
+
+// Extracts the text, page count and first-page thumbnail of the attachments scanned clean
+func ProcessAttachments(ctx context.Context) error {
+	batchSize := web.AppConfig.DefaultInt("preview_batch_size", 10)
+	timeout := time.Duration(web.AppConfig.DefaultInt("preview_timeout", 60)) * time.Second
+	attachments, err := models.ClaimUnprocessedAttachments(batchSize, time.Now(), timeout*2)
+	if err != nil {
+		log.Printf("Error claiming attachments to process: %v", err)
+		return err
+	}
+
+	var processed, failed int
+	for _, attachment := range attachments {
+		err := processAttachment(ctx, &attachment, timeout)
+		if errors.Is(err, documents.ErrInvalidDocument) {
+			log.Printf("Attachment %d cannot be processed: %v", attachment.Id, err)
+			if err := models.SetAttachmentPreviewFailed(attachment.Id); err != nil {
+				log.Printf("Error saving preview of attachment %d: %v", attachment.Id, err)
+			}
+			failed++
+			continue
+		}
+		if err != nil {
+			// the claim runs out and the next run processes the file again
+			log.Printf("Error processing attachment %d: %v", attachment.Id, err)
+			continue
+		}
+		processed++
+	}
+
+	if processed > 0 || failed > 0 {
+		log.Printf("Processed attachments: %d ready, %d failed", processed, failed)
+	}
+
+	return nil
+}
+
+func processAttachment(ctx context.Context, attachment *models.Attachment, timeout time.Duration) error {
+	store := storage.Default()
+	file, err := store.Get(attachment.FilePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	preview, err := documents.PreviewPDF(ctx, file,
+		web.AppConfig.DefaultInt("preview_max_text_length", 200000),
+		web.AppConfig.DefaultInt("preview_thumbnail_size", 400))
+	if err != nil {
+		return err
+	}
+
+	thumbnailPath := attachment.FilePath + ".thumb.png"
+	if err := store.Put(thumbnailPath, bytes.NewReader(preview.Thumbnail), int64(len(preview.Thumbnail))); err != nil {
+		return err
+	}
+
+	if err := models.SetAttachmentPreview(attachment.Id, preview.Text, preview.PageCount, thumbnailPath); err != nil {
+		// the attachment was deleted in the meantime
+		store.Delete(thumbnailPath)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package tasks
+
+import (
+	"backend/models"
+	"backend/storage"
+	"context"
+	"log"
+	"time"
+
+	"github.com/beego/beego/v2/server/web"
+)
+
+// Permanently deletes rows soft-deleted longer than the retention period, together with their attachment files
+func PurgeDeletedRecords(ctx context.Context) error {
+	retentionDays := web.AppConfig.DefaultInt("soft_delete_retention_days", 30)
+	cutoff := time.Now().AddDate(0, 0, -retentionDays)
+
+	filePaths, err := models.PurgeDeleted(cutoff)
+	if err != nil {
+		log.Printf("Error purging deleted records: %v", err)
+		return err
+	}
+
+	for _, filePath := range filePaths {
+		if err := storage.Default().Delete(filePath); err != nil {
+			log.Printf("Error removing attachment %s: %v", filePath, err)
+		}
+	}
+
+	if len(filePaths) > 0 {
+		log.Printf("Purged deleted records and removed %d files", len(filePaths))
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package tasks
+
+import (
+	"backend/models"
+	"backend/scanner"
+	"backend/storage"
+	"context"
+	"log"
+	"time"
+
+	"github.com/beego/beego/v2/server/web"
+)
+
+// Scans the quarantined attachments for malware and releases the clean ones. Infected files are
+// removed from the storage, files that cannot be scanned are rejected.
+func ScanAttachments(ctx context.Context) error {
+	batchSize := web.AppConfig.DefaultInt("scan_batch_size", 20)
+	claimTimeout := time.Duration(web.AppConfig.DefaultInt("clamav_timeout", 120)) * time.Second * 2
+	attachments, err := models.ClaimQuarantinedAttachments(batchSize, time.Now(), claimTimeout)
+	if err != nil {
+		log.Printf("Error claiming attachments to scan: %v", err)
+		return err
+	}
+
+	var clean, infected int
+	for _, attachment := range attachments {
+		status, result, err := scanAttachment(&attachment)
+		if err != nil {
+			// the claim runs out and the next run scans the file again
+			log.Printf("Error scanning attachment %d: %v", attachment.Id, err)
+			continue
+		}
+
+		if status == "infected" {
+			if err := storage.Default().Delete(attachment.FilePath); err != nil {
+				log.Printf("Error removing infected attachment %s: %v", attachment.FilePath, err)
+				continue
+			}
+			infected++
+			log.Printf("Attachment %d is infected with %s", attachment.Id, result)
+		}
+		if status == "clean" {
+			clean++
+		}
+
+		if err := models.SetAttachmentScanResult(attachment.Id, status, result); err != nil {
+			log.Printf("Error saving scan of attachment %d: %v", attachment.Id, err)
+		}
+	}
+
+	if clean > 0 || infected > 0 {
+		log.Printf("Scanned attachments: %d clean, %d infected", clean, infected)
+	}
+
+	return nil
+}
+
+// scanAttachment returns the status the attachment leaves quarantine with and the signature or reason
+func scanAttachment(attachment *models.Attachment) (string, string, error) {
+	file, err := storage.Default().Get(attachment.FilePath)
+	if err == storage.ErrNotFound {
+		return "rejected", "file not found", nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+	defer file.Close()
+
+	result, err := scanner.Default().Scan(file)
+	if err == scanner.ErrTooLarge {
+		return "rejected", "file is too large to scan", nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	if !result.Clean {
+		return "infected", result.Signature, nil
+	}
+	return "clean", "", nil
+}
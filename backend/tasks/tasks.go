@@ -0,0 +1,30 @@
+package tasks
+
+import (
+	"context"
+
+	"github.com/beego/beego/v2/task"
+)
+
+// Registers the background tasks and starts the task scheduler
+// Specs use the six field cron format: second minute hour day month weekday
+func StartTasks() {
+	task.AddTask("lift-expired-bans", task.NewTask("lift-expired-bans", "0 * * * * *", LiftExpiredBans))
+	task.AddTask("purge-deleted-records", task.NewTask("purge-deleted-records", "0 0 3 * * *", PurgeDeletedRecords))
+	task.AddTask("refresh-match-scores", task.NewTask("refresh-match-scores", "30 * * * * *", RefreshMatchScores))
+	task.AddTask("rebuild-match-scores", task.NewTask("rebuild-match-scores", "0 30 3 * * *", RebuildMatchScores))
+	task.AddTask("match-job-alerts", task.NewTask("match-job-alerts", "15 * * * * *", MatchJobAlerts))
+	task.AddTask("send-job-alert-digests", task.NewTask("send-job-alert-digests", "0 0 * * * *", SendJobAlertDigests))
+	task.AddTask("publish-scheduled-jobs", task.NewTask("publish-scheduled-jobs", "45 * * * * *", PublishScheduledJobs))
+	task.AddTask("expire-jobs", task.NewTask("expire-jobs", "50 * * * * *", ExpireJobs))
+	task.AddTask("prune-job-bookmarks", task.NewTask("prune-job-bookmarks", "0 15 3 * * *", PruneJobBookmarks))
+	task.AddTask("grant-monthly-credits", task.NewTask("grant-monthly-credits", "0 5 * * * *", GrantMonthlyCredits))
+	task.AddTask("remove-expired-uploads", task.NewTask("remove-expired-uploads", "0 20 * * * *", RemoveExpiredUploads))
+	task.AddTask("scan-attachments", task.NewTask("scan-attachments", "*/10 * * * * *", ScanAttachments))
+	task.AddTask("process-attachments", task.NewTask("process-attachments", "5/10 * * * * *", ProcessAttachments))
+
+	task.StartTask()
+
+	// Build the scores once at startup so recommendations do not wait for the nightly rebuild
+	go RebuildMatchScores(context.Background())
+}
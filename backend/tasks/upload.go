@@ -0,0 +1,32 @@
+package tasks
+
+import (
+	"backend/models"
+	"backend/storage"
+	"context"
+	"log"
+	"time"
+)
+
+// Removes the resumable uploads that were not finished or used before they expired, together with their files
+func RemoveExpiredUploads(ctx context.Context) error {
+	uploads, err := models.DeleteExpiredUploads(time.Now())
+	if err != nil {
+		log.Printf("Error removing expired uploads: %v", err)
+		return err
+	}
+
+	for _, upload := range uploads {
+		for _, key := range append(upload.PartKeys(), upload.FilePath) {
+			if err := storage.Default().Delete(key); err != nil {
+				log.Printf("Error removing upload %s: %v", key, err)
+			}
+		}
+	}
+
+	if len(uploads) > 0 {
+		log.Printf("Removed %d expired uploads", len(uploads))
+	}
+
+	return nil
+}
@@ -1,6 +1,9 @@
 package types
 
-import "time"
+import (
+	"mime/multipart"
+	"time"
+)
 
 type RegisterRequest struct {
 	Name     string `json:"name"`
@@ -28,8 +31,10 @@ type UpdateUserRequest struct {
 }
 
 type UpdateUserRequestAdmin struct {
-	Role string `json:"role"`
-	Ban  bool   `json:"ban"`
+	Role      string     `json:"role"`
+	Ban       bool       `json:"ban"`
+	BanReason string     `json:"ban_reason"`
+	BanEndsAt *time.Time `json:"ban_ends_at"`
 }
 
 type UpdateFreelancerDataRequest struct {
@@ -46,24 +51,97 @@ type UpdateClientDataRequest struct {
 	Location    string `json:"location"`
 }
 type AddDeleteSkillRequest struct {
-	SkillID int `json:"skill_id"`
+	SkillID           int    `json:"skill_id"`
+	Level             string `json:"level"`
+	YearsOfExperience int    `json:"years_of_experience"`
 }
 
 type AddUpdateSkillRequest struct {
-	SkillName string `json:"skill_name"`
+	SkillName  string `json:"skill_name"`
+	CategoryID int    `json:"category_id"`
+}
+
+type SkillCategoryRequest struct {
+	Name     string `json:"name"`
+	ParentID int    `json:"parent_id"`
+}
+
+type SkillAliasRequest struct {
+	Alias string `json:"alias"`
+}
+
+type MergeSkillRequest struct {
+	TargetSkillID int `json:"target_skill_id"`
 }
 
 type Skill struct {
-	Id   int    `json:"id"`
-	Name string `json:"name"`
+	Id                int      `json:"id"`
+	Name              string   `json:"name"`
+	CategoryID        int      `json:"category_id,omitempty"`
+	Aliases           []string `json:"aliases,omitempty"`
+	Level             string   `json:"level,omitempty"`               // freelancer skills only
+	YearsOfExperience int      `json:"years_of_experience,omitempty"` // freelancer skills only
+	Required          *bool    `json:"required,omitempty"`            // job skills only, true when omitted
+	MinLevel          string   `json:"min_level,omitempty"`           // job skills only
+}
+
+type SkillAlias struct {
+	Id      int    `json:"id"`
+	SkillID int    `json:"skill_id"`
+	Name    string `json:"name"`
+}
+
+type SkillCategory struct {
+	Id       int             `json:"id"`
+	Name     string          `json:"name"`
+	ParentID int             `json:"parent_id"`
+	Children []SkillCategory `json:"children"`
 }
 
 type FreelancerData struct {
-	Title        string  `json:"title"`
-	Description  string  `json:"description"`
-	Skills       []Skill `json:"skills"`
-	HourlyRate   float64 `json:"hourly_rate"`
-	HoursPerWeek string  `json:"hours_per_week"`
+	Title        string              `json:"title"`
+	Description  string              `json:"description"`
+	Skills       []Skill             `json:"skills"`
+	HourlyRate   float64             `json:"hourly_rate"`
+	HoursPerWeek string              `json:"hours_per_week"`
+	Portfolio    []PortfolioItemInfo `json:"portfolio,omitempty"`
+}
+
+// PortfolioItemRequest creates or replaces a portfolio item. Files are only taken on create,
+// later they are added and removed one by one.
+type PortfolioItemRequest struct {
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	Role        string             `json:"role"`
+	ProjectURL  string             `json:"project_url"`
+	SkillIDs    []int              `json:"skill_ids"`
+	StartDate   string             `json:"start_date"` // YYYY-MM-DD
+	EndDate     string             `json:"end_date"`   // YYYY-MM-DD, empty for ongoing work
+	Featured    bool               `json:"featured"`
+	Files       []AttachmentUpload `json:"files"`
+	Start       *time.Time         `json:"-"`
+	End         *time.Time         `json:"-"`
+}
+
+// PortfolioOrderRequest lists every portfolio item of the freelancer in the new order
+type PortfolioOrderRequest struct {
+	ItemIDs []int `json:"item_ids"`
+}
+
+type PortfolioItemInfo struct {
+	ID          int          `json:"id"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Role        string       `json:"role"`
+	ProjectURL  string       `json:"project_url"`
+	Skills      []Skill      `json:"skills"`
+	StartDate   string       `json:"start_date,omitempty"`
+	EndDate     string       `json:"end_date,omitempty"`
+	Featured    bool         `json:"featured"`
+	Position    int          `json:"position"`
+	Files       []Attachment `json:"files"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
 }
 
 type ClientData struct {
@@ -91,16 +169,24 @@ type UserResponseForAdmins struct {
 	Surname        string          `json:"surname"`
 	Ban            bool            `json:"ban"`
 	CreatedAt      time.Time       `json:"created_at"`
+	DeletedAt      *time.Time      `json:"deleted_at,omitempty"`
 	FreelancerData *FreelancerData `json:"freelancer_data,omitempty"`
 	ClientData     *ClientData     `json:"client_data,omitempty"`
 }
 
 type FreelancerInfo struct {
-	ID         int     `json:"id"`
-	Name       string  `json:"name"`
-	Surname    string  `json:"surname"`
-	Title      string  `json:"title"`
-	HourlyRate float64 `json:"hourly_rate"`
+	ID            int     `json:"id"`
+	Name          string  `json:"name"`
+	Surname       string  `json:"surname"`
+	Title         string  `json:"title"`
+	HourlyRate    float64 `json:"hourly_rate"`
+	TalentListIDs []int   `json:"talent_list_ids,omitempty"` // the current client's lists the freelancer is in
+}
+
+type FreelancerSkillFilter struct {
+	SkillID  int
+	MinLevel string
+	MinYears int
 }
 
 type ClientInfo struct {
@@ -111,55 +197,139 @@ type ClientInfo struct {
 }
 
 type CreateJobRequest struct {
-	Title        string   `json:"title"`
-	Description  string   `json:"description"`
-	Type         string   `json:"type"`
-	Rate         string   `json:"rate"`
-	Amount       int      `json:"amount"`
-	Length       string   `json:"length"`
-	HoursPerWeek string   `json:"hours_per_week"`
-	Skills       []*Skill `json:"skills"`
+	Title              string              `json:"title"`
+	Description        string              `json:"description"`
+	Type               string              `json:"type"`
+	Rate               string              `json:"rate"`
+	Amount             int                 `json:"amount"`
+	Length             string              `json:"length"`
+	HoursPerWeek       string              `json:"hours_per_week"`
+	Openings           int                 `json:"openings"` // defaults to 1
+	Skills             []*Skill            `json:"skills"`
+	ScreeningQuestions []ScreeningQuestion `json:"screening_questions"`
+	ExposedStages      []string            `json:"exposed_stages"` // application review stages freelancers see, none by default
+	Files              []AttachmentUpload  `json:"files"`          // spec documents of the job
+	JobVisibility
+	JobSchedule
 }
 
 type UpdateJobRequest struct {
-	Title        string   `json:"title"`
-	Description  string   `json:"description"`
-	Type         string   `json:"type"`
-	Rate         string   `json:"rate"`
-	Amount       int      `json:"amount"`
-	Length       string   `json:"length"`
-	HoursPerWeek string   `json:"hours_per_week"`
-	Skills       []*Skill `json:"skills"`
+	Title              string               `json:"title"`
+	Description        string               `json:"description"`
+	Type               string               `json:"type"`
+	Rate               string               `json:"rate"`
+	Amount             int                  `json:"amount"`
+	Length             string               `json:"length"`
+	HoursPerWeek       string               `json:"hours_per_week"`
+	Openings           int                  `json:"openings"`
+	Skills             []*Skill             `json:"skills"`
+	ScreeningQuestions *[]ScreeningQuestion `json:"screening_questions"` // replaces the questions, empty list to remove them
+	ExposedStages      *[]string            `json:"exposed_stages"`
+	JobVisibility
+}
+
+// ScreeningQuestion is a question freelancers answer when they apply to a job
+type ScreeningQuestion struct {
+	ID       int      `json:"id,omitempty"`
+	Type     string   `json:"type"` // text, yes-no, multiple-choice, number
+	Question string   `json:"question"`
+	Options  []string `json:"options,omitempty"` // the choices of multiple-choice questions
+	Required bool     `json:"required"`
+}
+
+type ScreeningAnswer struct {
+	QuestionID int    `json:"question_id"`
+	Question   string `json:"question,omitempty"`
+	Answer     string `json:"answer"` // yes-no questions are answered with "yes" or "no"
+}
+
+// ScreeningAnswerFilter keeps the applicants whose answer to the question matches
+type ScreeningAnswerFilter struct {
+	QuestionID int
+	Value      string   // the answer, text answers only have to contain it
+	Min        *float64 // number questions
+	Max        *float64
+}
+
+// JobVisibility is who can see and apply to a job, restricted jobs are limited to the freelancers
+// with the skill at the minimum level and with the minimum number of completed jobs
+type JobVisibility struct {
+	Visibility           string `json:"visibility"` // public, invite-only, restricted
+	RestrictedSkillID    int    `json:"restricted_skill_id,omitempty"`
+	RestrictedSkillLevel string `json:"restricted_skill_level,omitempty"`
+	MinCompletedJobs     int    `json:"min_completed_jobs,omitempty"`
+}
+
+// JobSchedule is when a job opens and expires. Drafts are not published until the client publishes them,
+// jobs with a future publish time are scheduled and the others open right away.
+type JobSchedule struct {
+	Draft     bool       `json:"draft"`
+	PublishAt *time.Time `json:"publish_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type PublishJobRequest struct {
+	PublishAt *time.Time `json:"publish_at"` // empty or past to publish now
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type JobExpiryRequest struct {
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type JobStatusChangeRequest struct {
+	Reason       string `json:"reason"`
+	FreelancerID int    `json:"freelancer_id"` // reopen: the freelancer leaving the job, empty for every hired freelancer
+}
+
+type JobStatusHistoryInfo struct {
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Event      string    `json:"event"`
+	ActorID    int       `json:"actor_id"`
+	Reason     string    `json:"reason"`
+	CreatedAt  time.Time `json:"created_at"`
 }
 
 type JobInfo struct {
-	ID            int     `json:"id"`
-	Title         string  `json:"title"`
-	Description   string  `json:"description"`
-	Type          string  `json:"type"`
-	Rate          string  `json:"rate"`
-	Amount        int     `json:"amount"`
-	Length        string  `json:"length"`
-	HoursPerWeek  string  `json:"hours_per_week"`
-	ClientID      int     `json:"client_id"`
-	Skills        []Skill `json:"skills"`
-	ApplicationID int     `json:"application_id"`
+	ID                 int                 `json:"id"`
+	Title              string              `json:"title"`
+	Description        string              `json:"description"`
+	Type               string              `json:"type"`
+	Rate               string              `json:"rate"`
+	Amount             int                 `json:"amount"`
+	Length             string              `json:"length"`
+	HoursPerWeek       string              `json:"hours_per_week"`
+	Openings           int                 `json:"openings"`
+	ClientID           int                 `json:"client_id"`
+	Skills             []Skill             `json:"skills"`
+	ApplicationID      int                 `json:"application_id"`
+	Bookmarked         bool                `json:"bookmarked"`
+	ExpiresAt          *time.Time          `json:"expires_at,omitempty"`
+	ScreeningQuestions []ScreeningQuestion `json:"screening_questions,omitempty"`
+	CreditCost         int                 `json:"credit_cost,omitempty"` // credits an application costs, only shown on the job page
+	Attachments        []Attachment        `json:"attachments,omitempty"` // only shown on the job page
 }
 
 type ClientJobInfo struct {
-	ID               int     `json:"id"`
-	Title            string  `json:"title"`
-	Description      string  `json:"description"`
-	Type             string  `json:"type"`
-	Rate             string  `json:"rate"`
-	Amount           int     `json:"amount"`
-	Length           string  `json:"length"`
-	HoursPerWeek     string  `json:"hours_per_week"`
-	Status           string  `json:"status"`
-	ClientID         int     `json:"client_id"`
-	FreelancerID     int     `json:"freelancer_id"`
-	Skills           []Skill `json:"skills"`
-	ApplicationCount int     `json:"application_count"`
+	ID               int        `json:"id"`
+	Title            string     `json:"title"`
+	Description      string     `json:"description"`
+	Type             string     `json:"type"`
+	Rate             string     `json:"rate"`
+	Amount           int        `json:"amount"`
+	Length           string     `json:"length"`
+	HoursPerWeek     string     `json:"hours_per_week"`
+	Openings         int        `json:"openings"`
+	Status           string     `json:"status"`
+	ClientID         int        `json:"client_id"`
+	FreelancerIDs    []int      `json:"freelancer_ids"`
+	Skills           []Skill    `json:"skills"`
+	ApplicationCount int        `json:"application_count"`
+	PublishAt        *time.Time `json:"publish_at"`
+	ExpiresAt        *time.Time `json:"expires_at"`
+	DeletedAt        *time.Time `json:"deleted_at,omitempty"`
+	JobVisibility
 }
 
 type FreelancerJobInfo struct {
@@ -171,6 +341,7 @@ type FreelancerJobInfo struct {
 	Amount        int     `json:"amount"`
 	Length        string  `json:"length"`
 	HoursPerWeek  string  `json:"hours_per_week"`
+	Openings      int     `json:"openings"`
 	Status        string  `json:"status"`
 	ClientID      int     `json:"client_id"`
 	Skills        []Skill `json:"skills"`
@@ -178,55 +349,458 @@ type FreelancerJobInfo struct {
 }
 
 type ClientJobDetailedInfo struct {
-	ID           int           `json:"id"`
-	Title        string        `json:"title"`
-	Description  string        `json:"description"`
-	Type         string        `json:"type"`
-	Rate         string        `json:"rate"`
-	Amount       int           `json:"amount"`
-	Length       string        `json:"length"`
-	HoursPerWeek string        `json:"hours_per_week"`
-	Status       string        `json:"status"`
-	ClientID     int           `json:"client_id"`
-	FreelancerID int           `json:"freelancer_id"`
-	Skills       []Skill       `json:"skills"`
-	Applications []Application `json:"applications"`
+	ID                   int                       `json:"id"`
+	Title                string                    `json:"title"`
+	Description          string                    `json:"description"`
+	Type                 string                    `json:"type"`
+	Rate                 string                    `json:"rate"`
+	Amount               int                       `json:"amount"`
+	Length               string                    `json:"length"`
+	HoursPerWeek         string                    `json:"hours_per_week"`
+	Openings             int                       `json:"openings"`
+	Status               string                    `json:"status"`
+	ClientID             int                       `json:"client_id"`
+	FreelancerIDs        []int                     `json:"freelancer_ids"`
+	Skills               []Skill                   `json:"skills"`
+	Applications         []Application             `json:"applications"`
+	Invitations          []JobInvitationInfo       `json:"invitations"`
+	ScreeningQuestions   []ScreeningQuestion       `json:"screening_questions"`
+	ExposedStages        []string                  `json:"exposed_stages"`
+	CancellationRequests []CancellationRequestInfo `json:"cancellation_requests"`
+	StatusHistory        []JobStatusHistoryInfo    `json:"status_history"`
+	Attachments          []Attachment              `json:"attachments"`
+	PublishAt            *time.Time                `json:"publish_at"`
+	ExpiresAt            *time.Time                `json:"expires_at"`
+	JobVisibility
 }
 
 type Application struct {
-	ID              int         `json:"id"`
-	UserID          int         `json:"user_id"`
-	JobID           int         `json:"job_id"`
-	JobTitle        string      `json:"job_title,omitempty"`
-	Description     string      `json:"description"`
-	RejectionReason string      `json:"rejection_reason"`
-	Status          string      `json:"status"`
-	CreatedAt       time.Time   `json:"created_at"`
-	Attachment      *Attachment `json:"attachment,omitempty"`
+	ID                  int                      `json:"id"`
+	UserID              int                      `json:"user_id"`
+	JobID               int                      `json:"job_id"`
+	JobTitle            string                   `json:"job_title,omitempty"`
+	Description         string                   `json:"description"`
+	RejectionReason     string                   `json:"rejection_reason"`
+	Status              string                   `json:"status"`
+	CreatedAt           time.Time                `json:"created_at"`
+	Attachment          *Attachment              `json:"attachment,omitempty"` // the first attachment, kept for older clients
+	Attachments         []Attachment             `json:"attachments"`
+	Answers             []ScreeningAnswer        `json:"answers,omitempty"`
+	Notes               string                   `json:"notes,omitempty"` // the client's private notes, only shown to the client
+	WithdrawalReason    string                   `json:"withdrawal_reason,omitempty"`
+	WithdrawnAt         *time.Time               `json:"withdrawn_at,omitempty"`
+	CancellationRequest *CancellationRequestInfo `json:"cancellation_request,omitempty"` // the freelancer's pending request to leave the job
+	DeletedAt           *time.Time               `json:"deleted_at,omitempty"`
 }
 
 type Attachment struct {
-	ID            int       `json:"id"`
-	ApplicationID int       `json:"application_id"`
-	FileName      string    `json:"file_name"`
-	FilePath      string    `json:"file_path"`
-	CreatedAt     time.Time `json:"created_at"`
+	ID              int       `json:"id"`
+	ApplicationID   int       `json:"application_id,omitempty"`
+	JobID           int       `json:"job_id,omitempty"`
+	PortfolioItemID int       `json:"portfolio_item_id,omitempty"`
+	FileName        string    `json:"file_name"`
+	FilePath        string    `json:"file_path"`
+	Size            int64     `json:"size"`
+	Status          string    `json:"status"`         // quarantined until scanned clean, then clean, infected or rejected
+	PreviewStatus   string    `json:"preview_status"` // pending until the text and thumbnail are extracted, then ready or failed
+	PageCount       int       `json:"page_count"`
+	ThumbnailURL    string    `json:"thumbnail_url,omitempty"`
+	Highlights      []string  `json:"highlights,omitempty"` // snippets matching the ?q= keywords, HTML escaped with the keywords in <mark>
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// AttachmentUpload is a file of a request: base64 encoded in JSON, a finished resumable upload
+// referenced by its ID, or a part of a multipart/form-data request
+type AttachmentUpload struct {
+	FileName   string                `json:"file_name"`
+	FileBase64 string                `json:"file_base64"`
+	UploadID   int                   `json:"upload_id"`
+	Part       *multipart.FileHeader `json:"-"`
 }
 
 type SubmitApplicationRequest struct {
-	JobID       int    `json:"job_id"`
-	Description string `json:"description"`
-	FileName    string `json:"file_name"`
-	FileBase64  string `json:"file_base64"`
+	JobID       int                `json:"job_id"`
+	Description string             `json:"description"`
+	FileName    string             `json:"file_name"` // single file of older clients, added to Files
+	FileBase64  string             `json:"file_base64"`
+	Files       []AttachmentUpload `json:"files"`
+	Answers     []ScreeningAnswer  `json:"answers"`
 }
 
+// UpdateApplicationRequest replaces the attachments with the file when one is given,
+// attachments are added and removed one at a time with the attachment endpoints
 type UpdateApplicationRequest struct {
-	Description string `json:"description"`
-	FileName    string `json:"file_name"`
-	FileBase64  string `json:"file_base64"`
+	Description string            `json:"description"`
+	FileName    string            `json:"file_name"`
+	FileBase64  string            `json:"file_base64"`
+	File        *AttachmentUpload `json:"-"` // the file of either request format
+}
+
+type CreateUploadRequest struct {
+	FileName string
+	Length   int64
+}
+
+type UploadInfo struct {
+	ID        int       `json:"id"`
+	FileName  string    `json:"file_name"`
+	Length    int64     `json:"length"`
+	Offset    int64     `json:"offset"`
+	Status    string    `json:"status"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
 type ChangeApplicationStatusRequest struct {
 	Status          string `json:"status"`
 	RejectionReason string `json:"rejection_reason"`
 }
+
+type WithdrawApplicationRequest struct {
+	Reason string `json:"reason"`
+}
+
+type CancellationRequestInfo struct {
+	ID             int        `json:"id"`
+	ApplicationID  int        `json:"application_id"`
+	FreelancerID   int        `json:"freelancer_id"`
+	Reason         string     `json:"reason"`
+	Status         string     `json:"status"`
+	CreatedAt      time.Time  `json:"created_at"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at"`
+}
+
+type ApplicationNotesRequest struct {
+	Notes string `json:"notes"`
+}
+
+// PipelineStage is a column of the applicants board of a job
+type PipelineStage struct {
+	Stage        string        `json:"stage"`
+	Applications []Application `json:"applications"`
+}
+
+// OfferTerms are the terms of an offer or counter-offer
+type OfferTerms struct {
+	Rate       string           `json:"rate"`
+	Amount     int              `json:"amount"`
+	StartDate  *time.Time       `json:"start_date"`
+	Milestones []OfferMilestone `json:"milestones"` // milestone amounts of fixed rate offers add up to the amount
+	Message    string           `json:"message"`
+}
+
+type OfferMilestone struct {
+	Title   string     `json:"title"`
+	Amount  int        `json:"amount"`
+	DueDate *time.Time `json:"due_date"`
+}
+
+type OfferInfo struct {
+	ID            int              `json:"id"`
+	ApplicationID int              `json:"application_id"`
+	Round         int              `json:"round"`
+	ProposedBy    string           `json:"proposed_by"`
+	Rate          string           `json:"rate"`
+	Amount        int              `json:"amount"`
+	StartDate     *time.Time       `json:"start_date"`
+	Milestones    []OfferMilestone `json:"milestones"`
+	Message       string           `json:"message"`
+	Status        string           `json:"status"`
+	CreatedAt     time.Time        `json:"created_at"`
+	RespondedAt   *time.Time       `json:"responded_at"`
+}
+
+type CreateBanRequest struct {
+	Reason string     `json:"reason"`
+	EndsAt *time.Time `json:"ends_at"` // omitted for a permanent ban
+}
+
+type BanInfo struct {
+	ID        int        `json:"id"`
+	UserID    int        `json:"user_id"`
+	AdminID   int        `json:"admin_id"`
+	Reason    string     `json:"reason"`
+	StartsAt  time.Time  `json:"starts_at"`
+	EndsAt    *time.Time `json:"ends_at"`
+	LiftedAt  *time.Time `json:"lifted_at"`
+	Active    bool       `json:"active"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type BannedUserResponse struct {
+	Error       string     `json:"error"`
+	BanID       int        `json:"ban_id,omitempty"`
+	Reason      string     `json:"reason"`
+	BannedUntil *time.Time `json:"banned_until"`
+	Permanent   bool       `json:"permanent"`
+}
+
+type SubmitBanAppealRequest struct {
+	Message string `json:"message"`
+}
+
+type ReviewBanAppealRequest struct {
+	Status   string `json:"status"`
+	Response string `json:"response"`
+}
+
+type BanAppealInfo struct {
+	ID            int        `json:"id"`
+	BanID         int        `json:"ban_id"`
+	UserID        int        `json:"user_id"`
+	Message       string     `json:"message"`
+	Status        string     `json:"status"`
+	AdminResponse string     `json:"admin_response"`
+	ReviewedByID  int        `json:"reviewed_by_id"`
+	ReviewedAt    *time.Time `json:"reviewed_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	Ban           *BanInfo   `json:"ban,omitempty"`
+}
+
+type CreateReportRequest struct {
+	TargetType  string `json:"target_type"`
+	TargetID    int    `json:"target_id"`
+	Category    string `json:"category"`
+	Description string `json:"description"`
+}
+
+type ResolveReportRequest struct {
+	Status     string     `json:"status"` // resolved, dismissed
+	Action     string     `json:"action"`
+	Resolution string     `json:"resolution"`
+	BanEndsAt  *time.Time `json:"ban_ends_at"` // only used by the 'ban-user' action
+}
+
+type ReportInfo struct {
+	ID               int        `json:"id"`
+	ReporterID       int        `json:"reporter_id"`
+	TargetType       string     `json:"target_type"`
+	TargetID         int        `json:"target_id"`
+	Category         string     `json:"category"`
+	Description      string     `json:"description"`
+	Status           string     `json:"status"`
+	Action           string     `json:"action"`
+	Resolution       string     `json:"resolution"`
+	ResolvedByID     int        `json:"resolved_by_id"`
+	ResolvedAt       *time.Time `json:"resolved_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+	OpenReportsCount int        `json:"open_reports_count,omitempty"` // open reports on the same target, admin only
+}
+
+type StatsQuery struct {
+	Interval string
+	From     time.Time
+	To       time.Time
+	Limit    int
+}
+
+type CountBucket struct {
+	Bucket time.Time `json:"bucket"`
+	Count  int       `json:"count"`
+}
+
+type RegistrationsBucket struct {
+	Bucket     time.Time `json:"bucket"`
+	Client     int       `json:"client"`
+	Freelancer int       `json:"freelancer"`
+	Admin      int       `json:"admin"`
+}
+
+type JobsBucket struct {
+	Bucket     time.Time `json:"bucket"`
+	Total      int       `json:"total"`
+	Open       int       `json:"open"`
+	InProgress int       `json:"in_progress"`
+	Completed  int       `json:"completed"`
+}
+
+type SkillCount struct {
+	Id    int    `json:"id"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type AdminStatsResponse struct {
+	Interval              string                `json:"interval"`
+	From                  time.Time             `json:"from"`
+	To                    time.Time             `json:"to"`
+	UsersByRole           map[string]int        `json:"users_by_role"`
+	JobsByStatus          map[string]int        `json:"jobs_by_status"`
+	BannedUsers           int                   `json:"banned_users"`
+	AcceptanceRate        *float64              `json:"acceptance_rate"`           // accepted / decided applications submitted in the range
+	MedianTimeToHireHours *float64              `json:"median_time_to_hire_hours"` // nil when there were no hires in the range
+	Registrations         []RegistrationsBucket `json:"registrations"`
+	JobsPosted            []JobsBucket          `json:"jobs_posted"`
+	ApplicationsSubmitted []CountBucket         `json:"applications_submitted"`
+	Hires                 []CountBucket         `json:"hires"`
+	BansIssued            []CountBucket         `json:"bans_issued"`
+	TopSkillsDemanded     []SkillCount          `json:"top_skills_demanded"`
+	TopSkillsOffered      []SkillCount          `json:"top_skills_offered"`
+}
+
+type MatchBreakdown struct {
+	Skills  float64 `json:"skills"`
+	Rate    float64 `json:"rate"`
+	Hours   float64 `json:"hours"`
+	History float64 `json:"history"`
+}
+
+type RecommendedJob struct {
+	Job       JobInfo        `json:"job"`
+	Score     float64        `json:"score"`
+	Breakdown MatchBreakdown `json:"breakdown"`
+	Reasons   []string       `json:"reasons"`
+}
+
+type RecommendedFreelancer struct {
+	Freelancer FreelancerInfo `json:"freelancer"`
+	Score      float64        `json:"score"`
+	Breakdown  MatchBreakdown `json:"breakdown"`
+	Reasons    []string       `json:"reasons"`
+}
+
+type JobSearchFilter struct {
+	SkillIDs  []int  `json:"skill_ids"`
+	Rate      string `json:"rate"`
+	MinAmount int    `json:"min_amount"`
+	MaxAmount int    `json:"max_amount"`
+	Keywords  string `json:"keywords"`
+}
+
+type SavedSearchRequest struct {
+	Name      string `json:"name"`
+	SkillIDs  []int  `json:"skill_ids"`
+	Rate      string `json:"rate"`
+	MinAmount int    `json:"min_amount"`
+	MaxAmount int    `json:"max_amount"`
+	Keywords  string `json:"keywords"`
+	Frequency string `json:"frequency"`
+	Channel   string `json:"channel"`
+}
+
+type SavedSearchInfo struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Skills     []Skill    `json:"skills"`
+	Rate       string     `json:"rate"`
+	MinAmount  int        `json:"min_amount"`
+	MaxAmount  int        `json:"max_amount"`
+	Keywords   string     `json:"keywords"`
+	Frequency  string     `json:"frequency"`
+	Channel    string     `json:"channel"`
+	LastSentAt *time.Time `json:"last_sent_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type NotificationInfo struct {
+	ID        int        `json:"id"`
+	Type      string     `json:"type"`
+	Title     string     `json:"title"`
+	Message   string     `json:"message"`
+	Link      string     `json:"link"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type CreditTransactionInfo struct {
+	ID            int       `json:"id"`
+	Type          string    `json:"type"` // allowance, application, refund, grant
+	Amount        int       `json:"amount"`
+	Balance       int       `json:"balance"`
+	JobID         int       `json:"job_id,omitempty"`
+	ApplicationID int       `json:"application_id,omitempty"`
+	Note          string    `json:"note,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type CreditsResponse struct {
+	Balance          int                     `json:"balance"`
+	MonthlyAllowance int                     `json:"monthly_allowance"`
+	Transactions     []CreditTransactionInfo `json:"transactions"`
+}
+
+type GrantCreditsRequest struct {
+	Amount int    `json:"amount"`
+	Note   string `json:"note"`
+}
+
+type NotificationsResponse struct {
+	UnreadCount   int                `json:"unread_count"`
+	Notifications []NotificationInfo `json:"notifications"`
+}
+
+type MarkNotificationsReadRequest struct {
+	IDs []int `json:"ids"` // empty marks every notification as read
+}
+
+type JobBookmarkRequest struct {
+	JobID int `json:"job_id"`
+}
+
+type JobBookmarkInfo struct {
+	Job       JobInfo   `json:"job"`
+	Status    string    `json:"status"`
+	Available bool      `json:"available"` // false once the job is no longer open or was deleted
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type TalentListRequest struct {
+	Name string `json:"name"`
+}
+
+type TalentListEntryRequest struct {
+	FreelancerID int    `json:"freelancer_id"`
+	Note         string `json:"note"`
+}
+
+type TalentListInfo struct {
+	ID              int       `json:"id"`
+	Name            string    `json:"name"`
+	FreelancerCount int       `json:"freelancer_count"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type TalentListEntryInfo struct {
+	Freelancer FreelancerInfo `json:"freelancer"`
+	Note       string         `json:"note"`
+	AddedAt    time.Time      `json:"added_at"`
+}
+
+type TalentListDetailedInfo struct {
+	ID          int                   `json:"id"`
+	Name        string                `json:"name"`
+	CreatedAt   time.Time             `json:"created_at"`
+	Freelancers []TalentListEntryInfo `json:"freelancers"`
+}
+
+type JobInvitationRequest struct {
+	FreelancerID int    `json:"freelancer_id"`
+	Message      string `json:"message"`
+}
+
+type AcceptJobInvitationRequest struct {
+	Description string            `json:"description"` // description of the application created for the invitation
+	Answers     []ScreeningAnswer `json:"answers"`
+}
+
+type JobInvitationInfo struct {
+	ID                int        `json:"id"`
+	FreelancerID      int        `json:"freelancer_id"`
+	FreelancerName    string     `json:"freelancer_name"`
+	FreelancerSurname string     `json:"freelancer_surname"`
+	Message           string     `json:"message"`
+	Status            string     `json:"status"`
+	ApplicationID     int        `json:"application_id"`
+	CreatedAt         time.Time  `json:"created_at"`
+	RespondedAt       *time.Time `json:"responded_at"`
+}
+
+type FreelancerInvitationInfo struct {
+	ID            int        `json:"id"`
+	Job           JobInfo    `json:"job"`
+	JobStatus     string     `json:"job_status"`
+	Message       string     `json:"message"`
+	Status        string     `json:"status"`
+	ApplicationID int        `json:"application_id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	RespondedAt   *time.Time `json:"responded_at"`
+}
@@ -25,3 +25,99 @@ var ValidProjectHoursPerWeek = map[string]bool{
 	"60-80": true,
 	"80+":   true,
 }
+
+var ValidJobVisibilities = map[string]bool{
+	"public":      true,
+	"invite-only": true,
+	"restricted":  true,
+}
+
+var ValidApplicationStatuses = map[string]bool{
+	"pending":        true,
+	"shortlisted":    true,
+	"interviewing":   true,
+	"offer-sent":     true,
+	"offer-declined": true,
+	"accepted":       true,
+	"rejected":       true,
+	"withdrawn":      true,
+}
+
+// ValidExposedStages are the review stages clients can show to freelancers
+var ValidExposedStages = map[string]bool{
+	"shortlisted":  true,
+	"interviewing": true,
+}
+
+var ValidScreeningQuestionTypes = map[string]bool{
+	"text":            true,
+	"yes-no":          true,
+	"multiple-choice": true,
+	"number":          true,
+}
+
+var ValidSkillLevels = map[string]bool{
+	"beginner":     true,
+	"intermediate": true,
+	"advanced":     true,
+	"expert":       true,
+}
+
+var ValidBanAppealReviewStatuses = map[string]bool{
+	"approved": true,
+	"rejected": true,
+}
+
+var ValidReportTargetTypes = map[string]bool{
+	"job":         true,
+	"application": true,
+	"user":        true,
+}
+
+var ValidReportCategories = map[string]bool{
+	"scam":          true,
+	"spam":          true,
+	"abuse":         true,
+	"fake-profile":  true,
+	"inappropriate": true,
+	"other":         true,
+}
+
+var ValidReportStatuses = map[string]bool{
+	"open":      true,
+	"resolved":  true,
+	"dismissed": true,
+}
+
+var ValidReportActions = map[string]bool{
+	"none":               true,
+	"remove-job":         true,
+	"remove-application": true,
+	"ban-user":           true,
+}
+
+var ValidStatsIntervals = map[string]bool{
+	"day":   true,
+	"week":  true,
+	"month": true,
+}
+
+var ValidAlertFrequencies = map[string]bool{
+	"instant": true,
+	"daily":   true,
+	"weekly":  true,
+}
+
+var ValidAlertChannels = map[string]bool{
+	"in-app": true,
+	"email":  true,
+}
+
+// ValidPortfolioFileTypes maps the extensions of portfolio item files to their content type
+var ValidPortfolioFileTypes = map[string]string{
+	".pdf":  "application/pdf",
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".gif":  "image/gif",
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"fmt"
+	"log"
+	"mime"
+	"net/smtp"
+	"strings"
+
+	"github.com/beego/beego/v2/server/web"
+)
+
+// SendEmail sends a plain text email through the SMTP server from the configuration.
+// Without an smtp_host the email is only logged, which is what development setups use.
+// The subject is encoded, so that user text in it can neither add headers nor break on non-ASCII.
+func SendEmail(to, subject, body string) error {
+	host := web.AppConfig.DefaultString("smtp_host", "")
+	if host == "" {
+		log.Printf("Email to %s not sent, smtp_host is not configured: %s", to, subject)
+		return nil
+	}
+
+	port := web.AppConfig.DefaultInt("smtp_port", 587)
+	user := web.AppConfig.DefaultString("smtp_user", "")
+	password := web.AppConfig.DefaultString("smtp_password", "")
+	from := web.AppConfig.DefaultString("smtp_from", "no-reply@freelance.local")
+
+	var auth smtp.Auth
+	if user != "" {
+		auth = smtp.PlainAuth("", user, password, host)
+	}
+
+	message := strings.Join([]string{
+		"From: " + from,
+		"To: " + to,
+		"Subject: " + mime.QEncoding.Encode("utf-8", subject),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body,
+	}, "\r\n")
+
+	return smtp.SendMail(fmt.Sprintf("%s:%d", host, port), auth, from, []string{to}, []byte(message))
+}
@@ -2,11 +2,25 @@ package validators
 
 import (
 	"backend/types"
+	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/go-passwd/validator"
 )
@@ -154,6 +168,13 @@ func UpdateUserValidatorAdmin(requestBody []byte) (*types.UpdateUserRequestAdmin
 		return nil, fmt.Errorf("Invalid role: %s. Role must be 'client', 'freelancer' or 'admin' ", updateUserRequest.Role)
 	}
 
+	if len(updateUserRequest.BanReason) > 1000 {
+		return nil, fmt.Errorf("Ban reason cannot be longer than 1000 symbols")
+	}
+	if updateUserRequest.BanEndsAt != nil && !updateUserRequest.BanEndsAt.After(time.Now()) {
+		return nil, fmt.Errorf("Ban end time must be in the future")
+	}
+
 	return updateUserRequest, nil
 
 }
@@ -256,6 +277,13 @@ func AddDeleteSkillValidator(requestBody []byte) (*types.AddDeleteSkillRequest,
 		return nil, fmt.Errorf("Invalid input")
 	}
 
+	if addDeleteSkillRequest.Level != "" && !types.ValidSkillLevels[addDeleteSkillRequest.Level] {
+		return nil, errors.New("invalid skill level: must be 'beginner', 'intermediate', 'advanced' or 'expert'")
+	}
+	if addDeleteSkillRequest.YearsOfExperience < 0 || addDeleteSkillRequest.YearsOfExperience > 60 {
+		return nil, errors.New("years of experience must be between 0 and 60")
+	}
+
 	return addDeleteSkillRequest, nil
 
 }
@@ -277,6 +305,9 @@ func AddUpdateSkillValidator(requestBody []byte) (*types.AddUpdateSkillRequest,
 	if len(addUpdateSkillRequest.SkillName) > 50 {
 		return nil, fmt.Errorf("Skill name cannot be more than 50 symbols")
 	}
+	if addUpdateSkillRequest.CategoryID < 0 {
+		return nil, fmt.Errorf("Category ID must be a positive integer")
+	}
 
 	return addUpdateSkillRequest, nil
 
@@ -336,6 +367,36 @@ func CreateJobValidator(requestBody []byte) (*types.CreateJobRequest, error) {
 		return nil, errors.New("invalid hours per week: must be '<20', '20-40', '40-60', '60-80' or '80+'")
 	}
 
+	if createJobRequest.Openings == 0 {
+		createJobRequest.Openings = 1
+	}
+	if createJobRequest.Openings < 1 || createJobRequest.Openings > 20 {
+		return nil, errors.New("openings must be between 1 and 20")
+	}
+
+	if createJobRequest.Visibility == "" {
+		createJobRequest.Visibility = "public"
+	}
+	if err := validateJobVisibility(&createJobRequest.JobVisibility); err != nil {
+		return nil, err
+	}
+	if err := validateJobSchedule(createJobRequest.PublishAt, createJobRequest.ExpiresAt); err != nil {
+		return nil, err
+	}
+	if err := validateScreeningQuestions(createJobRequest.ScreeningQuestions); err != nil {
+		return nil, err
+	}
+	if err := validateExposedStages(createJobRequest.ExposedStages); err != nil {
+		return nil, err
+	}
+	if err := validateAttachmentUploads(createJobRequest.Files); err != nil {
+		return nil, err
+	}
+
+	if err := validateJobSkills(createJobRequest.Skills); err != nil {
+		return nil, err
+	}
+
 	return createJobRequest, nil
 
 }
@@ -394,10 +455,97 @@ func UpdateJobValidator(requestBody []byte) (*types.UpdateJobRequest, error) {
 		}
 	}
 
+	if updateJobRequest.Openings != 0 {
+		if updateJobRequest.Openings < 1 || updateJobRequest.Openings > 20 {
+			return nil, errors.New("openings must be between 1 and 20")
+		}
+	}
+
+	if updateJobRequest.Visibility != "" {
+		if err := validateJobVisibility(&updateJobRequest.JobVisibility); err != nil {
+			return nil, err
+		}
+	}
+	if updateJobRequest.ScreeningQuestions != nil {
+		if err := validateScreeningQuestions(*updateJobRequest.ScreeningQuestions); err != nil {
+			return nil, err
+		}
+	}
+	if updateJobRequest.ExposedStages != nil {
+		if err := validateExposedStages(*updateJobRequest.ExposedStages); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := validateJobSkills(updateJobRequest.Skills); err != nil {
+		return nil, err
+	}
+
 	return updateJobRequest, nil
 
 }
 
+func validateJobVisibility(visibility *types.JobVisibility) error {
+	if !types.ValidJobVisibilities[visibility.Visibility] {
+		return errors.New("invalid visibility: must be 'public', 'invite-only' or 'restricted'")
+	}
+
+	if visibility.Visibility != "restricted" {
+		if visibility.RestrictedSkillID != 0 || visibility.RestrictedSkillLevel != "" || visibility.MinCompletedJobs != 0 {
+			return errors.New("restrictions can only be set on restricted jobs")
+		}
+		return nil
+	}
+
+	if visibility.RestrictedSkillID == 0 && visibility.MinCompletedJobs == 0 {
+		return errors.New("restricted jobs need a restricted skill or a minimum number of completed jobs")
+	}
+	if visibility.RestrictedSkillID < 0 {
+		return errors.New("invalid restricted skill: id must be a positive integer")
+	}
+	if visibility.RestrictedSkillLevel != "" {
+		if visibility.RestrictedSkillID == 0 {
+			return errors.New("restricted skill level requires a restricted skill")
+		}
+		if !types.ValidSkillLevels[visibility.RestrictedSkillLevel] {
+			return errors.New("invalid restricted skill level: must be 'beginner', 'intermediate', 'advanced' or 'expert'")
+		}
+	}
+	if visibility.MinCompletedJobs < 0 || visibility.MinCompletedJobs > 1000 {
+		return errors.New("minimum completed jobs must be between 0 and 1000")
+	}
+
+	return nil
+}
+
+// validateJobSchedule checks that the job expires in the future and after it is published
+func validateJobSchedule(publishAt, expiresAt *time.Time) error {
+	if expiresAt == nil {
+		return nil
+	}
+
+	if !expiresAt.After(time.Now()) {
+		return errors.New("expiry must be in the future")
+	}
+	if publishAt != nil && !expiresAt.After(*publishAt) {
+		return errors.New("expiry must be after the publish time")
+	}
+
+	return nil
+}
+
+func validateJobSkills(skills []*types.Skill) error {
+	for _, skill := range skills {
+		if skill == nil || skill.Id <= 0 {
+			return errors.New("invalid skill: id must be a positive integer")
+		}
+		if skill.MinLevel != "" && !types.ValidSkillLevels[skill.MinLevel] {
+			return errors.New("invalid minimum skill level: must be 'beginner', 'intermediate', 'advanced' or 'expert'")
+		}
+	}
+	return nil
+}
+
 func SubmitApplicationValidator(requestBody []byte) (*types.SubmitApplicationRequest, error) {
 
 	var submitApplicationRequest = new(types.SubmitApplicationRequest)
@@ -408,29 +556,69 @@ func SubmitApplicationValidator(requestBody []byte) (*types.SubmitApplicationReq
 		return nil, fmt.Errorf("Invalid input")
 	}
 
-	if submitApplicationRequest.JobID <= 0 {
-		return nil, fmt.Errorf("Job ID must be a positive integer")
+	// the single file of older clients is uploaded like the others
+	if submitApplicationRequest.FileName != "" || submitApplicationRequest.FileBase64 != "" {
+		submitApplicationRequest.Files = append([]types.AttachmentUpload{{
+			FileName:   submitApplicationRequest.FileName,
+			FileBase64: submitApplicationRequest.FileBase64,
+		}}, submitApplicationRequest.Files...)
 	}
 
-	if submitApplicationRequest.Description == "" {
-		return nil, fmt.Errorf("Missing required fields: description")
+	if err := validateSubmitApplication(submitApplicationRequest); err != nil {
+		return nil, err
+	}
+
+	return submitApplicationRequest, nil
+}
+
+// SubmitApplicationFormValidator reads an application sent as multipart/form-data. The files are
+// "files" parts and the answers are a JSON list in the "answers" field.
+func SubmitApplicationFormValidator(form *multipart.Form) (*types.SubmitApplicationRequest, error) {
+	if form == nil {
+		return nil, fmt.Errorf("Invalid input")
 	}
-	if submitApplicationRequest.FileBase64 != "" && submitApplicationRequest.FileName == "" {
-		return nil, fmt.Errorf("File name cannot be empty")
+
+	jobID, err := strconv.Atoi(formValue(form, "job_id"))
+	if err != nil {
+		return nil, fmt.Errorf("Job ID must be a positive integer")
 	}
-	if submitApplicationRequest.FileBase64 == "" && submitApplicationRequest.FileName != "" {
-		return nil, fmt.Errorf("File cannot be empty")
+
+	submitApplicationRequest := &types.SubmitApplicationRequest{
+		JobID:       jobID,
+		Description: formValue(form, "description"),
+		Files:       formFiles(form, "files"),
 	}
 
-	if submitApplicationRequest.FileName != "" {
-		if !strings.HasSuffix(strings.ToLower(submitApplicationRequest.FileName), ".pdf") {
-			return nil, fmt.Errorf("Only PDF files are allowed")
+	if answers := formValue(form, "answers"); answers != "" {
+		if err := json.Unmarshal([]byte(answers), &submitApplicationRequest.Answers); err != nil {
+			fmt.Println("Error parsing answers:", err)
+			return nil, fmt.Errorf("Invalid input")
 		}
 	}
 
+	if err := validateSubmitApplication(submitApplicationRequest); err != nil {
+		return nil, err
+	}
+
 	return submitApplicationRequest, nil
 }
 
+func validateSubmitApplication(submitApplicationRequest *types.SubmitApplicationRequest) error {
+	if submitApplicationRequest.JobID <= 0 {
+		return fmt.Errorf("Job ID must be a positive integer")
+	}
+
+	if submitApplicationRequest.Description == "" {
+		return fmt.Errorf("Missing required fields: description")
+	}
+
+	if err := validateAttachmentUploads(submitApplicationRequest.Files); err != nil {
+		return err
+	}
+
+	return validateScreeningAnswerList(submitApplicationRequest.Answers)
+}
+
 func UpdateApplicationValidator(requestBody []byte) (*types.UpdateApplicationRequest, error) {
 
 	var updateApplicationRequest = new(types.UpdateApplicationRequest)
@@ -441,32 +629,1371 @@ func UpdateApplicationValidator(requestBody []byte) (*types.UpdateApplicationReq
 		return nil, fmt.Errorf("Invalid input")
 	}
 
-	if updateApplicationRequest.FileName != "" {
-		if !strings.HasSuffix(strings.ToLower(updateApplicationRequest.FileName), ".pdf") {
-			return nil, fmt.Errorf("Only PDF files are allowed")
+	if updateApplicationRequest.FileName != "" || updateApplicationRequest.FileBase64 != "" {
+		updateApplicationRequest.File = &types.AttachmentUpload{
+			FileName:   updateApplicationRequest.FileName,
+			FileBase64: updateApplicationRequest.FileBase64,
+		}
+		if err := validateAttachmentUpload(updateApplicationRequest.File); err != nil {
+			return nil, err
 		}
 	}
 
 	return updateApplicationRequest, nil
 }
 
-func ChangeApplicationStatusValidator(requestBody []byte) (*types.ChangeApplicationStatusRequest, error) {
+// UpdateApplicationFormValidator reads an update sent as multipart/form-data, a "file" part
+// replaces the attachments
+func UpdateApplicationFormValidator(form *multipart.Form) (*types.UpdateApplicationRequest, error) {
+	if form == nil {
+		return nil, fmt.Errorf("Invalid input")
+	}
 
-	var changeApplicationStatusRequest = new(types.ChangeApplicationStatusRequest)
+	updateApplicationRequest := &types.UpdateApplicationRequest{
+		Description: formValue(form, "description"),
+	}
 
-	err := json.Unmarshal(requestBody, &changeApplicationStatusRequest)
+	if files := formFiles(form, "file"); len(files) > 0 {
+		if len(files) > 1 {
+			return nil, fmt.Errorf("Only one file can replace the attachments")
+		}
+		updateApplicationRequest.File = &files[0]
+		if err := validateAttachmentUpload(updateApplicationRequest.File); err != nil {
+			return nil, err
+		}
+	}
+
+	return updateApplicationRequest, nil
+}
+
+func AttachmentUploadValidator(requestBody []byte) (*types.AttachmentUpload, error) {
+
+	var attachmentUpload = new(types.AttachmentUpload)
+
+	err := json.Unmarshal(requestBody, &attachmentUpload)
 	if err != nil {
 		fmt.Println("Error parsing request body:", err)
 		return nil, fmt.Errorf("Invalid input")
 	}
 
-	if changeApplicationStatusRequest.Status == "" {
-		return nil, fmt.Errorf("Missing required fields: status")
+	if err := validateAttachmentUpload(attachmentUpload); err != nil {
+		return nil, err
 	}
 
-	if changeApplicationStatusRequest.Status != "accepted" && changeApplicationStatusRequest.Status != "rejected" {
-		return nil, fmt.Errorf("Invalid status. Status must be either 'accepted' or 'rejected'")
+	return attachmentUpload, nil
+}
+
+// AttachmentFormValidator reads a file sent as the "file" part of a multipart/form-data request
+func AttachmentFormValidator(form *multipart.Form) (*types.AttachmentUpload, error) {
+	if form == nil {
+		return nil, fmt.Errorf("Invalid input")
 	}
 
-	return changeApplicationStatusRequest, nil
+	files := formFiles(form, "file")
+	if len(files) != 1 {
+		return nil, fmt.Errorf("Send exactly one file")
+	}
+	if err := validateAttachmentUpload(&files[0]); err != nil {
+		return nil, err
+	}
+
+	return &files[0], nil
+}
+
+func validateAttachmentUploads(uploads []types.AttachmentUpload) error {
+	if len(uploads) > 10 {
+		return fmt.Errorf("At most 10 files can be attached")
+	}
+
+	for i := range uploads {
+		if err := validateAttachmentUpload(&uploads[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateAttachmentUpload(upload *types.AttachmentUpload) error {
+	return validateFileUpload(upload, AttachmentFileNameValidator)
+}
+
+// validateFileUpload checks a file of a request with checkName for its sanitized name
+func validateFileUpload(upload *types.AttachmentUpload, checkName func(fileName string) error) error {
+	if upload.UploadID != 0 {
+		if upload.UploadID < 0 {
+			return fmt.Errorf("Upload ID must be a positive integer")
+		}
+		if upload.FileBase64 != "" {
+			return fmt.Errorf("Send either a file or an upload ID")
+		}
+		// the name is checked once the upload is loaded
+		return nil
+	}
+
+	if upload.Part == nil && upload.FileBase64 == "" {
+		return fmt.Errorf("File cannot be empty")
+	}
+
+	upload.FileName = SanitizeFileName(upload.FileName)
+	return checkName(upload.FileName)
+}
+
+// PortfolioFileNameValidator checks the name of a file of a portfolio item, a PDF or an image
+func PortfolioFileNameValidator(fileName string) error {
+	if fileName == "" {
+		return fmt.Errorf("File name cannot be empty")
+	}
+	if len(fileName) > 200 {
+		return fmt.Errorf("File name cannot be longer than 200 symbols")
+	}
+	if _, ok := types.ValidPortfolioFileTypes[strings.ToLower(path.Ext(fileName))]; !ok {
+		return fmt.Errorf("Only PDF, PNG, JPEG and GIF files are allowed")
+	}
+
+	return nil
+}
+
+// PortfolioFileUploadValidator reads a file of a portfolio item sent as JSON
+func PortfolioFileUploadValidator(requestBody []byte) (*types.AttachmentUpload, error) {
+
+	var attachmentUpload = new(types.AttachmentUpload)
+
+	err := json.Unmarshal(requestBody, &attachmentUpload)
+	if err != nil {
+		fmt.Println("Error parsing request body:", err)
+		return nil, fmt.Errorf("Invalid input")
+	}
+
+	if err := validateFileUpload(attachmentUpload, PortfolioFileNameValidator); err != nil {
+		return nil, err
+	}
+
+	return attachmentUpload, nil
+}
+
+// PortfolioFileFormValidator reads a file of a portfolio item sent as the "file" part of a multipart/form-data request
+func PortfolioFileFormValidator(form *multipart.Form) (*types.AttachmentUpload, error) {
+	if form == nil {
+		return nil, fmt.Errorf("Invalid input")
+	}
+
+	files := formFiles(form, "file")
+	if len(files) != 1 {
+		return nil, fmt.Errorf("Send exactly one file")
+	}
+	if err := validateFileUpload(&files[0], PortfolioFileNameValidator); err != nil {
+		return nil, err
+	}
+
+	return &files[0], nil
+}
+
+// FileContentValidator checks that the content of the file is what its extension says, the
+// name is already checked to be a PDF or an image
+func FileContentValidator(fileName string, file io.Reader) error {
+	contentType, ok := types.ValidPortfolioFileTypes[strings.ToLower(path.Ext(fileName))]
+	if !ok || contentType == "application/pdf" {
+		return PDFContentValidator(file)
+	}
+	return ImageContentValidator(contentType, file)
+}
+
+// maxImagePixels is the largest image accepted, larger ones would take too much memory to show
+const maxImagePixels = 50_000_000
+
+// ImageContentValidator checks the magic bytes of an image against contentType and decodes its header
+// for the dimensions
+func ImageContentValidator(contentType string, file io.Reader) error {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return fmt.Errorf("Failed to read file")
+	}
+	head = head[:n]
+
+	if http.DetectContentType(head) != contentType {
+		return fmt.Errorf("File content does not match its extension")
+	}
+
+	config, _, err := image.DecodeConfig(io.MultiReader(bytes.NewReader(head), file))
+	if err != nil {
+		return fmt.Errorf("File is not a valid image")
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxImagePixels {
+		return fmt.Errorf("Image dimensions are not supported")
+	}
+
+	return nil
+}
+
+// PortfolioItemValidator reads a portfolio item to create or replace
+func PortfolioItemValidator(requestBody []byte) (*types.PortfolioItemRequest, error) {
+
+	var portfolioItemRequest = new(types.PortfolioItemRequest)
+
+	err := json.Unmarshal(requestBody, &portfolioItemRequest)
+	if err != nil {
+		fmt.Println("Error parsing request body:", err)
+		return nil, fmt.Errorf("Invalid input")
+	}
+
+	portfolioItemRequest.Title = strings.TrimSpace(portfolioItemRequest.Title)
+	portfolioItemRequest.Role = strings.TrimSpace(portfolioItemRequest.Role)
+	portfolioItemRequest.ProjectURL = strings.TrimSpace(portfolioItemRequest.ProjectURL)
+
+	if portfolioItemRequest.Title == "" {
+		return nil, fmt.Errorf("Missing required fields: title")
+	}
+	if len(portfolioItemRequest.Title) > 100 {
+		return nil, fmt.Errorf("Title cannot be longer than 100 symbols")
+	}
+	if len(portfolioItemRequest.Description) > 5000 {
+		return nil, fmt.Errorf("Description cannot be longer than 5000 symbols")
+	}
+	if len(portfolioItemRequest.Role) > 100 {
+		return nil, fmt.Errorf("Role cannot be longer than 100 symbols")
+	}
+
+	if portfolioItemRequest.ProjectURL != "" {
+		if len(portfolioItemRequest.ProjectURL) > 255 {
+			return nil, fmt.Errorf("Project URL cannot be longer than 255 symbols")
+		}
+		projectURL, err := url.Parse(portfolioItemRequest.ProjectURL)
+		if err != nil || (projectURL.Scheme != "http" && projectURL.Scheme != "https") || projectURL.Host == "" {
+			return nil, fmt.Errorf("Project URL must be an http or https URL")
+		}
+	}
+
+	if len(portfolioItemRequest.SkillIDs) > 20 {
+		return nil, fmt.Errorf("At most 20 skills can be linked")
+	}
+
+	if portfolioItemRequest.StartDate != "" {
+		start, err := time.Parse("2006-01-02", portfolioItemRequest.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid start date, expected format YYYY-MM-DD")
+		}
+		portfolioItemRequest.Start = &start
+	}
+	if portfolioItemRequest.EndDate != "" {
+		end, err := time.Parse("2006-01-02", portfolioItemRequest.EndDate)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid end date, expected format YYYY-MM-DD")
+		}
+		if portfolioItemRequest.Start == nil {
+			return nil, fmt.Errorf("An end date needs a start date")
+		}
+		if end.Before(*portfolioItemRequest.Start) {
+			return nil, fmt.Errorf("End date cannot be before the start date")
+		}
+		portfolioItemRequest.End = &end
+	}
+
+	if len(portfolioItemRequest.Files) > 10 {
+		return nil, fmt.Errorf("At most 10 files can be attached")
+	}
+	for i := range portfolioItemRequest.Files {
+		if err := validateFileUpload(&portfolioItemRequest.Files[i], PortfolioFileNameValidator); err != nil {
+			return nil, err
+		}
+	}
+
+	return portfolioItemRequest, nil
+}
+
+func PortfolioOrderValidator(requestBody []byte) (*types.PortfolioOrderRequest, error) {
+
+	var portfolioOrderRequest = new(types.PortfolioOrderRequest)
+
+	err := json.Unmarshal(requestBody, &portfolioOrderRequest)
+	if err != nil {
+		fmt.Println("Error parsing request body:", err)
+		return nil, fmt.Errorf("Invalid input")
+	}
+
+	if len(portfolioOrderRequest.ItemIDs) == 0 {
+		return nil, fmt.Errorf("Missing required fields: item_ids")
+	}
+
+	return portfolioOrderRequest, nil
+}
+
+// SanitizeFileName keeps the base name of a file name sent by a client and replaces the characters
+// other than letters, digits, spaces and ._-() with underscores. Leading and trailing dots and spaces
+// are dropped, so "../../.env" becomes "env".
+func SanitizeFileName(fileName string) string {
+	fileName = path.Base(strings.ReplaceAll(fileName, "\\", "/"))
+
+	var sanitized strings.Builder
+	for _, r := range fileName {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune(" ._-()", r) {
+			sanitized.WriteRune(r)
+		} else {
+			sanitized.WriteRune('_')
+		}
+	}
+
+	return strings.Trim(sanitized.String(), " .")
+}
+
+// pdfTailSize is how far from the end the trailer of a PDF is looked for
+const pdfTailSize = 1024
+
+var (
+	pdfHeaderRegex  = regexp.MustCompile(`^%PDF-[12]\.[0-9]`)
+	pdfTrailerRegex = regexp.MustCompile(`startxref\s+([0-9]+)\s+%%EOF`)
+)
+
+// PDFContentValidator reads the file and checks that its content is a PDF whatever its name says:
+// the magic bytes, the version header and a trailer pointing to the cross-reference table inside the file
+func PDFContentValidator(file io.Reader) error {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return fmt.Errorf("Failed to read file")
+	}
+	head = head[:n]
+
+	if http.DetectContentType(head) != "application/pdf" {
+		return fmt.Errorf("File content is not a PDF")
+	}
+	if !pdfHeaderRegex.Match(head) {
+		return fmt.Errorf("File is not a valid PDF")
+	}
+
+	size := int64(len(head))
+	tail := append([]byte(nil), head...)
+	buf := make([]byte, 32<<10)
+	for {
+		n, err := file.Read(buf)
+		size += int64(n)
+		tail = append(tail, buf[:n]...)
+		if len(tail) > pdfTailSize {
+			tail = append(tail[:0], tail[len(tail)-pdfTailSize:]...)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to read file")
+		}
+	}
+
+	trailers := pdfTrailerRegex.FindAllSubmatch(tail, -1)
+	if len(trailers) == 0 {
+		return fmt.Errorf("File is not a valid PDF")
+	}
+	xrefOffset, err := strconv.ParseInt(string(trailers[len(trailers)-1][1]), 10, 64)
+	if err != nil || xrefOffset <= 0 || xrefOffset >= size {
+		return fmt.Errorf("File is not a valid PDF")
+	}
+
+	return nil
+}
+
+// AttachmentFileNameValidator checks the name of a file attached to an application or a job
+func AttachmentFileNameValidator(fileName string) error {
+	if fileName == "" {
+		return fmt.Errorf("File name cannot be empty")
+	}
+	if len(fileName) > 200 {
+		return fmt.Errorf("File name cannot be longer than 200 symbols")
+	}
+	if !strings.HasSuffix(strings.ToLower(fileName), ".pdf") {
+		return fmt.Errorf("Only PDF files are allowed")
+	}
+
+	return nil
+}
+
+// CreateUploadValidator reads the Upload-Length and Upload-Metadata headers that start a resumable upload.
+// The metadata is a comma separated list of keys with base64 encoded values, the file name is "filename".
+func CreateUploadValidator(length, metadata string) (*types.CreateUploadRequest, error) {
+	createUploadRequest := new(types.CreateUploadRequest)
+
+	var err error
+	createUploadRequest.Length, err = strconv.ParseInt(length, 10, 64)
+	if err != nil || createUploadRequest.Length <= 0 {
+		return nil, fmt.Errorf("Upload-Length must be a positive integer")
+	}
+
+	for _, pair := range strings.Split(metadata, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key != "filename" {
+			continue
+		}
+		fileName, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("Upload-Metadata values must be base64 encoded")
+		}
+		createUploadRequest.FileName = SanitizeFileName(string(fileName))
+	}
+
+	if createUploadRequest.FileName == "" {
+		return nil, fmt.Errorf("Missing required fields: filename")
+	}
+	if len(createUploadRequest.FileName) > 200 {
+		return nil, fmt.Errorf("File name cannot be longer than 200 symbols")
+	}
+
+	return createUploadRequest, nil
+}
+
+// UploadChunkValidator checks the headers of a chunk of a resumable upload and returns its offset
+func UploadChunkValidator(contentType, offset string) (int64, error) {
+	if contentType != "application/offset+octet-stream" {
+		return 0, fmt.Errorf("Content-Type must be application/offset+octet-stream")
+	}
+
+	uploadOffset, err := strconv.ParseInt(offset, 10, 64)
+	if err != nil || uploadOffset < 0 {
+		return 0, fmt.Errorf("Upload-Offset must be a non-negative integer")
+	}
+
+	return uploadOffset, nil
+}
+
+func formValue(form *multipart.Form, key string) string {
+	if values := form.Value[key]; len(values) > 0 {
+		return strings.TrimSpace(values[0])
+	}
+	return ""
+}
+
+func formFiles(form *multipart.Form, key string) []types.AttachmentUpload {
+	var files []types.AttachmentUpload
+	for _, part := range form.File[key] {
+		files = append(files, types.AttachmentUpload{FileName: part.Filename, Part: part})
+	}
+	return files
+}
+
+func ChangeApplicationStatusValidator(requestBody []byte) (*types.ChangeApplicationStatusRequest, error) {
+
+	var changeApplicationStatusRequest = new(types.ChangeApplicationStatusRequest)
+
+	err := json.Unmarshal(requestBody, &changeApplicationStatusRequest)
+	if err != nil {
+		fmt.Println("Error parsing request body:", err)
+		return nil, fmt.Errorf("Invalid input")
+	}
+
+	if changeApplicationStatusRequest.Status == "" {
+		return nil, fmt.Errorf("Missing required fields: status")
+	}
+
+	if !types.ValidApplicationStatuses[changeApplicationStatusRequest.Status] {
+		return nil, fmt.Errorf("Invalid status. Status must be 'pending', 'shortlisted', 'interviewing' or 'rejected'")
+	}
+	// The offer stages and acceptance follow the offers sent on the application
+	switch changeApplicationStatusRequest.Status {
+	case "offer-sent", "offer-declined", "accepted":
+		return nil, fmt.Errorf("Send the freelancer an offer, they are hired once the offer is accepted")
+	case "withdrawn":
+		return nil, fmt.Errorf("Only the freelancer can withdraw an application")
+	}
+
+	return changeApplicationStatusRequest, nil
+}
+
+func WithdrawApplicationValidator(requestBody []byte) (*types.WithdrawApplicationRequest, error) {
+
+	var withdrawApplicationRequest = new(types.WithdrawApplicationRequest)
+
+	// The reason is optional, the request may come without a body
+	if len(requestBody) > 0 {
+		err := json.Unmarshal(requestBody, &withdrawApplicationRequest)
+		if err != nil {
+			fmt.Println("Error parsing request body:", err)
+			return nil, fmt.Errorf("Invalid input")
+		}
+	}
+
+	withdrawApplicationRequest.Reason = strings.TrimSpace(withdrawApplicationRequest.Reason)
+	if len(withdrawApplicationRequest.Reason) > 1000 {
+		return nil, fmt.Errorf("Reason cannot be longer than 1000 symbols")
+	}
+
+	return withdrawApplicationRequest, nil
+}
+
+func ApplicationNotesValidator(requestBody []byte) (*types.ApplicationNotesRequest, error) {
+
+	var applicationNotesRequest = new(types.ApplicationNotesRequest)
+
+	err := json.Unmarshal(requestBody, &applicationNotesRequest)
+	if err != nil {
+		fmt.Println("Error parsing request body:", err)
+		return nil, fmt.Errorf("Invalid input")
+	}
+
+	applicationNotesRequest.Notes = strings.TrimSpace(applicationNotesRequest.Notes)
+	if len(applicationNotesRequest.Notes) > 5000 {
+		return nil, fmt.Errorf("Notes cannot be longer than 5000 symbols")
+	}
+
+	return applicationNotesRequest, nil
+}
+
+func OfferValidator(requestBody []byte) (*types.OfferTerms, error) {
+
+	var offerTerms = new(types.OfferTerms)
+
+	err := json.Unmarshal(requestBody, &offerTerms)
+	if err != nil {
+		fmt.Println("Error parsing request body:", err)
+		return nil, fmt.Errorf("Invalid input")
+	}
+	if offerTerms.Rate == "" {
+		return nil, fmt.Errorf("Missing required fields: rate")
+	} else if offerTerms.Amount == 0 {
+		return nil, fmt.Errorf("Missing required fields: amount")
+	}
+
+	if !types.ValidProjectRates[offerTerms.Rate] {
+		return nil, errors.New("invalid rate: must be 'hourly' or 'fixed'")
+	}
+	if offerTerms.Amount < 1 {
+		return nil, errors.New("amount cannot be less than 1")
+	}
+	if offerTerms.Rate == "hourly" && offerTerms.Amount > 1000 {
+		return nil, errors.New("amount cannot be more than 1000 if rate is hourly")
+	}
+
+	today := time.Now().Truncate(24 * time.Hour)
+	if offerTerms.StartDate != nil && offerTerms.StartDate.Before(today) {
+		return nil, errors.New("start date cannot be in the past")
+	}
+
+	offerTerms.Message = strings.TrimSpace(offerTerms.Message)
+	if len(offerTerms.Message) > 1000 {
+		return nil, fmt.Errorf("Message cannot be longer than 1000 symbols")
+	}
+
+	if len(offerTerms.Milestones) > 20 {
+		return nil, errors.New("an offer cannot have more than 20 milestones")
+	}
+	total := 0
+	for i := range offerTerms.Milestones {
+		milestone := &offerTerms.Milestones[i]
+		milestone.Title = strings.TrimSpace(milestone.Title)
+		if milestone.Title == "" {
+			return nil, fmt.Errorf("Missing required fields: milestone title")
+		}
+		if len(milestone.Title) > 100 {
+			return nil, fmt.Errorf("Milestone title cannot be longer than 100 symbols")
+		}
+		if milestone.Amount < 1 {
+			return nil, errors.New("milestone amount cannot be less than 1")
+		}
+		if milestone.DueDate != nil && offerTerms.StartDate != nil && milestone.DueDate.Before(*offerTerms.StartDate) {
+			return nil, errors.New("milestone due date cannot be before the start date")
+		}
+		total += milestone.Amount
+	}
+	if offerTerms.Rate == "fixed" && len(offerTerms.Milestones) > 0 && total != offerTerms.Amount {
+		return nil, errors.New("milestone amounts must add up to the amount of a fixed rate offer")
+	}
+
+	return offerTerms, nil
+}
+
+func CreateBanValidator(requestBody []byte) (*types.CreateBanRequest, error) {
+
+	var createBanRequest = new(types.CreateBanRequest)
+
+	err := json.Unmarshal(requestBody, &createBanRequest)
+	if err != nil {
+		fmt.Println("Error parsing request body:", err)
+		return nil, fmt.Errorf("Invalid input")
+	}
+
+	if createBanRequest.Reason == "" {
+		return nil, fmt.Errorf("Missing required fields: reason")
+	}
+	if len(createBanRequest.Reason) > 1000 {
+		return nil, fmt.Errorf("Reason cannot be longer than 1000 symbols")
+	}
+	if createBanRequest.EndsAt != nil && !createBanRequest.EndsAt.After(time.Now()) {
+		return nil, fmt.Errorf("Ban end time must be in the future")
+	}
+
+	return createBanRequest, nil
+}
+
+func SubmitBanAppealValidator(requestBody []byte) (*types.SubmitBanAppealRequest, error) {
+
+	var submitBanAppealRequest = new(types.SubmitBanAppealRequest)
+
+	err := json.Unmarshal(requestBody, &submitBanAppealRequest)
+	if err != nil {
+		fmt.Println("Error parsing request body:", err)
+		return nil, fmt.Errorf("Invalid input")
+	}
+
+	if submitBanAppealRequest.Message == "" {
+		return nil, fmt.Errorf("Missing required fields: message")
+	}
+	if len(submitBanAppealRequest.Message) > 1000 {
+		return nil, fmt.Errorf("Message cannot be longer than 1000 symbols")
+	}
+
+	return submitBanAppealRequest, nil
+}
+
+func ReviewBanAppealValidator(requestBody []byte) (*types.ReviewBanAppealRequest, error) {
+
+	var reviewBanAppealRequest = new(types.ReviewBanAppealRequest)
+
+	err := json.Unmarshal(requestBody, &reviewBanAppealRequest)
+	if err != nil {
+		fmt.Println("Error parsing request body:", err)
+		return nil, fmt.Errorf("Invalid input")
+	}
+
+	if reviewBanAppealRequest.Status == "" {
+		return nil, fmt.Errorf("Missing required fields: status")
+	}
+	if !types.ValidBanAppealReviewStatuses[reviewBanAppealRequest.Status] {
+		return nil, fmt.Errorf("Invalid status. Status must be either 'approved' or 'rejected'")
+	}
+	if len(reviewBanAppealRequest.Response) > 1000 {
+		return nil, fmt.Errorf("Response cannot be longer than 1000 symbols")
+	}
+
+	return reviewBanAppealRequest, nil
+}
+
+func CreateReportValidator(requestBody []byte) (*types.CreateReportRequest, error) {
+
+	var createReportRequest = new(types.CreateReportRequest)
+
+	err := json.Unmarshal(requestBody, &createReportRequest)
+	if err != nil {
+		fmt.Println("Error parsing request body:", err)
+		return nil, fmt.Errorf("Invalid input")
+	}
+
+	if createReportRequest.TargetType == "" {
+		return nil, fmt.Errorf("Missing required fields: target_type")
+	} else if createReportRequest.TargetID == 0 {
+		return nil, fmt.Errorf("Missing required fields: target_id")
+	} else if createReportRequest.Category == "" {
+		return nil, fmt.Errorf("Missing required fields: category")
+	}
+
+	if !types.ValidReportTargetTypes[createReportRequest.TargetType] {
+		return nil, errors.New("invalid target type: must be 'job', 'application' or 'user'")
+	}
+	if createReportRequest.TargetID < 0 {
+		return nil, fmt.Errorf("Target ID must be a positive integer")
+	}
+	if !types.ValidReportCategories[createReportRequest.Category] {
+		return nil, errors.New("invalid category: must be 'scam', 'spam', 'abuse', 'fake-profile', 'inappropriate' or 'other'")
+	}
+	if len(createReportRequest.Description) > 1000 {
+		return nil, fmt.Errorf("Description cannot be longer than 1000 symbols")
+	}
+
+	return createReportRequest, nil
+}
+
+func ResolveReportValidator(requestBody []byte) (*types.ResolveReportRequest, error) {
+
+	var resolveReportRequest = new(types.ResolveReportRequest)
+
+	err := json.Unmarshal(requestBody, &resolveReportRequest)
+	if err != nil {
+		fmt.Println("Error parsing request body:", err)
+		return nil, fmt.Errorf("Invalid input")
+	}
+
+	if resolveReportRequest.Status != "resolved" && resolveReportRequest.Status != "dismissed" {
+		return nil, fmt.Errorf("Invalid status. Status must be either 'resolved' or 'dismissed'")
+	}
+
+	if resolveReportRequest.Action == "" {
+		resolveReportRequest.Action = "none"
+	}
+	if !types.ValidReportActions[resolveReportRequest.Action] {
+		return nil, errors.New("invalid action: must be 'none', 'remove-job', 'remove-application' or 'ban-user'")
+	}
+	if resolveReportRequest.Status == "dismissed" && resolveReportRequest.Action != "none" {
+		return nil, fmt.Errorf("Dismissed reports cannot take an action")
+	}
+
+	if len(resolveReportRequest.Resolution) > 1000 {
+		return nil, fmt.Errorf("Resolution cannot be longer than 1000 symbols")
+	}
+	if resolveReportRequest.BanEndsAt != nil && !resolveReportRequest.BanEndsAt.After(time.Now()) {
+		return nil, fmt.Errorf("Ban end time must be in the future")
+	}
+
+	return resolveReportRequest, nil
+}
+
+func StatsQueryValidator(interval, from, to string, limit int) (*types.StatsQuery, error) {
+
+	var statsQuery = new(types.StatsQuery)
+
+	if interval == "" {
+		interval = "day"
+	}
+	if !types.ValidStatsIntervals[interval] {
+		return nil, errors.New("invalid interval: must be 'day', 'week' or 'month'")
+	}
+	statsQuery.Interval = interval
+
+	statsQuery.To = time.Now()
+	if to != "" {
+		parsedTo, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid 'to' date, expected format YYYY-MM-DD")
+		}
+		// include the whole 'to' day
+		statsQuery.To = parsedTo.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+
+	switch interval {
+	case "day":
+		statsQuery.From = statsQuery.To.AddDate(0, 0, -30)
+	case "week":
+		statsQuery.From = statsQuery.To.AddDate(0, 0, -7*12)
+	case "month":
+		statsQuery.From = statsQuery.To.AddDate(0, -12, 0)
+	}
+	if from != "" {
+		parsedFrom, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid 'from' date, expected format YYYY-MM-DD")
+		}
+		statsQuery.From = parsedFrom
+	}
+
+	if !statsQuery.From.Before(statsQuery.To) {
+		return nil, fmt.Errorf("'from' date must be before 'to' date")
+	}
+	if statsQuery.To.Sub(statsQuery.From) > 5*366*24*time.Hour {
+		return nil, fmt.Errorf("Date range cannot be longer than 5 years")
+	}
+	if interval == "day" && statsQuery.To.Sub(statsQuery.From) > 366*24*time.Hour {
+		return nil, fmt.Errorf("Daily statistics cannot cover more than a year")
+	}
+
+	if limit == 0 {
+		limit = 10
+	}
+	if limit < 1 || limit > 50 {
+		return nil, fmt.Errorf("Limit must be between 1 and 50")
+	}
+	statsQuery.Limit = limit
+
+	return statsQuery, nil
+}
+
+func SkillCategoryValidator(requestBody []byte) (*types.SkillCategoryRequest, error) {
+
+	var skillCategoryRequest = new(types.SkillCategoryRequest)
+
+	err := json.Unmarshal(requestBody, &skillCategoryRequest)
+	if err != nil {
+		fmt.Println("Error parsing request body:", err)
+		return nil, fmt.Errorf("Invalid input")
+	}
+
+	if skillCategoryRequest.Name == "" {
+		return nil, fmt.Errorf("Category name cannot be empty")
+	}
+	if len(skillCategoryRequest.Name) > 50 {
+		return nil, fmt.Errorf("Category name cannot be more than 50 symbols")
+	}
+	if skillCategoryRequest.ParentID < 0 {
+		return nil, fmt.Errorf("Parent ID must be a positive integer")
+	}
+
+	return skillCategoryRequest, nil
+}
+
+func SkillAliasValidator(requestBody []byte) (*types.SkillAliasRequest, error) {
+
+	var skillAliasRequest = new(types.SkillAliasRequest)
+
+	err := json.Unmarshal(requestBody, &skillAliasRequest)
+	if err != nil {
+		fmt.Println("Error parsing request body:", err)
+		return nil, fmt.Errorf("Invalid input")
+	}
+
+	skillAliasRequest.Alias = strings.TrimSpace(skillAliasRequest.Alias)
+	if skillAliasRequest.Alias == "" {
+		return nil, fmt.Errorf("Alias cannot be empty")
+	}
+	if len(skillAliasRequest.Alias) > 50 {
+		return nil, fmt.Errorf("Alias cannot be more than 50 symbols")
+	}
+
+	return skillAliasRequest, nil
+}
+
+func MergeSkillValidator(requestBody []byte) (*types.MergeSkillRequest, error) {
+
+	var mergeSkillRequest = new(types.MergeSkillRequest)
+
+	err := json.Unmarshal(requestBody, &mergeSkillRequest)
+	if err != nil {
+		fmt.Println("Error parsing request body:", err)
+		return nil, fmt.Errorf("Invalid input")
+	}
+
+	if mergeSkillRequest.TargetSkillID <= 0 {
+		return nil, fmt.Errorf("Target skill ID must be a positive integer")
+	}
+
+	return mergeSkillRequest, nil
+}
+
+func FreelancerSkillFilterValidator(skillID int, minLevel string, minYears int) (*types.FreelancerSkillFilter, error) {
+
+	var skillFilter = new(types.FreelancerSkillFilter)
+
+	if skillID < 0 {
+		return nil, errors.New("skill ID must be a positive integer")
+	}
+	if skillID == 0 && (minLevel != "" || minYears != 0) {
+		return nil, errors.New("min_level and min_years require a skill_id")
+	}
+	if minLevel == "" {
+		minLevel = "beginner"
+	}
+	if !types.ValidSkillLevels[minLevel] {
+		return nil, errors.New("invalid skill level: must be 'beginner', 'intermediate', 'advanced' or 'expert'")
+	}
+	if minYears < 0 {
+		return nil, errors.New("minimum years of experience cannot be negative")
+	}
+
+	skillFilter.SkillID = skillID
+	skillFilter.MinLevel = minLevel
+	skillFilter.MinYears = minYears
+
+	return skillFilter, nil
+}
+
+func JobSearchQueryValidator(skillIDs, rate, minAmount, maxAmount, keywords string) (*types.JobSearchFilter, error) {
+
+	var jobSearchFilter = new(types.JobSearchFilter)
+
+	for _, skillID := range strings.Split(skillIDs, ",") {
+		if strings.TrimSpace(skillID) == "" {
+			continue
+		}
+		id, err := strconv.Atoi(strings.TrimSpace(skillID))
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("Invalid skill ID: %s", skillID)
+		}
+		jobSearchFilter.SkillIDs = append(jobSearchFilter.SkillIDs, id)
+	}
+
+	if minAmount != "" {
+		amount, err := strconv.Atoi(minAmount)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid minimum amount")
+		}
+		jobSearchFilter.MinAmount = amount
+	}
+	if maxAmount != "" {
+		amount, err := strconv.Atoi(maxAmount)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid maximum amount")
+		}
+		jobSearchFilter.MaxAmount = amount
+	}
+
+	jobSearchFilter.Rate = rate
+	jobSearchFilter.Keywords = strings.TrimSpace(keywords)
+
+	if err := validateJobSearchFilter(jobSearchFilter); err != nil {
+		return nil, err
+	}
+
+	return jobSearchFilter, nil
+}
+
+func SavedSearchValidator(requestBody []byte) (*types.SavedSearchRequest, error) {
+
+	var savedSearchRequest = new(types.SavedSearchRequest)
+
+	err := json.Unmarshal(requestBody, &savedSearchRequest)
+	if err != nil {
+		fmt.Println("Error parsing request body:", err)
+		return nil, fmt.Errorf("Invalid input")
+	}
+
+	savedSearchRequest.Name = strings.TrimSpace(savedSearchRequest.Name)
+	savedSearchRequest.Keywords = strings.TrimSpace(savedSearchRequest.Keywords)
+
+	if savedSearchRequest.Name == "" {
+		return nil, fmt.Errorf("Missing required fields: name")
+	} else if savedSearchRequest.Frequency == "" {
+		return nil, fmt.Errorf("Missing required fields: frequency")
+	}
+
+	if len(savedSearchRequest.Name) > 50 {
+		return nil, fmt.Errorf("Name cannot be longer than 50 symbols")
+	}
+	// The name is used in the subject of alert emails
+	if strings.IndexFunc(savedSearchRequest.Name, unicode.IsControl) != -1 {
+		return nil, fmt.Errorf("Name cannot contain control characters")
+	}
+	if !types.ValidAlertFrequencies[savedSearchRequest.Frequency] {
+		return nil, errors.New("invalid frequency: must be 'instant', 'daily' or 'weekly'")
+	}
+	if savedSearchRequest.Channel == "" {
+		savedSearchRequest.Channel = "in-app"
+	}
+	if !types.ValidAlertChannels[savedSearchRequest.Channel] {
+		return nil, errors.New("invalid channel: must be 'in-app' or 'email'")
+	}
+
+	for _, skillID := range savedSearchRequest.SkillIDs {
+		if skillID <= 0 {
+			return nil, errors.New("invalid skill: id must be a positive integer")
+		}
+	}
+
+	err = validateJobSearchFilter(&types.JobSearchFilter{
+		SkillIDs:  savedSearchRequest.SkillIDs,
+		Rate:      savedSearchRequest.Rate,
+		MinAmount: savedSearchRequest.MinAmount,
+		MaxAmount: savedSearchRequest.MaxAmount,
+		Keywords:  savedSearchRequest.Keywords,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return savedSearchRequest, nil
+}
+
+func validateJobSearchFilter(filter *types.JobSearchFilter) error {
+	if filter.Rate != "" && !types.ValidProjectRates[filter.Rate] {
+		return errors.New("invalid project rate: must be 'hourly' or 'fixed'")
+	}
+	if filter.MinAmount < 0 || filter.MaxAmount < 0 {
+		return errors.New("amounts cannot be negative")
+	}
+	if filter.MaxAmount > 0 && filter.MinAmount > filter.MaxAmount {
+		return errors.New("minimum amount cannot be more than the maximum amount")
+	}
+	if len(filter.Keywords) > 255 {
+		return fmt.Errorf("Keywords cannot be longer than 255 symbols")
+	}
+	return nil
+}
+
+func GrantCreditsValidator(requestBody []byte) (*types.GrantCreditsRequest, error) {
+
+	var grantCreditsRequest = new(types.GrantCreditsRequest)
+
+	err := json.Unmarshal(requestBody, &grantCreditsRequest)
+	if err != nil {
+		fmt.Println("Error parsing request body:", err)
+		return nil, fmt.Errorf("Invalid input")
+	}
+	if grantCreditsRequest.Amount == 0 {
+		return nil, fmt.Errorf("Missing required fields: amount")
+	}
+
+	if grantCreditsRequest.Amount < 1 || grantCreditsRequest.Amount > 1000 {
+		return nil, errors.New("amount must be between 1 and 1000")
+	}
+
+	grantCreditsRequest.Note = strings.TrimSpace(grantCreditsRequest.Note)
+	if len(grantCreditsRequest.Note) > 255 {
+		return nil, fmt.Errorf("Note cannot be longer than 255 symbols")
+	}
+
+	return grantCreditsRequest, nil
+}
+
+func MarkNotificationsReadValidator(requestBody []byte) (*types.MarkNotificationsReadRequest, error) {
+
+	var markNotificationsReadRequest = new(types.MarkNotificationsReadRequest)
+
+	if len(requestBody) == 0 {
+		return markNotificationsReadRequest, nil
+	}
+
+	err := json.Unmarshal(requestBody, &markNotificationsReadRequest)
+	if err != nil {
+		fmt.Println("Error parsing request body:", err)
+		return nil, fmt.Errorf("Invalid input")
+	}
+
+	return markNotificationsReadRequest, nil
+}
+
+func JobBookmarkValidator(requestBody []byte) (*types.JobBookmarkRequest, error) {
+
+	var jobBookmarkRequest = new(types.JobBookmarkRequest)
+
+	err := json.Unmarshal(requestBody, &jobBookmarkRequest)
+	if err != nil {
+		fmt.Println("Error parsing request body:", err)
+		return nil, fmt.Errorf("Invalid input")
+	}
+
+	if jobBookmarkRequest.JobID == 0 {
+		return nil, fmt.Errorf("Missing required fields: job_id")
+	}
+	if jobBookmarkRequest.JobID < 0 {
+		return nil, errors.New("invalid job: id must be a positive integer")
+	}
+
+	return jobBookmarkRequest, nil
+}
+
+func TalentListValidator(requestBody []byte) (*types.TalentListRequest, error) {
+
+	var talentListRequest = new(types.TalentListRequest)
+
+	err := json.Unmarshal(requestBody, &talentListRequest)
+	if err != nil {
+		fmt.Println("Error parsing request body:", err)
+		return nil, fmt.Errorf("Invalid input")
+	}
+
+	talentListRequest.Name = strings.TrimSpace(talentListRequest.Name)
+
+	if talentListRequest.Name == "" {
+		return nil, fmt.Errorf("Missing required fields: name")
+	}
+	if len(talentListRequest.Name) > 50 {
+		return nil, fmt.Errorf("Name cannot be longer than 50 symbols")
+	}
+
+	return talentListRequest, nil
+}
+
+func TalentListEntryValidator(requestBody []byte) (*types.TalentListEntryRequest, error) {
+
+	var talentListEntryRequest = new(types.TalentListEntryRequest)
+
+	err := json.Unmarshal(requestBody, &talentListEntryRequest)
+	if err != nil {
+		fmt.Println("Error parsing request body:", err)
+		return nil, fmt.Errorf("Invalid input")
+	}
+
+	talentListEntryRequest.Note = strings.TrimSpace(talentListEntryRequest.Note)
+
+	if talentListEntryRequest.FreelancerID == 0 {
+		return nil, fmt.Errorf("Missing required fields: freelancer_id")
+	}
+	if talentListEntryRequest.FreelancerID < 0 {
+		return nil, errors.New("invalid freelancer: id must be a positive integer")
+	}
+	if len(talentListEntryRequest.Note) > 255 {
+		return nil, fmt.Errorf("Note cannot be longer than 255 symbols")
+	}
+
+	return talentListEntryRequest, nil
+}
+
+func JobInvitationValidator(requestBody []byte) (*types.JobInvitationRequest, error) {
+
+	var jobInvitationRequest = new(types.JobInvitationRequest)
+
+	err := json.Unmarshal(requestBody, &jobInvitationRequest)
+	if err != nil {
+		fmt.Println("Error parsing request body:", err)
+		return nil, fmt.Errorf("Invalid input")
+	}
+
+	jobInvitationRequest.Message = strings.TrimSpace(jobInvitationRequest.Message)
+
+	if jobInvitationRequest.FreelancerID == 0 {
+		return nil, fmt.Errorf("Missing required fields: freelancer_id")
+	}
+	if jobInvitationRequest.FreelancerID < 0 {
+		return nil, errors.New("invalid freelancer: id must be a positive integer")
+	}
+	if len(jobInvitationRequest.Message) > 1000 {
+		return nil, fmt.Errorf("Message cannot be longer than 1000 symbols")
+	}
+
+	return jobInvitationRequest, nil
+}
+
+func AcceptJobInvitationValidator(requestBody []byte) (*types.AcceptJobInvitationRequest, error) {
+
+	var acceptJobInvitationRequest = new(types.AcceptJobInvitationRequest)
+
+	if len(requestBody) == 0 {
+		return acceptJobInvitationRequest, nil
+	}
+
+	err := json.Unmarshal(requestBody, &acceptJobInvitationRequest)
+	if err != nil {
+		fmt.Println("Error parsing request body:", err)
+		return nil, fmt.Errorf("Invalid input")
+	}
+
+	acceptJobInvitationRequest.Description = strings.TrimSpace(acceptJobInvitationRequest.Description)
+
+	if err := validateScreeningAnswerList(acceptJobInvitationRequest.Answers); err != nil {
+		return nil, err
+	}
+
+	return acceptJobInvitationRequest, nil
+}
+
+func PublishJobValidator(requestBody []byte) (*types.PublishJobRequest, error) {
+
+	var publishJobRequest = new(types.PublishJobRequest)
+
+	if len(requestBody) == 0 {
+		return publishJobRequest, nil
+	}
+
+	err := json.Unmarshal(requestBody, &publishJobRequest)
+	if err != nil {
+		fmt.Println("Error parsing request body:", err)
+		return nil, fmt.Errorf("Invalid input")
+	}
+
+	if err := validateJobSchedule(publishJobRequest.PublishAt, publishJobRequest.ExpiresAt); err != nil {
+		return nil, err
+	}
+
+	return publishJobRequest, nil
+}
+
+func JobExpiryValidator(requestBody []byte) (*types.JobExpiryRequest, error) {
+
+	var jobExpiryRequest = new(types.JobExpiryRequest)
+
+	if len(requestBody) == 0 {
+		return jobExpiryRequest, nil
+	}
+
+	err := json.Unmarshal(requestBody, &jobExpiryRequest)
+	if err != nil {
+		fmt.Println("Error parsing request body:", err)
+		return nil, fmt.Errorf("Invalid input")
+	}
+
+	if err := validateJobSchedule(nil, jobExpiryRequest.ExpiresAt); err != nil {
+		return nil, err
+	}
+
+	return jobExpiryRequest, nil
+}
+
+func JobStatusChangeValidator(requestBody []byte) (*types.JobStatusChangeRequest, error) {
+
+	var jobStatusChangeRequest = new(types.JobStatusChangeRequest)
+
+	if len(requestBody) == 0 {
+		return jobStatusChangeRequest, nil
+	}
+
+	err := json.Unmarshal(requestBody, &jobStatusChangeRequest)
+	if err != nil {
+		fmt.Println("Error parsing request body:", err)
+		return nil, fmt.Errorf("Invalid input")
+	}
+
+	if len(jobStatusChangeRequest.Reason) > 1000 {
+		return nil, fmt.Errorf("Reason cannot be longer than 1000 symbols")
+	}
+	if jobStatusChangeRequest.FreelancerID < 0 {
+		return nil, errors.New("invalid freelancer: id must be a positive integer")
+	}
+
+	return jobStatusChangeRequest, nil
+}
+
+func validateExposedStages(stages []string) error {
+	seen := make(map[string]bool, len(stages))
+	for _, stage := range stages {
+		if !types.ValidExposedStages[stage] {
+			return fmt.Errorf("invalid exposed stage: %s. Must be 'shortlisted' or 'interviewing'", stage)
+		}
+		if seen[stage] {
+			return fmt.Errorf("duplicate exposed stage: %s", stage)
+		}
+		seen[stage] = true
+	}
+	return nil
+}
+
+func validateScreeningQuestions(questions []types.ScreeningQuestion) error {
+	if len(questions) > 10 {
+		return errors.New("a job cannot have more than 10 screening questions")
+	}
+
+	for i := range questions {
+		question := &questions[i]
+		question.Question = strings.TrimSpace(question.Question)
+
+		if question.Question == "" {
+			return fmt.Errorf("Missing required fields: screening_questions[%d].question", i)
+		}
+		if len(question.Question) > 255 {
+			return fmt.Errorf("Screening question cannot be longer than 255 symbols")
+		}
+		if !types.ValidScreeningQuestionTypes[question.Type] {
+			return errors.New("invalid screening question type: must be 'text', 'yes-no', 'multiple-choice' or 'number'")
+		}
+
+		if question.Type != "multiple-choice" {
+			if len(question.Options) > 0 {
+				return errors.New("only multiple-choice screening questions can have options")
+			}
+			continue
+		}
+
+		if len(question.Options) < 2 || len(question.Options) > 10 {
+			return errors.New("multiple-choice screening questions must have between 2 and 10 options")
+		}
+		seen := make(map[string]bool, len(question.Options))
+		for j, option := range question.Options {
+			option = strings.TrimSpace(option)
+			if option == "" || len(option) > 100 {
+				return errors.New("screening question options must be between 1 and 100 symbols")
+			}
+			if seen[strings.ToLower(option)] {
+				return fmt.Errorf("duplicate screening question option: %s", option)
+			}
+			seen[strings.ToLower(option)] = true
+			question.Options[j] = option
+		}
+	}
+
+	return nil
+}
+
+// validateScreeningAnswerList checks the shape of the answers, ScreeningAnswersValidator checks them against the questions
+func validateScreeningAnswerList(answers []types.ScreeningAnswer) error {
+	seen := make(map[int]bool, len(answers))
+	for i := range answers {
+		answer := &answers[i]
+		answer.Answer = strings.TrimSpace(answer.Answer)
+
+		if answer.QuestionID <= 0 {
+			return errors.New("invalid screening answer: question_id must be a positive integer")
+		}
+		if seen[answer.QuestionID] {
+			return fmt.Errorf("Question %d is answered more than once", answer.QuestionID)
+		}
+		seen[answer.QuestionID] = true
+
+		if len(answer.Answer) > 1000 {
+			return fmt.Errorf("Answer cannot be longer than 1000 symbols")
+		}
+	}
+
+	return nil
+}
+
+// ScreeningAnswersValidator checks the answers of an application against the job's screening questions
+// and normalises yes-no and multiple-choice answers to their canonical form
+func ScreeningAnswersValidator(questions []types.ScreeningQuestion, answers []types.ScreeningAnswer) error {
+	questionsByID := make(map[int]types.ScreeningQuestion, len(questions))
+	for _, question := range questions {
+		questionsByID[question.ID] = question
+	}
+
+	answered := make(map[int]bool, len(answers))
+	for i := range answers {
+		answer := &answers[i]
+		question, ok := questionsByID[answer.QuestionID]
+		if !ok {
+			return fmt.Errorf("Question %d is not a screening question of this job", answer.QuestionID)
+		}
+		if answer.Answer == "" {
+			continue
+		}
+		answered[question.ID] = true
+
+		switch question.Type {
+		case "yes-no":
+			answer.Answer = strings.ToLower(answer.Answer)
+			if answer.Answer != "yes" && answer.Answer != "no" {
+				return fmt.Errorf("Answer to \"%s\" must be 'yes' or 'no'", question.Question)
+			}
+		case "multiple-choice":
+			valid := false
+			for _, option := range question.Options {
+				if strings.EqualFold(option, answer.Answer) {
+					answer.Answer = option
+					valid = true
+					break
+				}
+			}
+			if !valid {
+				return fmt.Errorf("Answer to \"%s\" must be one of: %s", question.Question, strings.Join(question.Options, ", "))
+			}
+		case "number":
+			if _, err := strconv.ParseFloat(answer.Answer, 64); err != nil {
+				return fmt.Errorf("Answer to \"%s\" must be a number", question.Question)
+			}
+		}
+	}
+
+	for _, question := range questions {
+		if question.Required && !answered[question.ID] {
+			return fmt.Errorf("Missing answer to required question: %s", question.Question)
+		}
+	}
+
+	return nil
+}
+
+// ScreeningAnswerFilterValidator reads the answer_<question id> parameters of the query, and answer_<question id>_min
+// and answer_<question id>_max for number questions
+func ScreeningAnswerFilterValidator(query url.Values, questions []types.ScreeningQuestion) ([]types.ScreeningAnswerFilter, error) {
+	var filters []types.ScreeningAnswerFilter
+
+	for _, question := range questions {
+		prefix := "answer_" + strconv.Itoa(question.ID)
+		filter := types.ScreeningAnswerFilter{QuestionID: question.ID, Value: strings.TrimSpace(query.Get(prefix))}
+
+		for _, bound := range []string{"min", "max"} {
+			value := query.Get(prefix + "_" + bound)
+			if value == "" {
+				continue
+			}
+			if question.Type != "number" {
+				return nil, fmt.Errorf("Only number questions can be filtered by %s", bound)
+			}
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid %s for question %d", bound, question.ID)
+			}
+			if bound == "min" {
+				filter.Min = &number
+			} else {
+				filter.Max = &number
+			}
+		}
+
+		if filter.Value != "" || filter.Min != nil || filter.Max != nil {
+			filters = append(filters, filter)
+		}
+	}
+
+	return filters, nil
+}
+
+// ApplicationKeywordsValidator splits the ?q= keywords that applications are searched by into lower case words
+func ApplicationKeywordsValidator(keywords string) ([]string, error) {
+	keywords = strings.TrimSpace(keywords)
+	if len(keywords) > 255 {
+		return nil, fmt.Errorf("Keywords cannot be longer than 255 symbols")
+	}
+
+	return strings.Fields(strings.ToLower(keywords)), nil
 }
@@ -0,0 +1,148 @@
+package validators
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// minimalPDF returns a small PDF whose trailer points to its cross-reference table
+func minimalPDF() []byte {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	b.WriteString("1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n")
+	b.WriteString("2 0 obj\n<< /Type /Pages /Kids [] /Count 0 >>\nendobj\n")
+	xref := b.Len()
+	b.WriteString("xref\n0 3\n0000000000 65535 f \ntrailer\n<< /Size 3 /Root 1 0 R >>\n")
+	fmt.Fprintf(&b, "startxref\n%d\n%%%%EOF\n", xref)
+	return b.Bytes()
+}
+
+func TestPDFContentValidator(t *testing.T) {
+	pdf := minimalPDF()
+	withoutTrailer := bytes.Replace(pdf, []byte("startxref"), []byte("xrefstart"), 1)
+	outsideOffset := bytes.Replace(pdf, []byte(fmt.Sprintf("startxref\n%d", bytes.Index(pdf, []byte("xref\n0 3")))), []byte("startxref\n99999999"), 1)
+
+	// Padding between the objects and the trailer, so that the trailer is read from the end of a large file
+	padded := append([]byte(nil), pdf[:bytes.Index(pdf, []byte("xref\n"))]...)
+	padded = append(padded, []byte("% "+strings.Repeat("x", 100<<10)+"\n")...)
+	xref := len(padded)
+	padded = append(padded, []byte(fmt.Sprintf("xref\n0 3\ntrailer\n<< /Size 3 >>\nstartxref\n%d\n%%%%EOF\n", xref))...)
+
+	// A trailer that only appears far from the end does not count
+	trailerFarFromEnd := append(append([]byte(nil), pdf...), []byte("% "+strings.Repeat("y", 2048)+"\n")...)
+
+	tests := []struct {
+		name    string
+		content []byte
+		ok      bool
+	}{
+		{"valid PDF", pdf, true},
+		{"large PDF", padded, true},
+		{"trailing whitespace", append(append([]byte(nil), pdf...), "\r\n\r\n"...), true},
+		{"empty file", nil, false},
+		{"text renamed to .pdf", []byte("just some notes, not a document"), false},
+		{"PNG renamed to .pdf", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), false},
+		{"ZIP renamed to .pdf", []byte("PK\x03\x04\x14\x00\x00\x00\x08\x00"), false},
+		{"HTML renamed to .pdf", []byte("<html><body>%PDF-1.4</body></html>"), false},
+		{"unknown PDF version", append([]byte("%PDF-9.9"), pdf[8:]...), false},
+		{"missing startxref", withoutTrailer, false},
+		{"header only", []byte("%PDF-1.4\n"), false},
+		{"startxref past the end", outsideOffset, false},
+		{"startxref far from the end", trailerFarFromEnd, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := PDFContentValidator(bytes.NewReader(tt.content))
+			if tt.ok && err != nil {
+				t.Errorf("PDFContentValidator = %v, want valid", err)
+			}
+			if !tt.ok && err == nil {
+				t.Error("PDFContentValidator accepted the file")
+			}
+		})
+	}
+}
+
+func TestSanitizeFileName(t *testing.T) {
+	tests := []struct {
+		fileName string
+		want     string
+	}{
+		{"cv.pdf", "cv.pdf"},
+		{"My CV (2024)_final-v2.pdf", "My CV (2024)_final-v2.pdf"},
+		{"Życiorys.pdf", "Życiorys.pdf"},
+		{"../../.env", "env"},
+		{"../../etc/passwd", "passwd"},
+		{"/var/www/cv.pdf", "cv.pdf"},
+		{`a\b.pdf`, "b.pdf"},
+		{`C:\Users\me\cv.pdf`, "cv.pdf"},
+		{"..", ""},
+		{"", ""},
+		{" cv.pdf. ", "cv.pdf"},
+		{"cv\x00.pdf", "cv_.pdf"},
+		{"cv\r\n.pdf", "cv__.pdf"},
+		{"<script>.pdf", "_script_.pdf"},
+		{"a;rm -rf.pdf", "a_rm -rf.pdf"},
+	}
+
+	for _, tt := range tests {
+		if got := SanitizeFileName(tt.fileName); got != tt.want {
+			t.Errorf("SanitizeFileName(%q) = %q, want %q", tt.fileName, got, tt.want)
+		}
+	}
+}
+
+func TestAttachmentFileNameValidatorAfterSanitizing(t *testing.T) {
+	tests := []struct {
+		fileName string
+		ok       bool
+	}{
+		{"cv.pdf", true},
+		{"CV.PDF", true},
+		{"../../.env", false},
+		{`a\b.pdf`, true},
+		{"cv.pdf.exe", false},
+		{"..", false},
+		{strings.Repeat("a", 197) + ".pdf", false},
+	}
+
+	for _, tt := range tests {
+		err := AttachmentFileNameValidator(SanitizeFileName(tt.fileName))
+		if tt.ok && err != nil {
+			t.Errorf("AttachmentFileNameValidator(%q) = %v, want valid", tt.fileName, err)
+		}
+		if !tt.ok && err == nil {
+			t.Errorf("AttachmentFileNameValidator(%q) accepted the name", tt.fileName)
+		}
+	}
+}
+
+func TestSavedSearchValidatorName(t *testing.T) {
+	tests := []struct {
+		name string
+		ok   bool
+	}{
+		{"Go jobs", true},
+		{"Développeur Go – remote", true},
+		{"Go jobs\r\nBcc: victim@example.com", false},
+		{"Go\njobs", false},
+		{"Go\tjobs", false},
+		{"Go\x00jobs", false},
+		{"Go\u0085jobs", false},
+	}
+
+	for _, tt := range tests {
+		body, _ := json.Marshal(map[string]string{"name": tt.name, "frequency": "daily", "channel": "email"})
+		_, err := SavedSearchValidator(body)
+		if tt.ok && err != nil {
+			t.Errorf("SavedSearchValidator(%q) = %v, want valid", tt.name, err)
+		}
+		if !tt.ok && err == nil {
+			t.Errorf("SavedSearchValidator(%q) accepted the name", tt.name)
+		}
+	}
+}